@@ -138,7 +138,7 @@ func channelHandler(cl ari.Client, h *ari.ChannelHandle) {
 
 			log.Info("New Channel State", "state", data.State)
 
-			if data.State == "Up" {
+			if data.IsAnswered() {
 				return
 			}
 		}
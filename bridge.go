@@ -36,7 +36,10 @@ type Bridge interface {
 	// StopMOH stops music on hold
 	StopMOH(key *Key) error
 
-	// Play plays the media URI to the bridge
+	// Play plays the media URI to the bridge.  The media URIs are plain
+	// strings, as they are on the other implementations of this interface and
+	// of Player; BridgeHandle.PlayMedia takes typed MediaURIs and validates
+	// them before it calls Play.
 	Play(key *Key, playbackID string, mediaURI ...string) (*PlaybackHandle, error)
 
 	// StagePlay stages a `Play` operation and returns the `PlaybackHandle`
@@ -66,13 +69,16 @@ type BridgeData struct {
 	// Key is the cluster-unique identifier for this bridge
 	Key *Key `json:"key"`
 
-	ID         string   `json:"id"`           // Unique Id for this bridge
-	Class      string   `json:"bridge_class"` // Class of the bridge
-	Type       string   `json:"bridge_type"`  // Type of bridge (mixing, holding, dtmf_events, proxy_media)
-	ChannelIDs []string `json:"channels"`     // List of pariticipating channel ids
-	Creator    string   `json:"creator"`      // Creating entity of the bridge
-	Name       string   `json:"name"`         // The name of the bridge
-	Technology string   `json:"technology"`   // Name of the bridging technology
+	ID            string   `json:"id"`                        // Unique Id for this bridge
+	Class         string   `json:"bridge_class"`              // Class of the bridge
+	Type          string   `json:"bridge_type"`               // Type of bridge (mixing, holding, dtmf_events, proxy_media)
+	ChannelIDs    []string `json:"channels"`                  // List of pariticipating channel ids
+	Creator       string   `json:"creator"`                   // Creating entity of the bridge
+	Creationtime  DateTime `json:"creationtime,omitzero"`     // Time at which the bridge was created
+	Name          string   `json:"name"`                      // The name of the bridge
+	Technology    string   `json:"technology"`                // Name of the bridging technology
+	VideoMode     string   `json:"video_mode,omitempty"`      // The video mode the bridge uses (none, talker, sfu, single)
+	VideoSourceID string   `json:"video_source_id,omitempty"` // The ID of the channel that is the source of video in this bridge, if one exists
 }
 
 // BridgeAddChannelOptions describes additional options to be applied to a channel when it is joined to a bridge
@@ -180,11 +186,32 @@ func (bh *BridgeHandle) Play(id string, mediaURI ...string) (*PlaybackHandle, er
 	return bh.b.Play(bh.key, id, mediaURI...)
 }
 
+// PlayMedia initiates playback of the given typed media URIs, which are
+// validated before they are sent
+func (bh *BridgeHandle) PlayMedia(id string, uri ...MediaURI) (*PlaybackHandle, error) {
+	list, err := MediaURIs(uri...)
+	if err != nil {
+		return nil, err
+	}
+
+	return bh.b.Play(bh.key, id, list...)
+}
+
 // StagePlay stages a `Play` operation.
 func (bh *BridgeHandle) StagePlay(id string, mediaURI ...string) (*PlaybackHandle, error) {
 	return bh.b.StagePlay(bh.key, id, mediaURI...)
 }
 
+// StagePlayMedia stages a `PlayMedia` operation
+func (bh *BridgeHandle) StagePlayMedia(id string, uri ...MediaURI) (*PlaybackHandle, error) {
+	list, err := MediaURIs(uri...)
+	if err != nil {
+		return nil, err
+	}
+
+	return bh.b.StagePlay(bh.key, id, list...)
+}
+
 // Record records the bridge to the given filename
 func (bh *BridgeHandle) Record(name string, opts *RecordingOptions) (*LiveRecordingHandle, error) {
 	return bh.b.Record(bh.key, name, opts)
@@ -1,20 +1,195 @@
 package ari
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode"
+)
 
-// NOTE: Direct translation from ARI client 2.0
+// callerIDNumberChars are the characters of which a bare (unbracketed) number may consist
+const callerIDNumberChars = "0123456789*#+"
+
+// callerIDPunctuation are the characters which commonly separate the parts
+// of a written phone number (ex. "+1 (800) 555-8282"), and which are dropped
+// from a bare number
+const callerIDPunctuation = " ()-."
 
 // CallerIDFromString interprets the provided string
 // as a CallerID.  Usually, this string will be of the following forms:
 //   - "Name" <number>
 //   - <number>
 //   - "Name" number
+//   - Name <number>
+//   - number
+//   - Name
+//
+// Quoted names may contain backslash-escaped quotes and backslashes.
+// Unquoted names may contain spaces.  An unbracketed value which consists
+// only of digits, '*', '#' and '+', once any phone punctuation (spaces,
+// parentheses, dashes and dots) is dropped, is taken as a number; any other
+// is taken as a name.  The empty string yields an empty CallerID.
 func CallerIDFromString(src string) (*CallerID, error) {
-	// TODO: implement complete callerid parser
-	return nil, errors.New("CallerIDFromString not yet implemented")
+	s := strings.TrimSpace(src)
+
+	cid := new(CallerID)
+
+	switch {
+	case s == "":
+		return cid, nil
+	case s[0] == '"':
+		name, rest, err := unquoteCallerIDName(s)
+		if err != nil {
+			return nil, err
+		}
+
+		cid.Name = name
+
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return cid, nil
+		}
+
+		if rest[0] == '<' {
+			cid.Number, err = bracketedCallerIDNumber(rest)
+			return cid, err
+		}
+
+		number, ok := bareCallerIDNumber(rest)
+		if !ok || strings.ContainsAny(rest, " \t") {
+			return nil, errors.New("invalid caller ID number: " + rest)
+		}
+
+		cid.Number = number
+
+		return cid, nil
+	case strings.Contains(s, "<"):
+		idx := strings.Index(s, "<")
+
+		var err error
+
+		cid.Name = strings.TrimSpace(s[:idx])
+		cid.Number, err = bracketedCallerIDNumber(s[idx:])
+
+		return cid, err
+	default:
+		if number, ok := bareCallerIDNumber(s); ok {
+			cid.Number = number
+		} else {
+			cid.Name = s
+		}
+	}
+
+	return cid, nil
+}
+
+// unquoteCallerIDName reads the quoted name at the start of s, returning the
+// unescaped name and the remainder of s
+func unquoteCallerIDName(s string) (name string, rest string, err error) {
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", "", errors.New("unterminated escape in caller ID name")
+			}
+
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", "", errors.New("unterminated quote in caller ID name")
+}
+
+// bracketedCallerIDNumber returns the number of the bracketed form `<number>`
+func bracketedCallerIDNumber(s string) (string, error) {
+	if !strings.HasSuffix(s, ">") {
+		return "", errors.New("unterminated bracket in caller ID number")
+	}
+
+	number := s[1 : len(s)-1]
+	if strings.ContainsAny(number, "<>") {
+		return "", errors.New("invalid caller ID number: " + number)
+	}
+
+	return strings.TrimSpace(number), nil
+}
+
+// bareCallerIDNumber returns the number written by s, without its phone
+// punctuation, if s is a number
+func bareCallerIDNumber(s string) (string, bool) {
+	var b strings.Builder
+
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune(callerIDNumberChars, r):
+			b.WriteRune(r)
+		case strings.ContainsRune(callerIDPunctuation, r):
+		default:
+			return "", false
+		}
+	}
+
+	return b.String(), b.Len() > 0
 }
 
-// String returns the stringified callerid
+// String returns the stringified callerid, in the form `"Name" <number>`.
+// The result is safe to pass to Asterisk (as, for instance, the CallerID of
+// an OriginateRequest):  quotes and backslashes in the name are escaped and
+// control characters are removed, and characters which may not appear in a
+// number (brackets, quotes, whitespace, and control characters) are removed
+// from the number.  Empty components are omitted.
 func (cid *CallerID) String() string {
-	return cid.Name + "<" + cid.Number + ">"
+	if cid == nil {
+		return ""
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, cid.Name)
+
+	number := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) || strings.ContainsRune(`<>"`, r) {
+			return -1
+		}
+
+		return r
+	}, cid.Number)
+
+	var parts []string
+
+	if name != "" {
+		parts = append(parts, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name)+`"`)
+	}
+
+	if number != "" {
+		parts = append(parts, "<"+number+">")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// callerIDJSON is the JSON form of CallerID, which (unlike the
+// protobuf-generated tags) always includes both fields, as Asterisk does.
+type callerIDJSON struct {
+	Name   string `json:"name"`
+	Number string `json:"number"`
+}
+
+// MarshalJSON encodes the CallerID to JSON
+func (cid *CallerID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&callerIDJSON{
+		Name:   cid.Name,
+		Number: cid.Number,
+	})
 }
@@ -0,0 +1,160 @@
+package ari
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode"
+)
+
+var callerIDParseTests = []struct {
+	Input  string
+	Name   string
+	Number string
+}{
+	{`"Jane" <100>`, "Jane", "100"},
+	{`<102>`, "", "102"},
+	{`8005558282`, "", "8005558282"},
+	{`"Jane Doe" 100`, "Jane Doe", "100"},
+	{`Jane Doe <100>`, "Jane Doe", "100"},
+	{`Jane Doe`, "Jane Doe", ""},
+	{`"Jane \"JD\" Doe" <100>`, `Jane "JD" Doe`, "100"},
+	{`"back\\slash" <100>`, `back\slash`, "100"},
+	{`"<not a number>"`, "<not a number>", ""},
+	{`"Jane" <>`, "Jane", ""},
+	{`  "Jane"   < 100 >  `, "Jane", "100"},
+	{`<alice@example.com>`, "", "alice@example.com"},
+	{`+1 (800) 555-8282`, "", "+18005558282"},
+	{`800.555.8282`, "", "8005558282"},
+	{`"Jane" 555-0100`, "Jane", "5550100"},
+	{`(Jane)`, "(Jane)", ""},
+	{`()`, "()", ""},
+	{`*97#`, "", "*97#"},
+	{``, "", ""},
+}
+
+func TestCallerIDFromString(t *testing.T) {
+	for _, tc := range callerIDParseTests {
+		cid, err := CallerIDFromString(tc.Input)
+		if err != nil {
+			t.Errorf("Unexpected error parsing '%s': %v", tc.Input, err)
+			continue
+		}
+
+		if cid.Name != tc.Name || cid.Number != tc.Number {
+			t.Errorf("Parsing '%s': expected name '%s' and number '%s', got name '%s' and number '%s'", tc.Input, tc.Name, tc.Number, cid.Name, cid.Number)
+		}
+	}
+}
+
+func TestCallerIDFromStringInvalid(t *testing.T) {
+	for _, s := range []string{
+		`"Jane <100>`,
+		`"Jane\`,
+		`Jane <100`,
+		`Jane <100> extra`,
+		`<<100>>`,
+		`"Jane" 100 200`,
+	} {
+		if _, err := CallerIDFromString(s); err == nil {
+			t.Errorf("Expected error parsing '%s'", s)
+		}
+	}
+}
+
+func TestCallerIDString(t *testing.T) {
+	for _, tc := range []struct {
+		CID      *CallerID
+		Expected string
+	}{
+		{&CallerID{Name: "Jane", Number: "100"}, `"Jane" <100>`},
+		{&CallerID{Number: "100"}, `<100>`},
+		{&CallerID{Name: "Jane"}, `"Jane"`},
+		{&CallerID{Name: `Jane "JD" \ Doe`, Number: "100"}, `"Jane \"JD\" \\ Doe" <100>`},
+		{&CallerID{Name: "Jane\r\nVia: evil", Number: "1 0<0>"}, `"JaneVia: evil" <100>`},
+		{&CallerID{}, ``},
+		{nil, ``},
+	} {
+		if s := tc.CID.String(); s != tc.Expected {
+			t.Errorf("Expected '%s', got '%s'", tc.Expected, s)
+		}
+	}
+}
+
+// callerIDValue is a random CallerID whose components are representable:
+// the name has no control characters, and the number has no characters
+// which are removed by the formatter.
+type callerIDValue struct {
+	Name   string
+	Number string
+}
+
+// Generate implements quick.Generator
+func (callerIDValue) Generate(r *rand.Rand, size int) reflect.Value {
+	nameChars := []rune(`abcXYZ 019"\<>'*#+-_().,;:éü名`)
+	numberChars := []rune(`0123456789*#+-.@abcXYZ`)
+
+	gen := func(chars []rune, valid func(rune) bool) string {
+		var b strings.Builder
+
+		for n := r.Intn(size + 1); n > 0; n-- {
+			c := chars[r.Intn(len(chars))]
+			if r.Intn(8) == 0 {
+				c = rune(r.Intn(0x3000))
+			}
+
+			if valid(c) {
+				b.WriteRune(c)
+			}
+		}
+
+		return b.String()
+	}
+
+	return reflect.ValueOf(callerIDValue{
+		Name: gen(nameChars, func(c rune) bool {
+			return !unicode.IsControl(c)
+		}),
+		Number: gen(numberChars, func(c rune) bool {
+			return !unicode.IsControl(c) && !unicode.IsSpace(c) && !strings.ContainsRune(`<>"`, c)
+		}),
+	})
+}
+
+func TestCallerIDRoundTrip(t *testing.T) {
+	f := func(v callerIDValue) bool {
+		s := (&CallerID{Name: v.Name, Number: v.Number}).String()
+
+		cid, err := CallerIDFromString(s)
+		if err != nil {
+			t.Logf("failed to parse '%s': %v", s, err)
+			return false
+		}
+
+		return cid.Name == v.Name && cid.Number == v.Number
+	}
+
+	if err := quick.Check(f, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCallerIDFormatIdempotent(t *testing.T) {
+	f := func(name, number string) bool {
+		s := (&CallerID{Name: name, Number: number}).String()
+
+		cid, err := CallerIDFromString(s)
+		if err != nil {
+			t.Logf("failed to parse '%s': %v", s, err)
+			return false
+		}
+
+		return cid.String() == s
+	}
+
+	if err := quick.Check(f, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,9 +2,10 @@ package ari
 
 import (
 	"encoding/json"
-	"strings"
+	"net"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	ptypes "github.com/gogo/protobuf/types"
 	"github.com/rotisserie/eris"
 )
@@ -56,7 +57,9 @@ type Channel interface {
 	// Answer answers the channel
 	Answer(key *Key) error
 
-	// Hangup hangs up the given channel
+	// Hangup hangs up the given channel with the given reason (see
+	// HangupReason).  If no reason is given, the channel is hung up with
+	// HangupNormal.
 	Hangup(key *Key, reason string) error
 
 	// Ring indicates ringing to the channel
@@ -95,7 +98,10 @@ type Channel interface {
 	// StopSilence stops the silence on the channel
 	StopSilence(key *Key) error
 
-	// Play plays the media URI to the channel
+	// Play plays the media URI to the channel.  The media URIs are plain
+	// strings, as they are on the other implementations of this interface and
+	// of Player; ChannelHandle.PlayMedia takes typed MediaURIs and validates
+	// them before it calls Play.
 	Play(key *Key, playbackID string, mediaURI ...string) (*PlaybackHandle, error)
 
 	// StagePlay stages a `Play` operation and returns the `PlaybackHandle`
@@ -144,22 +150,98 @@ type channelDataJSON struct {
 	Name         string            `json:"name"`  // Name of this channel (tech/name-id format)
 	State        string            `json:"state"` // State of the channel
 	Accountcode  string            `json:"accountcode"`
-	Caller       *CallerID         `json:"caller"`    // CallerId of the calling endpoint
-	Connected    *CallerID         `json:"connected"` // CallerId of the connected line
+	Caller       *CallerID         `json:"caller,omitempty"`    // CallerId of the calling endpoint
+	Connected    *CallerID         `json:"connected,omitempty"` // CallerId of the connected line
 	Creationtime DateTime          `json:"creationtime"`
-	Dialplan     *DialplanCEP      `json:"dialplan"` // Current location in the dialplan
-	Language     string            `json:"language"` // Language for the channel
-	ChannelVars  map[string]string `json:"channelvars"`
+	Dialplan     *DialplanCEP      `json:"dialplan,omitempty"` // Current location in the dialplan
+	Language     string            `json:"language"`           // Language for the channel
+	ChannelVars  map[string]string `json:"channelvars,omitzero"`
 	ProtocolId   string            `json:"protocol_id"` // Protocol identifier from the underlying channel technology (SIP header Call-ID value for chan_sip and PJSIP) (since Asterisk 12)
 }
 
-// MarshalJSON encodes ChannelData to JSON
+// dialplanCEPJSON is the JSON form of DialplanCEP, which (unlike the
+// protobuf-generated tags) always includes every field, as Asterisk does.
+type dialplanCEPJSON struct {
+	Context  string `json:"context"`
+	Exten    string `json:"exten"`
+	Priority int64  `json:"priority"`
+	AppName  string `json:"app_name"`
+	AppData  string `json:"app_data"`
+}
+
+// MarshalJSON encodes DialplanCEP to JSON
+func (d *DialplanCEP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&dialplanCEPJSON{
+		Context:  d.Context,
+		Exten:    d.Exten,
+		Priority: d.Priority,
+		AppName:  d.AppName,
+		AppData:  d.AppData,
+	})
+}
+
+// creationtimeOffsetTag is the protobuf tag (field 13, varint) under which
+// ChannelData keeps, among its unrecognized fields, the offset in seconds
+// east of UTC of the zone in which the creation time was reported.  The
+// creation time is a protobuf Timestamp, which has no zone, and the offset
+// is not a field of the message in ari.proto, so it is carried this way to
+// survive both JSON and protobuf round-trips.
+const creationtimeOffsetTag = 13<<3 | proto.WireVarint
+
+// creationtimeOffset returns the offset of the zone in which the creation
+// time was reported, or zero (UTC) if it is not known
+func (d *ChannelData) creationtimeOffset() int {
+	b := proto.NewBuffer(d.XXX_unrecognized)
+
+	for {
+		tag, err := b.DecodeVarint()
+		if err != nil {
+			return 0
+		}
+
+		if tag == creationtimeOffsetTag {
+			v, err := b.DecodeVarint()
+			if err != nil {
+				return 0
+			}
+
+			return int(int32(v))
+		}
+
+		if err := skipField(b, tag); err != nil {
+			return 0
+		}
+	}
+}
+
+// skipField skips the value of an unrecognized protobuf field with the given tag
+func skipField(b *proto.Buffer, tag uint64) (err error) {
+	switch tag & 7 {
+	case proto.WireVarint:
+		_, err = b.DecodeVarint()
+	case proto.WireFixed64:
+		_, err = b.DecodeFixed64()
+	case proto.WireBytes:
+		_, err = b.DecodeRawBytes(false)
+	case proto.WireFixed32:
+		_, err = b.DecodeFixed32()
+	default:
+		err = eris.Errorf("unsupported wire type %d", tag&7)
+	}
+
+	return err
+}
+
+// MarshalJSON encodes ChannelData to JSON.  The creation time is encoded in
+// the zone in which it was reported.
 func (d *ChannelData) MarshalJSON() ([]byte, error) {
 	t, err := ptypes.TimestampFromProto(d.Creationtime)
 	if err != nil {
 		t = time.Now()
 	}
 
+	t = t.In(time.FixedZone("", d.creationtimeOffset()))
+
 	return json.Marshal(&channelDataJSON{
 		Key:          d.Key,
 		ID:           d.ID,
@@ -191,6 +273,8 @@ func (d *ChannelData) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	_, offset := time.Time(in.Creationtime).Zone()
+
 	*d = ChannelData{
 		Key:          in.Key,
 		ID:           in.ID,
@@ -206,9 +290,32 @@ func (d *ChannelData) UnmarshalJSON(data []byte) error {
 		ProtocolId:   in.ProtocolId,
 	}
 
+	if offset != 0 {
+		d.XXX_unrecognized = append(proto.EncodeVarint(creationtimeOffsetTag), proto.EncodeVarint(uint64(int64(offset)))...)
+	}
+
 	return nil
 }
 
+// ChannelState returns the state of the channel
+func (d *ChannelData) ChannelState() ChannelState {
+	if d == nil {
+		return ChannelStateUnknown
+	}
+
+	return ChannelState(d.State)
+}
+
+// IsAnswered indicates whether the channel has been answered
+func (d *ChannelData) IsAnswered() bool {
+	return d.ChannelState().IsAnswered()
+}
+
+// IsRinging indicates whether the channel is ringing
+func (d *ChannelData) IsRinging() bool {
+	return d.ChannelState().IsRinging()
+}
+
 // ChannelCreateRequest describes how a channel should be created, when
 // using the separate Create and Dial calls.
 type ChannelCreateRequest struct {
@@ -235,6 +342,21 @@ type ChannelCreateRequest struct {
 	Formats string `json:"formats,omitempty"`
 }
 
+// Validate checks the ChannelCreateRequest against the rules which Asterisk
+// enforces, returning a *ValidationError describing any problems.
+// The native client checks it before sending the request.
+func (req *ChannelCreateRequest) Validate() error {
+	v := new(validator)
+
+	v.check(req.Endpoint != "", "Endpoint", "is required")
+	v.check(req.Endpoint == "" || validEndpoint(req.Endpoint), "Endpoint", "must be of the form tech/resource")
+	v.check(req.App != "", "App", "is required")
+	v.check(req.OtherChannelID == "" || isLocalEndpoint(req.Endpoint), "OtherChannelID", "is only valid for Local channels")
+	v.check(req.Formats == "" || req.Originator == "", "Formats", "may not be used with Originator")
+
+	return v.err()
+}
+
 // SnoopOptions enumerates the non-required arguments for the snoop operation
 type SnoopOptions struct {
 	// App is the ARI application into which the newly-created Snoop channel should be dropped.
@@ -252,6 +374,20 @@ type SnoopOptions struct {
 	Whisper Direction `json:"whisper,omitempty"`
 }
 
+// Validate checks the SnoopOptions against the rules which Asterisk
+// enforces, returning a *ValidationError describing any problems.
+// The native client checks it before sending the request.
+func (opts *SnoopOptions) Validate() error {
+	v := new(validator)
+
+	v.check(opts.App != "", "App", "is required")
+	v.check(opts.Spy.valid(), "Spy", "must be one of: none, in, out, both")
+	v.check(opts.Whisper.valid(), "Whisper", "must be one of: none, in, out, both")
+	v.check(opts.Spy.orNone() != DirectionNone || opts.Whisper.orNone() != DirectionNone, "Spy", "may not be none when Whisper is none")
+
+	return v.err()
+}
+
 // ExternalMediaOptions describes the parameters to the externalMedia channel creation operation
 type ExternalMediaOptions struct {
 	// ChannelID specifies the channel ID to be used for the external media channel.  This parameter is optional and if not specified, a randomly-generated channel ID will be used.
@@ -260,7 +396,7 @@ type ExternalMediaOptions struct {
 	// App is the ARI Application to which the newly-created external media channel should be placed.  This parameter is optional and if not specified, the current application will be used.
 	App string `json:"app"`
 
-	// ExternalHost specifies the <host>:<port> of the external host to which the external media channel will be connected.  This parameter is MANDATORY and has no default.
+	// ExternalHost specifies the <host>:<port> of the external host to which the external media channel will be connected (or, for the websocket transport, the name of a websocket client configuration).  This parameter is MANDATORY for client connections and has no default.
 	ExternalHost string `json:"external_host"`
 
 	// Encapsulation specifies the payload encapsulation which should be used.  Options include:  'rtp'.  This parameter is optional and if not specified, 'rtp' will be used.
@@ -285,6 +421,37 @@ type ExternalMediaOptions struct {
 	Variables map[string]string `json:"variables"`
 }
 
+// Validate checks the ExternalMediaOptions against the rules which Asterisk
+// enforces, returning a *ValidationError describing any problems.  Empty
+// optional fields are valid, since they are given defaults when the channel
+// is created.  The native client checks it, once it has applied those
+// defaults, before sending the request.
+func (opts *ExternalMediaOptions) Validate() error {
+	v := new(validator)
+
+	// Asterisk connects to the external host only as a client; as a server,
+	// it waits for the connection
+	client := opts.ConnectionType == "" || opts.ConnectionType == "client"
+
+	v.check(!client || opts.ExternalHost != "", "ExternalHost", "is required for client connections")
+
+	// A websocket client connects to the external host by the name of its
+	// configuration (in websocket_client.conf), rather than by address
+	if client && opts.ExternalHost != "" && opts.Transport != "websocket" {
+		_, port, err := net.SplitHostPort(opts.ExternalHost)
+		v.check(err == nil && port != "", "ExternalHost", "must be of the form <host>:<port>")
+	}
+
+	v.check(opts.Format != "", "Format", "is required")
+	v.oneOf(opts.Encapsulation, "Encapsulation", "rtp", "audiosocket", "none")
+	v.oneOf(opts.Transport, "Transport", "udp", "tcp", "websocket")
+	v.oneOf(opts.ConnectionType, "ConnectionType", "client", "server")
+	v.oneOf(opts.Direction, "Direction", "both")
+	v.check(opts.Encapsulation != "audiosocket" || opts.Data != "", "Data", "is required for audiosocket encapsulation")
+
+	return v.err()
+}
+
 // ChannelHandle provides a wrapper on the Channel interface for operations on a particular channel ID.
 type ChannelHandle struct {
 	key *Key
@@ -352,6 +519,17 @@ func (ch *ChannelHandle) Play(id string, mediaURI ...string) (ph *PlaybackHandle
 	return ch.c.Play(ch.key, id, mediaURI...)
 }
 
+// PlayMedia initiates playback of the given typed media URIs, which are
+// validated before they are sent
+func (ch *ChannelHandle) PlayMedia(id string, uri ...MediaURI) (*PlaybackHandle, error) {
+	list, err := MediaURIs(uri...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.c.Play(ch.key, id, list...)
+}
+
 // Record records the channel to the given filename
 func (ch *ChannelHandle) Record(name string, opts *RecordingOptions) (*LiveRecordingHandle, error) {
 	return ch.c.Record(ch.key, name, opts)
@@ -362,6 +540,16 @@ func (ch *ChannelHandle) StagePlay(id string, mediaURI ...string) (*PlaybackHand
 	return ch.c.StagePlay(ch.key, id, mediaURI...)
 }
 
+// StagePlayMedia stages a `PlayMedia` operation
+func (ch *ChannelHandle) StagePlayMedia(id string, uri ...MediaURI) (*PlaybackHandle, error) {
+	list, err := MediaURIs(uri...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.c.StagePlay(ch.key, id, list...)
+}
+
 // StageRecord stages a `Record` operation
 func (ch *ChannelHandle) StageRecord(name string, opts *RecordingOptions) (*LiveRecordingHandle, error) {
 	return ch.c.StageRecord(ch.key, name, opts)
@@ -383,7 +571,12 @@ func (ch *ChannelHandle) Congestion() error {
 
 // Hangup hangs up the channel with the normal cause code
 func (ch *ChannelHandle) Hangup() error {
-	return ch.c.Hangup(ch.key, "normal")
+	return ch.c.Hangup(ch.key, string(HangupNormal))
+}
+
+// HangupWithReason hangs up the channel with the given reason
+func (ch *ChannelHandle) HangupWithReason(reason HangupReason) error {
+	return ch.c.Hangup(ch.key, string(reason))
 }
 
 //--
@@ -404,7 +597,7 @@ func (ch *ChannelHandle) IsAnswered() (bool, error) {
 		return false, eris.Wrap(err, "Failed to get updated channel")
 	}
 
-	return strings.ToLower(updated.State) == "up", nil
+	return updated.IsAnswered(), nil
 }
 
 // ------
@@ -0,0 +1,152 @@
+package ari
+
+// ChannelState describes the state of a channel, as reported in the State
+// field of ChannelData
+type ChannelState string
+
+const (
+	// ChannelStateDown indicates the channel is down and available
+	ChannelStateDown ChannelState = "Down"
+
+	// ChannelStateReserved indicates the channel is down but reserved
+	ChannelStateReserved ChannelState = "Rsrved"
+
+	// ChannelStateOffHook indicates the channel is off hook
+	ChannelStateOffHook ChannelState = "OffHook"
+
+	// ChannelStateDialing indicates digits (or equivalent) have been dialed
+	ChannelStateDialing ChannelState = "Dialing"
+
+	// ChannelStateRing indicates the line is ringing:  an inbound call is being offered
+	ChannelStateRing ChannelState = "Ring"
+
+	// ChannelStateRinging indicates the remote end is ringing
+	ChannelStateRinging ChannelState = "Ringing"
+
+	// ChannelStateUp indicates the channel is answered
+	ChannelStateUp ChannelState = "Up"
+
+	// ChannelStateBusy indicates the line is busy
+	ChannelStateBusy ChannelState = "Busy"
+
+	// ChannelStateDialingOffHook indicates digits have been dialed while off hook
+	ChannelStateDialingOffHook ChannelState = "Dialing Offhook"
+
+	// ChannelStatePreRing indicates the channel has detected an incoming call and is waiting for ring
+	ChannelStatePreRing ChannelState = "Pre-ring"
+
+	// ChannelStateUnknown indicates the state of the channel is not known
+	ChannelStateUnknown ChannelState = "Unknown"
+)
+
+// channelStateTransitions lists the states to which each state may move
+var channelStateTransitions = map[ChannelState][]ChannelState{
+	ChannelStateDown: {
+		ChannelStateReserved, ChannelStateOffHook, ChannelStateDialing, ChannelStateDialingOffHook,
+		ChannelStatePreRing, ChannelStateRing, ChannelStateRinging, ChannelStateUp, ChannelStateBusy,
+	},
+	ChannelStateReserved: {
+		ChannelStateDown, ChannelStateOffHook, ChannelStateDialing, ChannelStateRing,
+		ChannelStateRinging, ChannelStateUp, ChannelStateBusy,
+	},
+	ChannelStateOffHook:        {ChannelStateDown, ChannelStateDialing, ChannelStateDialingOffHook, ChannelStateUp, ChannelStateBusy},
+	ChannelStateDialing:        {ChannelStateDown, ChannelStateRing, ChannelStateRinging, ChannelStateUp, ChannelStateBusy},
+	ChannelStateDialingOffHook: {ChannelStateDown, ChannelStateRing, ChannelStateRinging, ChannelStateUp, ChannelStateBusy},
+	ChannelStatePreRing:        {ChannelStateDown, ChannelStateRing, ChannelStateRinging, ChannelStateUp},
+	ChannelStateRing:           {ChannelStateDown, ChannelStateRinging, ChannelStateUp, ChannelStateBusy},
+	ChannelStateRinging:        {ChannelStateDown, ChannelStateRing, ChannelStateUp, ChannelStateBusy},
+	ChannelStateUp:             {ChannelStateDown},
+	ChannelStateBusy:           {ChannelStateDown},
+}
+
+// Valid indicates whether the state is one of the known channel states
+func (s ChannelState) Valid() bool {
+	if s == ChannelStateUnknown {
+		return true
+	}
+
+	_, ok := channelStateTransitions[s]
+
+	return ok
+}
+
+// CanTransitionTo indicates whether a channel in this state may move to the
+// given state.  A state may always "move" to itself, and a channel in an
+// unknown state may move to any state.
+func (s ChannelState) CanTransitionTo(next ChannelState) bool {
+	if s == next || s == ChannelStateUnknown || !s.Valid() {
+		return next.Valid()
+	}
+
+	for _, x := range channelStateTransitions[s] {
+		if x == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAnswered indicates whether the state is that of an answered channel
+func (s ChannelState) IsAnswered() bool {
+	return s == ChannelStateUp
+}
+
+// IsRinging indicates whether the state is that of a ringing channel,
+// whether the ringing is inbound (Ring) or outbound (Ringing)
+func (s ChannelState) IsRinging() bool {
+	return s == ChannelStateRing || s == ChannelStateRinging
+}
+
+// DialStatus describes the status of a dial attempt, as reported in the
+// Dialstatus field of the Dial event
+type DialStatus string
+
+const (
+	// DialStatusNone indicates the dial attempt has just begun
+	DialStatusNone DialStatus = ""
+
+	// DialStatusProgress indicates the peer has indicated progress
+	DialStatusProgress DialStatus = "PROGRESS"
+
+	// DialStatusRinging indicates the peer is ringing
+	DialStatusRinging DialStatus = "RINGING"
+
+	// DialStatusAnswer indicates the peer answered
+	DialStatusAnswer DialStatus = "ANSWER"
+
+	// DialStatusBusy indicates the peer was busy
+	DialStatusBusy DialStatus = "BUSY"
+
+	// DialStatusNoAnswer indicates the peer did not answer
+	DialStatusNoAnswer DialStatus = "NOANSWER"
+
+	// DialStatusCancel indicates the dial attempt was cancelled
+	DialStatusCancel DialStatus = "CANCEL"
+
+	// DialStatusCongestion indicates the peer could not be reached due to congestion
+	DialStatusCongestion DialStatus = "CONGESTION"
+
+	// DialStatusChanUnavailable indicates the peer channel was unavailable
+	DialStatusChanUnavailable DialStatus = "CHANUNAVAIL"
+)
+
+// IsAnswered indicates whether the dial attempt was answered
+func (s DialStatus) IsAnswered() bool {
+	return s == DialStatusAnswer
+}
+
+// IsRinging indicates whether the peer of the dial attempt is ringing
+func (s DialStatus) IsRinging() bool {
+	return s == DialStatusRinging
+}
+
+// IsFinal indicates whether the status is a final outcome of the dial attempt
+func (s DialStatus) IsFinal() bool {
+	switch s {
+	case DialStatusNone, DialStatusProgress, DialStatusRinging:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package ari
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChannelStateTransitions(t *testing.T) {
+	tests := []struct {
+		from, to ChannelState
+		ok       bool
+	}{
+		{ChannelStateDown, ChannelStateRinging, true},
+		{ChannelStateRing, ChannelStateUp, true},
+		{ChannelStateRinging, ChannelStateUp, true},
+		{ChannelStateUp, ChannelStateDown, true},
+		{ChannelStateUp, ChannelStateUp, true},
+		{ChannelStateUp, ChannelStateRinging, false},
+		{ChannelStateBusy, ChannelStateUp, false},
+		{ChannelStateUnknown, ChannelStateUp, true},
+		{ChannelStateDown, "Bogus", false},
+	}
+
+	for _, tt := range tests {
+		if ok := tt.from.CanTransitionTo(tt.to); ok != tt.ok {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.ok, ok)
+		}
+	}
+
+	for from, list := range channelStateTransitions {
+		for _, to := range list {
+			if !to.Valid() {
+				t.Errorf("%s transitions to invalid state %s", from, to)
+			}
+		}
+	}
+}
+
+func TestChannelDataState(t *testing.T) {
+	var d *ChannelData
+
+	if s := d.ChannelState(); s != ChannelStateUnknown {
+		t.Errorf("expected nil data to be in unknown state, got %s", s)
+	}
+
+	d = &ChannelData{State: "Ringing"}
+
+	if !d.IsRinging() || d.IsAnswered() {
+		t.Errorf("expected ringing channel")
+	}
+
+	d.State = "Up"
+
+	if d.IsRinging() || !d.IsAnswered() {
+		t.Errorf("expected answered channel")
+	}
+}
+
+func TestEventStateHelpers(t *testing.T) {
+	for _, name := range []string{"ChannelStateChange", "Dial"} {
+		golden, err := os.ReadFile(filepath.Join("testdata", "events", name+".json"))
+		if err != nil {
+			t.Fatalf("failed to read sample: %v", err)
+		}
+
+		e, err := DecodeEvent(golden)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", name, err)
+		}
+
+		switch v := e.(type) {
+		case *ChannelStateChange:
+			if !v.IsRinging() || v.IsAnswered() {
+				t.Errorf("expected ringing channel state change")
+			}
+		case *Dial:
+			if !v.IsAnswered() || v.IsRinging() || !v.Status().IsFinal() {
+				t.Errorf("expected answered dial, got %q", v.Dialstatus)
+			}
+		default:
+			t.Errorf("unexpected event type %T", e)
+		}
+	}
+}
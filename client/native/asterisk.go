@@ -2,6 +2,7 @@ package native
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/rotisserie/eris"
 
@@ -64,7 +65,7 @@ func (a *AsteriskVariables) Get(key *ari.Key) (string, error) {
 		Value string `json:"value"`
 	}
 
-	err := a.client.get(fmt.Sprintf("/asterisk/variable?variable=%s", key.ID), &m)
+	err := a.client.get(fmt.Sprintf("/asterisk/variable?variable=%s", url.QueryEscape(key.ID)), &m)
 	if err != nil {
 		return "", eris.Wrapf(err, "Error getting asterisk variable '%v'", key.ID)
 	}
@@ -3,6 +3,7 @@ package native
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/CyCoreSystems/ari/v6"
@@ -104,6 +105,10 @@ func (c *Channel) StageOriginate(referenceKey *ari.Key, req ari.OriginateRequest
 		req.ChannelID = rid.New(rid.Channel)
 	}
 
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	return ari.NewChannelHandle(c.client.stamp(ari.NewKey(ari.ChannelKey, req.ChannelID)), c,
 		func(ch *ari.ChannelHandle) error {
 			type response struct {
@@ -128,6 +133,10 @@ func (c *Channel) Create(key *ari.Key, req ari.ChannelCreateRequest) (*ari.Chann
 		req.ChannelID = rid.New(rid.Channel)
 	}
 
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	err := c.client.post("/channels/create", nil, &req)
 	if err != nil {
 		return nil, err
@@ -372,9 +381,22 @@ func (c *Channel) Snoop(key *ari.Key, snoopID string, opts *ari.SnoopOptions) (*
 }
 
 // StageSnoop creates a new `ChannelHandle` with a `Snoop` operation staged.
+// If no options are given, the snoop spies on both directions of the
+// channel.  The snooping channel enters the client's application unless
+// the options name another.
 func (c *Channel) StageSnoop(key *ari.Key, snoopID string, opts *ari.SnoopOptions) (*ari.ChannelHandle, error) {
 	if opts == nil {
-		opts = &ari.SnoopOptions{App: c.client.ApplicationName()}
+		opts = &ari.SnoopOptions{Spy: ari.DirectionBoth}
+	}
+
+	if opts.App == "" {
+		o := *opts
+		o.App = c.client.ApplicationName()
+		opts = &o
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
 
 	if snoopID == "" {
@@ -409,10 +431,6 @@ func (c *Channel) StageExternalMedia(key *ari.Key, opts ari.ExternalMediaOptions
 		opts.App = c.client.ApplicationName()
 	}
 
-	if opts.ExternalHost == "" {
-		return nil, errors.New("ExternalHost is mandatory")
-	}
-
 	if opts.Encapsulation == "" {
 		opts.Encapsulation = "rtp"
 	}
@@ -425,14 +443,14 @@ func (c *Channel) StageExternalMedia(key *ari.Key, opts ari.ExternalMediaOptions
 		opts.ConnectionType = "client"
 	}
 
-	if opts.Format == "" {
-		return nil, errors.New("format is mandatory")
-	}
-
 	if opts.Direction == "" {
 		opts.Direction = "both"
 	}
 
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Create the snooping channel's key
 	k := c.client.stamp(ari.NewKey(ari.ChannelKey, opts.ChannelID))
 
@@ -468,7 +486,7 @@ func (c *Channel) GetVariable(key *ari.Key, name string) (string, error) {
 		Value string `json:"value"`
 	}
 
-	err := c.client.get(fmt.Sprintf("/channels/%s/variable?variable=%s", key.ID, name), &m)
+	err := c.client.get(fmt.Sprintf("/channels/%s/variable?variable=%s", key.ID, url.QueryEscape(name)), &m)
 
 	return m.Value, err
 }
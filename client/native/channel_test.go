@@ -0,0 +1,63 @@
+package native
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+func TestChannelValidation(t *testing.T) {
+	var requests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := New(&Options{Application: "test", URL: srv.URL})
+	ch := ari.NewKey(ari.ChannelKey, "ch1")
+
+	_, err := c.Channel().Originate(nil, ari.OriginateRequest{Endpoint: "PJSIP/alice", App: "test"})
+	checkValidation(t, "originate without timeout", err, "Timeout")
+
+	_, err = c.Channel().Create(nil, ari.ChannelCreateRequest{App: "test"})
+	checkValidation(t, "create without endpoint", err, "Endpoint")
+
+	_, err = c.Channel().Snoop(ch, "", &ari.SnoopOptions{Spy: ari.DirectionNone, Whisper: ari.DirectionNone})
+	checkValidation(t, "snoop on nothing", err, "Spy")
+
+	_, err = c.Channel().ExternalMedia(ch, ari.ExternalMediaOptions{ExternalHost: "127.0.0.1:4000"})
+	checkValidation(t, "external media without format", err, "Format")
+
+	if requests != 0 {
+		t.Errorf("expected invalid requests not to be sent, got %d requests", requests)
+	}
+
+	if _, err = c.Channel().Snoop(ch, "", nil); err != nil {
+		t.Errorf("expected the default snoop to be valid, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the default snoop to be sent, got %d requests", requests)
+	}
+}
+
+func checkValidation(t *testing.T, name string, err error, field string) {
+	t.Helper()
+
+	var verr *ari.ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("%s: expected a validation error, got %v", name, err)
+		return
+	}
+
+	if verr.Field(field) == nil {
+		t.Errorf("%s: expected field %s to be invalid, got %v", name, field, err)
+	}
+}
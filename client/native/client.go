@@ -145,6 +145,7 @@ func New(opts *Options) *Client {
 
 	return &Client{
 		appName: opts.Application,
+		dialogs: ari.NewDialogRegistry(),
 		Options: opts,
 	}
 }
@@ -167,6 +168,9 @@ type Client struct {
 	// Bus the event bus for the Client
 	bus ari.Bus
 
+	// dialogs is the registry of dialog bindings by which events are tagged before being sent to the bus
+	dialogs *ari.DialogRegistry
+
 	// httpClient is the reusable HTTP client on which commands to Asterisk are sent
 	httpClient http.Client
 
@@ -224,6 +228,11 @@ func (c *Client) DeviceState() ari.DeviceState {
 	return &DeviceState{c}
 }
 
+// Dialogs returns the dialog registry for this client
+func (c *Client) Dialogs() *ari.DialogRegistry {
+	return c.dialogs
+}
+
 // Endpoint returns the ARI Endpoint accessors for this client
 func (c *Client) Endpoint() ari.Endpoint {
 	return &Endpoint{c}
@@ -425,6 +434,8 @@ func (c *Client) wsRead(ws *websocket.Conn) chan error {
 				continue
 			}
 
+			c.dialogs.Tag(e)
+
 			c.bus.Send(e)
 		}
 	}()
@@ -432,7 +443,8 @@ func (c *Client) wsRead(ws *websocket.Conn) chan error {
 	return errChan
 }
 
-// stamp imprints the node metadata onto the given Key
+// stamp imprints the node metadata onto the given Key, along with the
+// dialog to which the resource it describes is bound, if any
 func (c *Client) stamp(key *ari.Key) *ari.Key {
 	if key == nil {
 		key = &ari.Key{}
@@ -442,6 +454,10 @@ func (c *Client) stamp(key *ari.Key) *ari.Key {
 	ret.App = c.appName
 	ret.Node = c.node
 
+	if ret.Dialog == "" && c.dialogs != nil {
+		ret.Dialog = c.dialogs.Dialog(&ret)
+	}
+
 	return &ret
 }
 
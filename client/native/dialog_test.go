@@ -0,0 +1,27 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+func TestDialogKeys(t *testing.T) {
+	c := New(&Options{Application: "test"})
+
+	if err := c.Dialogs().Bind("call1", ari.NewKey(ari.ChannelKey, "ch1")); err != nil {
+		t.Fatalf("failed to bind channel: %v", err)
+	}
+
+	if d := c.Channel().Get(ari.NewKey(ari.ChannelKey, "ch1")).Key().Dialog; d != "call1" {
+		t.Errorf("expected the channel handle to carry dialog 'call1', got '%s'", d)
+	}
+
+	if d := c.Channel().Get(ari.NewKey(ari.ChannelKey, "ch2")).Key().Dialog; d != "" {
+		t.Errorf("expected no dialog for an unbound channel, got '%s'", d)
+	}
+
+	if d := c.Bridge().Get(ari.NewKey(ari.BridgeKey, "ch1")).Key().Dialog; d != "" {
+		t.Errorf("expected no dialog for a bridge of the same ID, got '%s'", d)
+	}
+}
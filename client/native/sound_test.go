@@ -0,0 +1,45 @@
+package native
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+func TestSoundList(t *testing.T) {
+	var query string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sounds" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query = r.URL.RawQuery
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": "hello-world", "text": "Hello world.", "formats": [{"language": "en", "format": "gsm"}]},
+			{"id": "digits/1", "text": "One.", "formats": [{"language": "fr", "format": "gsm"}]},
+			{"name": "legacy", "text": "Not a sound ID."}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := New(&Options{Application: "test", URL: srv.URL})
+
+	list, err := c.Sound().List(map[string]string{"lang": "fr"}, nil)
+	if err != nil {
+		t.Fatalf("failed to list sounds: %v", err)
+	}
+
+	if query != "lang=fr" {
+		t.Errorf("expected filter 'lang=fr', got '%s'", query)
+	}
+
+	if len(list) != 2 || list[0].ID != "hello-world" || list[1].ID != "digits/1" || list[0].Kind != ari.SoundKey {
+		t.Errorf("unexpected sound keys %v", list)
+	}
+}
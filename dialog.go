@@ -0,0 +1,247 @@
+package ari
+
+import (
+	"errors"
+	"sync"
+)
+
+// DialogRegistry binds ARI resources (channels, bridges, playbacks, live
+// recordings, etc) to dialogs.  Events passed through the registry (see
+// `Tag`) which relate to a bound resource are tagged with that resource's
+// dialog, so that all the events for a call may be received with a single
+// `DialogKey` subscription.
+//
+// Child resources of a bound resource are bound automatically:  playbacks
+// and recordings started on a bound channel or bridge, channels dialed by a
+// bound channel, and channels replacing a bound channel.  A binding is
+// released when its resource is destroyed, and a dialog is released when its
+// last binding is released.
+type DialogRegistry struct {
+	bindings map[dialogResource]string
+
+	mu sync.RWMutex
+}
+
+// DialogClient is implemented by clients which tag their events by dialog
+// binding, such as the native client
+type DialogClient interface {
+	// Dialogs returns the dialog registry of the client
+	Dialogs() *DialogRegistry
+}
+
+// Dialogs returns the dialog registry of the given client, or nil if the
+// client does not maintain one
+func Dialogs(c Client) *DialogRegistry {
+	if dc, ok := c.(DialogClient); ok {
+		return dc.Dialogs()
+	}
+
+	return nil
+}
+
+// dialogResource identifies a resource which may be bound to a dialog
+type dialogResource struct {
+	kind string
+	id   string
+}
+
+// NewDialogRegistry returns a new, empty dialog registry
+func NewDialogRegistry() *DialogRegistry {
+	return &DialogRegistry{
+		bindings: make(map[dialogResource]string),
+	}
+}
+
+// Bind binds the resource described by the given key to the given dialog.  If
+// the resource is already bound to a dialog, it is rebound to the new one.
+func (r *DialogRegistry) Bind(dialog string, key *Key) error {
+	if dialog == "" {
+		return errors.New("no dialog specified")
+	}
+
+	if key == nil || key.Kind == "" || key.ID == "" {
+		return errors.New("key must specify both a kind and an ID")
+	}
+
+	r.mu.Lock()
+	r.bindings[dialogResource{kind: key.Kind, id: key.ID}] = dialog
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Unbind removes any dialog binding for the resource described by the given key
+func (r *DialogRegistry) Unbind(key *Key) {
+	if key == nil {
+		return
+	}
+
+	r.mu.Lock()
+	delete(r.bindings, dialogResource{kind: key.Kind, id: key.ID})
+	r.mu.Unlock()
+}
+
+// Release removes all bindings to the given dialog
+func (r *DialogRegistry) Release(dialog string) {
+	r.mu.Lock()
+
+	for res, d := range r.bindings {
+		if d == dialog {
+			delete(r.bindings, res)
+		}
+	}
+
+	r.mu.Unlock()
+}
+
+// Dialog returns the dialog to which the resource described by the given key
+// is bound, or the empty string if it is not bound.
+func (r *DialogRegistry) Dialog(key *Key) string {
+	if key == nil {
+		return ""
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.bindings[dialogResource{kind: key.Kind, id: key.ID}]
+}
+
+// Bindings returns the keys of the resources which are bound to the given dialog
+func (r *DialogRegistry) Bindings(dialog string) (kx Keys) {
+	r.mu.RLock()
+
+	for res, d := range r.bindings {
+		if d == dialog {
+			kx = append(kx, NewKey(res.kind, res.id, WithDialog(dialog)))
+		}
+	}
+
+	r.mu.RUnlock()
+
+	return
+}
+
+// Dialogs returns the list of dialogs which have at least one bound resource
+func (r *DialogRegistry) Dialogs() (list []string) {
+	seen := make(map[string]bool)
+
+	r.mu.RLock()
+
+	for _, d := range r.bindings {
+		if !seen[d] {
+			seen[d] = true
+
+			list = append(list, d)
+		}
+	}
+
+	r.mu.RUnlock()
+
+	return
+}
+
+// Tag tags the given event with the dialog of the first bound resource to
+// which the event relates, if any, returning that dialog.  Child resources
+// of bound resources are bound and destroyed resources are released as a
+// side effect.  Tag should be called for each event before it is dispatched
+// to the bus.
+func (r *DialogRegistry) Tag(e Event) string {
+	if e == nil {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dialog := e.GetDialog()
+	if dialog == "" {
+		for _, res := range relatedResources(e) {
+			if d, ok := r.bindings[res]; ok {
+				dialog = d
+				break
+			}
+		}
+	}
+
+	if dialog == "" {
+		return ""
+	}
+
+	e.SetDialog(dialog)
+
+	r.bindChildren(dialog, e)
+	r.releaseDestroyed(e)
+
+	return dialog
+}
+
+// relatedResources returns the resources to which the event relates:  those
+// of its keys, followed by the targets of any playbacks or recordings.
+func relatedResources(e Event) (list []dialogResource) {
+	for _, k := range e.Keys() {
+		list = append(list, dialogResource{kind: k.Kind, id: k.ID})
+	}
+
+	if v, ok := e.(interface{ GetChannelIDs() []string }); ok {
+		for _, id := range v.GetChannelIDs() {
+			list = append(list, dialogResource{kind: ChannelKey, id: id})
+		}
+	}
+
+	if v, ok := e.(interface{ GetBridgeIDs() []string }); ok {
+		for _, id := range v.GetBridgeIDs() {
+			list = append(list, dialogResource{kind: BridgeKey, id: id})
+		}
+	}
+
+	return list
+}
+
+// bindChildren binds any resources created by a bound resource to its dialog.
+// The registry lock must be held.
+func (r *DialogRegistry) bindChildren(dialog string, e Event) {
+	switch v := e.(type) {
+	case *Dial:
+		r.bindID(dialog, ChannelKey, v.Peer.ID)
+		r.bindID(dialog, ChannelKey, v.Forwarded.ID)
+	case *PlaybackStarted:
+		r.bindID(dialog, PlaybackKey, v.Playback.ID)
+	case *RecordingStarted:
+		r.bindID(dialog, LiveRecordingKey, v.Recording.ID())
+	case *StasisStart:
+		if v.ReplaceChannel.ID != "" {
+			r.bindID(dialog, ChannelKey, v.Channel.ID)
+		}
+	}
+}
+
+// releaseDestroyed removes the binding of any resource destroyed by the
+// event.  The registry lock must be held.
+func (r *DialogRegistry) releaseDestroyed(e Event) {
+	switch v := e.(type) {
+	case *BridgeDestroyed:
+		delete(r.bindings, dialogResource{kind: BridgeKey, id: v.Destroyed()})
+	case *ChannelDestroyed:
+		delete(r.bindings, dialogResource{kind: ChannelKey, id: v.Destroyed()})
+	case *PlaybackFinished:
+		delete(r.bindings, dialogResource{kind: PlaybackKey, id: v.Destroyed()})
+	case *RecordingFailed:
+		delete(r.bindings, dialogResource{kind: LiveRecordingKey, id: v.Destroyed()})
+	case *RecordingFinished:
+		delete(r.bindings, dialogResource{kind: LiveRecordingKey, id: v.Destroyed()})
+	}
+}
+
+// bindID binds the given resource to the dialog, if it is not already bound
+// to a dialog.  The registry lock must be held.
+func (r *DialogRegistry) bindID(dialog, kind, id string) {
+	if id == "" {
+		return
+	}
+
+	res := dialogResource{kind: kind, id: id}
+	if _, ok := r.bindings[res]; !ok {
+		r.bindings[res] = dialog
+	}
+}
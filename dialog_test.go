@@ -0,0 +1,141 @@
+package ari
+
+import "testing"
+
+func TestDialogRegistryBind(t *testing.T) {
+	r := NewDialogRegistry()
+
+	if err := r.Bind("", NewKey(ChannelKey, "ch1")); err == nil {
+		t.Errorf("Expected error binding to empty dialog")
+	}
+
+	if err := r.Bind("d1", NewKey(ChannelKey, "")); err == nil {
+		t.Errorf("Expected error binding key without ID")
+	}
+
+	if err := r.Bind("d1", NewKey(ChannelKey, "ch1")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := r.Bind("d1", NewKey(BridgeKey, "br1")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if d := r.Dialog(NewKey(ChannelKey, "ch1")); d != "d1" {
+		t.Errorf("Expected dialog '%s', got '%s'", "d1", d)
+	}
+
+	if d := r.Dialog(NewKey(BridgeKey, "ch1")); d != "" {
+		t.Errorf("Expected no dialog for differing kind, got '%s'", d)
+	}
+
+	if n := len(r.Bindings("d1")); n != 2 {
+		t.Errorf("Expected 2 bindings, got %d", n)
+	}
+
+	r.Unbind(NewKey(BridgeKey, "br1"))
+
+	if n := len(r.Bindings("d1")); n != 1 {
+		t.Errorf("Expected 1 binding, got %d", n)
+	}
+
+	r.Release("d1")
+
+	if n := len(r.Dialogs()); n != 0 {
+		t.Errorf("Expected no dialogs after release, got %d", n)
+	}
+}
+
+func TestDialogRegistryTag(t *testing.T) {
+	r := NewDialogRegistry()
+
+	if err := r.Bind("d1", NewKey(ChannelKey, "ch1")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	unrelated := &ChannelStateChange{
+		EventData: EventData{Type: Events.ChannelStateChange},
+		Channel:   ChannelData{ID: "ch2"},
+	}
+	if d := r.Tag(unrelated); d != "" || unrelated.GetDialog() != "" {
+		t.Errorf("Expected unrelated event to be untagged, got '%s'", unrelated.GetDialog())
+	}
+
+	state := &ChannelStateChange{
+		EventData: EventData{Type: Events.ChannelStateChange},
+		Channel:   ChannelData{ID: "ch1"},
+	}
+	if d := r.Tag(state); d != "d1" || state.GetDialog() != "d1" {
+		t.Errorf("Expected event to be tagged with '%s', got '%s'", "d1", state.GetDialog())
+	}
+
+	if !DialogKey("d1").Match(state.Keys().First()) {
+		t.Errorf("Expected dialog key to match tagged event key")
+	}
+
+	// Playbacks started on a bound channel should be bound automatically
+	r.Tag(&PlaybackStarted{
+		EventData: EventData{Type: Events.PlaybackStarted},
+		Playback:  PlaybackData{ID: "pb1", TargetURI: "channel:ch1"},
+	})
+
+	if d := r.Dialog(NewKey(PlaybackKey, "pb1")); d != "d1" {
+		t.Errorf("Expected playback to be bound to '%s', got '%s'", "d1", d)
+	}
+
+	finished := &PlaybackFinished{
+		EventData: EventData{Type: Events.PlaybackFinished},
+		Playback:  PlaybackData{ID: "pb1", TargetURI: "channel:ch1"},
+	}
+	if d := r.Tag(finished); d != "d1" {
+		t.Errorf("Expected finished playback to be tagged with '%s', got '%s'", "d1", d)
+	}
+
+	if d := r.Dialog(NewKey(PlaybackKey, "pb1")); d != "" {
+		t.Errorf("Expected finished playback to be released, got '%s'", d)
+	}
+
+	// Channels dialed by a bound channel should be bound automatically
+	r.Tag(&Dial{
+		EventData: EventData{Type: Events.Dial},
+		Caller:    ChannelData{ID: "ch1"},
+		Peer:      ChannelData{ID: "ch3"},
+	})
+
+	if d := r.Dialog(NewKey(ChannelKey, "ch3")); d != "d1" {
+		t.Errorf("Expected dialed channel to be bound to '%s', got '%s'", "d1", d)
+	}
+
+	for _, id := range []string{"ch1", "ch3"} {
+		r.Tag(&ChannelDestroyed{
+			EventData: EventData{Type: Events.ChannelDestroyed},
+			Channel:   ChannelData{ID: id},
+		})
+	}
+
+	if n := len(r.Dialogs()); n != 0 {
+		t.Errorf("Expected dialog to be released after its channels were destroyed, got %d dialogs", n)
+	}
+}
+
+type dialogClient struct {
+	Client
+
+	dialogs *DialogRegistry
+}
+
+func (c *dialogClient) Dialogs() *DialogRegistry {
+	return c.dialogs
+}
+
+func TestDialogs(t *testing.T) {
+	r := NewDialogRegistry()
+
+	if d := Dialogs(&dialogClient{dialogs: r}); d != r {
+		t.Errorf("Expected the registry of the client")
+	}
+
+	if d := Dialogs(nil); d != nil {
+		t.Errorf("Expected no registry for a client without one")
+	}
+}
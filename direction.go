@@ -16,3 +16,22 @@ const (
 	// DirectionBoth indicates both the directions flowing both inward to Asterisk and outward from Asterisk.
 	DirectionBoth Direction = "both"
 )
+
+// valid indicates whether the Direction is one of the known values, or empty
+func (d Direction) valid() bool {
+	switch d {
+	case "", DirectionNone, DirectionIn, DirectionOut, DirectionBoth:
+		return true
+	}
+
+	return false
+}
+
+// orNone returns the Direction, or DirectionNone if it is empty (the default)
+func (d Direction) orNone() Direction {
+	if d == "" {
+		return DirectionNone
+	}
+
+	return d
+}
@@ -29,7 +29,7 @@ type Event interface {
 // EventData provides the basic metadata for an ARI event
 type EventData struct {
 	// Application indicates the ARI application which emitted this event
-	Application string `json:"application"`
+	Application string `json:"application,omitempty"`
 
 	// Dialog indicates a dialog to which the event has been bound
 	Dialog string `json:"dialog,omitempty"`
@@ -38,10 +38,10 @@ type EventData struct {
 	Node string `json:"asterisk_id,omitempty"`
 
 	// Timestamp indicates the time this event was generated
-	Timestamp DateTime `json:"timestamp,omitempty"`
+	Timestamp DateTime `json:"timestamp,omitzero"`
 
 	// Type is the type name of this event
-	Type string `json:"type"`
+	Type string `json:"type,omitempty"`
 }
 
 // GetApplication gets the application of the event
@@ -504,6 +504,17 @@ func (evt *ChannelCreated) GetChannelIDs() (sx []string) {
 	return
 }
 
+// Destroyed returns the channel that was destroyed by this event.
+// Used to release dialog bindings.
+func (evt *ChannelDestroyed) Destroyed() string {
+	return evt.Channel.ID
+}
+
+// HangupCause returns the cause of the hangup of the channel
+func (evt *ChannelDestroyed) HangupCause() Cause {
+	return Cause(evt.Cause)
+}
+
 // GetChannelIDs gets the channel IDs for the event
 func (evt *ChannelDialplan) GetChannelIDs() (sx []string) {
 	sx = append(sx, evt.Channel.ID)
@@ -542,6 +553,11 @@ func (evt *ChannelHangupRequest) GetChannelIDs() (sx []string) {
 	return
 }
 
+// HangupCause returns the cause of the hangup request
+func (evt *ChannelHangupRequest) HangupCause() Cause {
+	return Cause(evt.Cause)
+}
+
 // GetChannelIDs gets the channel IDs for the event
 func (evt *ChannelHold) GetChannelIDs() (sx []string) {
 	sx = append(sx, evt.Channel.ID)
@@ -566,6 +582,16 @@ func (evt *ChannelStateChange) GetChannelIDs() (sx []string) {
 	return
 }
 
+// IsAnswered indicates whether the channel has been answered
+func (evt *ChannelStateChange) IsAnswered() bool {
+	return evt.Channel.IsAnswered()
+}
+
+// IsRinging indicates whether the channel is ringing
+func (evt *ChannelStateChange) IsRinging() bool {
+	return evt.Channel.IsRinging()
+}
+
 // GetChannelIDs gets the channel IDs for the event
 func (evt *ChannelTalkingStarted) GetChannelIDs() (sx []string) {
 	sx = append(sx, evt.Channel.ID)
@@ -623,6 +649,21 @@ func (evt *Dial) GetChannelIDs() (sx []string) {
 	return
 }
 
+// Status returns the status of the dial attempt
+func (evt *Dial) Status() DialStatus {
+	return DialStatus(evt.Dialstatus)
+}
+
+// IsAnswered indicates whether the peer of the dial attempt has answered
+func (evt *Dial) IsAnswered() bool {
+	return evt.Status().IsAnswered()
+}
+
+// IsRinging indicates whether the peer of the dial attempt is ringing
+func (evt *Dial) IsRinging() bool {
+	return evt.Status().IsRinging()
+}
+
 // GetEndpointIDs gets the endpoint IDs for the event
 func (evt *EndpointStateChange) GetEndpointIDs() (sx []string) {
 	sx = append(sx, evt.Endpoint.ID())
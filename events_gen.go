@@ -12,7 +12,9 @@ import (
 type EventTypes struct {
 	All                      string
 	ApplicationMoveFailed    string
+	ApplicationRegistered    string
 	ApplicationReplaced      string
+	ApplicationUnregistered  string
 	BridgeAttendedTransfer   string
 	BridgeBlindTransfer      string
 	BridgeCreated            string
@@ -32,6 +34,8 @@ type EventTypes struct {
 	ChannelStateChange       string
 	ChannelTalkingFinished   string
 	ChannelTalkingStarted    string
+	ChannelToneDetected      string
+	ChannelTransfer          string
 	ChannelUnhold            string
 	ChannelUserevent         string
 	ChannelVarset            string
@@ -46,6 +50,7 @@ type EventTypes struct {
 	PlaybackContinuing       string
 	PlaybackFinished         string
 	PlaybackStarted          string
+	RESTResponse             string
 	RecordingFailed          string
 	RecordingFinished        string
 	RecordingStarted         string
@@ -60,7 +65,9 @@ var Events EventTypes
 func init() {
 	Events.All = "all"
 	Events.ApplicationMoveFailed = "ApplicationMoveFailed"
+	Events.ApplicationRegistered = "ApplicationRegistered"
 	Events.ApplicationReplaced = "ApplicationReplaced"
+	Events.ApplicationUnregistered = "ApplicationUnregistered"
 	Events.BridgeAttendedTransfer = "BridgeAttendedTransfer"
 	Events.BridgeBlindTransfer = "BridgeBlindTransfer"
 	Events.BridgeCreated = "BridgeCreated"
@@ -80,6 +87,8 @@ func init() {
 	Events.ChannelStateChange = "ChannelStateChange"
 	Events.ChannelTalkingFinished = "ChannelTalkingFinished"
 	Events.ChannelTalkingStarted = "ChannelTalkingStarted"
+	Events.ChannelToneDetected = "ChannelToneDetected"
+	Events.ChannelTransfer = "ChannelTransfer"
 	Events.ChannelUnhold = "ChannelUnhold"
 	Events.ChannelUserevent = "ChannelUserevent"
 	Events.ChannelVarset = "ChannelVarset"
@@ -94,6 +103,7 @@ func init() {
 	Events.PlaybackContinuing = "PlaybackContinuing"
 	Events.PlaybackFinished = "PlaybackFinished"
 	Events.PlaybackStarted = "PlaybackStarted"
+	Events.RESTResponse = "RESTResponse"
 	Events.RecordingFailed = "RecordingFailed"
 	Events.RecordingFinished = "RecordingFinished"
 	Events.RecordingStarted = "RecordingStarted"
@@ -119,10 +129,18 @@ func DecodeEvent(data []byte) (Event, error) {
 		var e ApplicationMoveFailed
 		err = json.Unmarshal(data, &e)
 		return &e, err
+	case Events.ApplicationRegistered:
+		var e ApplicationRegistered
+		err = json.Unmarshal(data, &e)
+		return &e, err
 	case Events.ApplicationReplaced:
 		var e ApplicationReplaced
 		err = json.Unmarshal(data, &e)
 		return &e, err
+	case Events.ApplicationUnregistered:
+		var e ApplicationUnregistered
+		err = json.Unmarshal(data, &e)
+		return &e, err
 	case Events.BridgeAttendedTransfer:
 		var e BridgeAttendedTransfer
 		err = json.Unmarshal(data, &e)
@@ -199,6 +217,14 @@ func DecodeEvent(data []byte) (Event, error) {
 		var e ChannelTalkingStarted
 		err = json.Unmarshal(data, &e)
 		return &e, err
+	case Events.ChannelToneDetected:
+		var e ChannelToneDetected
+		err = json.Unmarshal(data, &e)
+		return &e, err
+	case Events.ChannelTransfer:
+		var e ChannelTransfer
+		err = json.Unmarshal(data, &e)
+		return &e, err
 	case Events.ChannelUnhold:
 		var e ChannelUnhold
 		err = json.Unmarshal(data, &e)
@@ -255,6 +281,10 @@ func DecodeEvent(data []byte) (Event, error) {
 		var e PlaybackStarted
 		err = json.Unmarshal(data, &e)
 		return &e, err
+	case Events.RESTResponse:
+		var e RESTResponse
+		err = json.Unmarshal(data, &e)
+		return &e, err
 	case Events.RecordingFailed:
 		var e RecordingFailed
 		err = json.Unmarshal(data, &e)
@@ -284,6 +314,21 @@ func DecodeEvent(data []byte) (Event, error) {
 	return nil, eris.New("unhandled type: " + typer.Type)
 }
 
+// EncodeEvent converts an ARI event to its JSON encoding.  The result is
+// suitable for DecodeEvent, which will return an event equal to the original.
+// Transport-related metadata (the event Header) is not encoded.
+func EncodeEvent(e Event) ([]byte, error) {
+	if e == nil {
+		return nil, eris.New("no event")
+	}
+
+	if e.GetType() == "" {
+		return nil, eris.New("no type found")
+	}
+
+	return json.Marshal(e)
+}
+
 // ApplicationMoveFailed - "Notification that trying to move a channel to another Stasis application failed."
 type ApplicationMoveFailed struct {
 	EventData `json:",inline"`
@@ -296,6 +341,28 @@ type ApplicationMoveFailed struct {
 	Destination string      `json:"destination"`
 }
 
+// GetChannelIDs gets the channel IDs for the event
+func (evt *ApplicationMoveFailed) GetChannelIDs() (sx []string) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Channel.ID)
+	}
+
+	return
+}
+
+// ApplicationRegistered - "Notification that a Stasis app has been registered."
+type ApplicationRegistered struct {
+	EventData `json:",inline"`
+
+	// Header describes any transport-related metadata
+	Header Header `json:"-"`
+}
+
+// Keys returns the list of keys associated with this event
+func (evt *ApplicationRegistered) Keys() (sx Keys) {
+	return
+}
+
 // ApplicationReplaced - "Notification that another WebSocket has taken over for an application.An application may only be subscribed to by a single WebSocket at a time. If multiple WebSockets attempt to subscribe to the same application, the newer WebSocket wins, and the older one receives this event."
 type ApplicationReplaced struct {
 	EventData `json:",inline"`
@@ -304,6 +371,19 @@ type ApplicationReplaced struct {
 	Header Header `json:"-"`
 }
 
+// ApplicationUnregistered - "Notification that a Stasis app has been unregistered."
+type ApplicationUnregistered struct {
+	EventData `json:",inline"`
+
+	// Header describes any transport-related metadata
+	Header Header `json:"-"`
+}
+
+// Keys returns the list of keys associated with this event
+func (evt *ApplicationUnregistered) Keys() (sx Keys) {
+	return
+}
+
 // BridgeAttendedTransfer - "Notification that an attended transfer has occurred."
 type BridgeAttendedTransfer struct {
 	EventData `json:",inline"`
@@ -319,16 +399,70 @@ type BridgeAttendedTransfer struct {
 	DestinationThreewayChannel ChannelData `json:"destination_threeway_channel"` // Transferer channel that survived the threeway result
 	DestinationType            string      `json:"destination_type"`             // How the transfer was accomplished
 	IsExternal                 bool        `json:"is_external"`                  // Whether the transfer was externally initiated or not
-	ReplaceChannel             ChannelData `json:"replace_channel,omitempty"`    // The channel that is replacing transferer_first_leg in the swap
+	ReplaceChannel             ChannelData `json:"replace_channel,omitzero"`     // The channel that is replacing transferer_first_leg in the swap
 	Result                     string      `json:"result"`                       // The result of the transfer attempt
-	TransferTarget             ChannelData `json:"transfer_target,omitempty"`    // The channel that is being transferred to
-	Transferee                 ChannelData `json:"transferee,omitempty"`         // The channel that is being transferred
+	TransferTarget             ChannelData `json:"transfer_target,omitzero"`     // The channel that is being transferred to
+	Transferee                 ChannelData `json:"transferee,omitzero"`          // The channel that is being transferred
 	TransfererFirstLeg         ChannelData `json:"transferer_first_leg"`         // First leg of the transferer
 	TransfererFirstLegBridge   BridgeData  `json:"transferer_first_leg_bridge"`  // Bridge the transferer first leg is in
 	TransfererSecondLeg        ChannelData `json:"transferer_second_leg"`        // Second leg of the transferer
 	TransfererSecondLegBridge  BridgeData  `json:"transferer_second_leg_bridge"` // Bridge the transferer second leg is in
 }
 
+// GetChannelIDs gets the channel IDs for the event
+func (evt *BridgeAttendedTransfer) GetChannelIDs() (sx []string) {
+	if evt.DestinationLinkFirstLeg.ID != "" {
+		sx = append(sx, evt.DestinationLinkFirstLeg.ID)
+	}
+
+	if evt.DestinationLinkSecondLeg.ID != "" {
+		sx = append(sx, evt.DestinationLinkSecondLeg.ID)
+	}
+
+	if evt.DestinationThreewayChannel.ID != "" {
+		sx = append(sx, evt.DestinationThreewayChannel.ID)
+	}
+
+	if evt.ReplaceChannel.ID != "" {
+		sx = append(sx, evt.ReplaceChannel.ID)
+	}
+
+	if evt.TransferTarget.ID != "" {
+		sx = append(sx, evt.TransferTarget.ID)
+	}
+
+	if evt.Transferee.ID != "" {
+		sx = append(sx, evt.Transferee.ID)
+	}
+
+	if evt.TransfererFirstLeg.ID != "" {
+		sx = append(sx, evt.TransfererFirstLeg.ID)
+	}
+
+	if evt.TransfererSecondLeg.ID != "" {
+		sx = append(sx, evt.TransfererSecondLeg.ID)
+	}
+
+	return
+}
+
+// GetBridgeIDs gets the bridge IDs for the event
+func (evt *BridgeAttendedTransfer) GetBridgeIDs() (sx []string) {
+	if evt.DestinationThreewayBridge.ID != "" {
+		sx = append(sx, evt.DestinationThreewayBridge.ID)
+	}
+
+	if evt.TransfererFirstLegBridge.ID != "" {
+		sx = append(sx, evt.TransfererFirstLegBridge.ID)
+	}
+
+	if evt.TransfererSecondLegBridge.ID != "" {
+		sx = append(sx, evt.TransfererSecondLegBridge.ID)
+	}
+
+	return
+}
+
 // BridgeBlindTransfer - "Notification that a blind transfer has occurred."
 type BridgeBlindTransfer struct {
 	EventData `json:",inline"`
@@ -336,14 +470,40 @@ type BridgeBlindTransfer struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Bridge         BridgeData  `json:"bridge"`                    // The bridge being transferred
-	Channel        ChannelData `json:"channel"`                   // The channel performing the blind transfer
-	Context        string      `json:"context"`                   // The context transferred to
-	Exten          string      `json:"exten"`                     // The extension transferred to
-	IsExternal     bool        `json:"is_external"`               // Whether the transfer was externally initiated or not
-	ReplaceChannel ChannelData `json:"replace_channel,omitempty"` // The channel that is replacing transferer when the transferee(s) can not be transferred directly
-	Result         string      `json:"result"`                    // The result of the transfer attempt
-	Transferee     ChannelData `json:"transferee,omitempty"`      // The channel that is being transferred
+	Bridge         BridgeData  `json:"bridge"`                   // The bridge being transferred
+	Channel        ChannelData `json:"channel"`                  // The channel performing the blind transfer
+	Context        string      `json:"context"`                  // The context transferred to
+	Exten          string      `json:"exten"`                    // The extension transferred to
+	IsExternal     bool        `json:"is_external"`              // Whether the transfer was externally initiated or not
+	ReplaceChannel ChannelData `json:"replace_channel,omitzero"` // The channel that is replacing transferer when the transferee(s) can not be transferred directly
+	Result         string      `json:"result"`                   // The result of the transfer attempt
+	Transferee     ChannelData `json:"transferee,omitzero"`      // The channel that is being transferred
+}
+
+// GetChannelIDs gets the channel IDs for the event
+func (evt *BridgeBlindTransfer) GetChannelIDs() (sx []string) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Channel.ID)
+	}
+
+	if evt.ReplaceChannel.ID != "" {
+		sx = append(sx, evt.ReplaceChannel.ID)
+	}
+
+	if evt.Transferee.ID != "" {
+		sx = append(sx, evt.Transferee.ID)
+	}
+
+	return
+}
+
+// GetBridgeIDs gets the bridge IDs for the event
+func (evt *BridgeBlindTransfer) GetBridgeIDs() (sx []string) {
+	if evt.Bridge.ID != "" {
+		sx = append(sx, evt.Bridge.ID)
+	}
+
+	return
 }
 
 // BridgeCreated - "Notification that a bridge has been created."
@@ -385,7 +545,16 @@ type BridgeVideoSourceChanged struct {
 	Header Header `json:"-"`
 
 	Bridge           BridgeData `json:"bridge"`
-	OldVideoSourceId string     `json:"old_video_source_id,omitempty"`
+	OldVideoSourceId string     `json:"old_video_source_id,omitzero"`
+}
+
+// GetBridgeIDs gets the bridge IDs for the event
+func (evt *BridgeVideoSourceChanged) GetBridgeIDs() (sx []string) {
+	if evt.Bridge.ID != "" {
+		sx = append(sx, evt.Bridge.ID)
+	}
+
+	return
 }
 
 // ChannelCallerID - "Channel changed Caller ID."
@@ -410,6 +579,15 @@ type ChannelConnectedLine struct {
 	Channel ChannelData `json:"channel"` // The channel whose connected line has changed.
 }
 
+// GetChannelIDs gets the channel IDs for the event
+func (evt *ChannelConnectedLine) GetChannelIDs() (sx []string) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Channel.ID)
+	}
+
+	return
+}
+
 // ChannelCreated - "Notification that a channel has been created."
 type ChannelCreated struct {
 	EventData `json:",inline"`
@@ -427,9 +605,19 @@ type ChannelDestroyed struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Cause    int         `json:"cause"`     // Integer representation of the cause of the hangup
-	CauseTxt string      `json:"cause_txt"` // Text representation of the cause of the hangup
-	Channel  ChannelData `json:"channel"`
+	Cause     int         `json:"cause"`     // Integer representation of the cause of the hangup
+	CauseTxt  string      `json:"cause_txt"` // Text representation of the cause of the hangup
+	Channel   ChannelData `json:"channel"`
+	TechCause int         `json:"tech_cause,omitzero"` // Integer representation of the technology-specific off-nominal cause of the hangup.
+}
+
+// GetChannelIDs gets the channel IDs for the event
+func (evt *ChannelDestroyed) GetChannelIDs() (sx []string) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Channel.ID)
+	}
+
+	return
 }
 
 // ChannelDialplan - "Channel changed location in the dialplan."
@@ -474,9 +662,10 @@ type ChannelHangupRequest struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Cause   int         `json:"cause"`   // Integer representation of the cause of the hangup.
-	Channel ChannelData `json:"channel"` // The channel on which the hangup was requested.
-	Soft    bool        `json:"soft"`    // Whether the hangup request was a soft hangup request.
+	Cause     int         `json:"cause"`               // Integer representation of the cause of the hangup.
+	Channel   ChannelData `json:"channel"`             // The channel on which the hangup was requested.
+	Soft      bool        `json:"soft"`                // Whether the hangup request was a soft hangup request.
+	TechCause int         `json:"tech_cause,omitzero"` // Integer representation of the technology-specific off-nominal cause of the hangup.
 }
 
 // ChannelHold - "A channel initiated a media hold."
@@ -486,8 +675,8 @@ type ChannelHold struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Channel    ChannelData `json:"channel"`              // The channel that initiated the hold event.
-	Musicclass string      `json:"musicclass,omitempty"` // The music on hold class that the initiator requested.
+	Channel    ChannelData `json:"channel"`             // The channel that initiated the hold event.
+	Musicclass string      `json:"musicclass,omitzero"` // The music on hold class that the initiator requested.
 }
 
 // ChannelLeftBridge - "Notification that a channel has left a bridge."
@@ -522,6 +711,15 @@ type ChannelTalkingFinished struct {
 	Duration int         `json:"duration"` // The length of time, in milliseconds, that talking was detected on the channel
 }
 
+// GetChannelIDs gets the channel IDs for the event
+func (evt *ChannelTalkingFinished) GetChannelIDs() (sx []string) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Channel.ID)
+	}
+
+	return
+}
+
 // ChannelTalkingStarted - "Talking was detected on the channel."
 type ChannelTalkingStarted struct {
 	EventData `json:",inline"`
@@ -532,6 +730,109 @@ type ChannelTalkingStarted struct {
 	Channel ChannelData `json:"channel"` // The channel on which talking started.
 }
 
+// ChannelToneDetected - "Tone was detected on the channel."
+type ChannelToneDetected struct {
+	EventData `json:",inline"`
+
+	// Header describes any transport-related metadata
+	Header Header `json:"-"`
+
+	Channel ChannelData `json:"channel"` // The channel the tone was detected on.
+}
+
+// Keys returns the list of keys associated with this event
+func (evt *ChannelToneDetected) Keys() (sx Keys) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Key(ChannelKey, evt.Channel.ID))
+	}
+
+	return
+}
+
+// GetChannelIDs gets the channel IDs for the event
+func (evt *ChannelToneDetected) GetChannelIDs() (sx []string) {
+	if evt.Channel.ID != "" {
+		sx = append(sx, evt.Channel.ID)
+	}
+
+	return
+}
+
+// ChannelTransfer - "transfer on a channel."
+type ChannelTransfer struct {
+	EventData `json:",inline"`
+
+	// Header describes any transport-related metadata
+	Header Header `json:"-"`
+
+	ReferTo    ReferTo    `json:"refer_to"`       // Refer-To information with optionally both affected channels
+	ReferredBy ReferredBy `json:"referred_by"`    // Referred-By SIP Header according rfc3892
+	State      string     `json:"state,omitzero"` // Transfer State
+}
+
+// Keys returns the list of keys associated with this event
+func (evt *ChannelTransfer) Keys() (sx Keys) {
+	if evt.ReferTo.Bridge.ID != "" {
+		sx = append(sx, evt.Key(BridgeKey, evt.ReferTo.Bridge.ID))
+	}
+
+	if evt.ReferTo.ConnectedChannel.ID != "" {
+		sx = append(sx, evt.Key(ChannelKey, evt.ReferTo.ConnectedChannel.ID))
+	}
+
+	if evt.ReferTo.DestinationChannel.ID != "" {
+		sx = append(sx, evt.Key(ChannelKey, evt.ReferTo.DestinationChannel.ID))
+	}
+
+	if evt.ReferredBy.Bridge.ID != "" {
+		sx = append(sx, evt.Key(BridgeKey, evt.ReferredBy.Bridge.ID))
+	}
+
+	if evt.ReferredBy.ConnectedChannel.ID != "" {
+		sx = append(sx, evt.Key(ChannelKey, evt.ReferredBy.ConnectedChannel.ID))
+	}
+
+	if evt.ReferredBy.SourceChannel.ID != "" {
+		sx = append(sx, evt.Key(ChannelKey, evt.ReferredBy.SourceChannel.ID))
+	}
+
+	return
+}
+
+// GetChannelIDs gets the channel IDs for the event
+func (evt *ChannelTransfer) GetChannelIDs() (sx []string) {
+	if evt.ReferTo.ConnectedChannel.ID != "" {
+		sx = append(sx, evt.ReferTo.ConnectedChannel.ID)
+	}
+
+	if evt.ReferTo.DestinationChannel.ID != "" {
+		sx = append(sx, evt.ReferTo.DestinationChannel.ID)
+	}
+
+	if evt.ReferredBy.ConnectedChannel.ID != "" {
+		sx = append(sx, evt.ReferredBy.ConnectedChannel.ID)
+	}
+
+	if evt.ReferredBy.SourceChannel.ID != "" {
+		sx = append(sx, evt.ReferredBy.SourceChannel.ID)
+	}
+
+	return
+}
+
+// GetBridgeIDs gets the bridge IDs for the event
+func (evt *ChannelTransfer) GetBridgeIDs() (sx []string) {
+	if evt.ReferTo.Bridge.ID != "" {
+		sx = append(sx, evt.ReferTo.Bridge.ID)
+	}
+
+	if evt.ReferredBy.Bridge.ID != "" {
+		sx = append(sx, evt.ReferredBy.Bridge.ID)
+	}
+
+	return
+}
+
 // ChannelUnhold - "A channel initiated a media unhold."
 type ChannelUnhold struct {
 	EventData `json:",inline"`
@@ -549,11 +850,11 @@ type ChannelUserevent struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Bridge    BridgeData   `json:"bridge,omitempty"`   // A bridge that is signaled with the user event.
-	Channel   ChannelData  `json:"channel,omitempty"`  // A channel that is signaled with the user event.
-	Endpoint  EndpointData `json:"endpoint,omitempty"` // A endpoint that is signaled with the user event.
-	Eventname string       `json:"eventname"`          // The name of the user event.
-	Userevent interface{}  `json:"userevent"`          // Custom Userevent data
+	Bridge    BridgeData   `json:"bridge,omitzero"`   // A bridge that is signaled with the user event.
+	Channel   ChannelData  `json:"channel,omitzero"`  // A channel that is signaled with the user event.
+	Endpoint  EndpointData `json:"endpoint,omitzero"` // A endpoint that is signaled with the user event.
+	Eventname string       `json:"eventname"`         // The name of the user event.
+	Userevent interface{}  `json:"userevent"`         // Custom Userevent data
 }
 
 // ChannelVarset - "Channel variable changed."
@@ -563,9 +864,9 @@ type ChannelVarset struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Channel  ChannelData `json:"channel,omitempty"` // The channel on which the variable was set.If missing, the variable is a global variable.
-	Value    string      `json:"value"`             // The new value of the variable.
-	Variable string      `json:"variable"`          // The variable that changed.
+	Channel  ChannelData `json:"channel,omitzero"` // The channel on which the variable was set.If missing, the variable is a global variable.
+	Value    string      `json:"value"`            // The new value of the variable.
+	Variable string      `json:"variable"`         // The variable that changed.
 }
 
 // ContactInfo - "Detailed information about a contact on an endpoint."
@@ -575,10 +876,10 @@ type ContactInfo struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Aor           string `json:"aor"`                      // The Address of Record this contact belongs to.
-	ContactStatus string `json:"contact_status"`           // The current status of the contact.
-	RoundtripUsec string `json:"roundtrip_usec,omitempty"` // Current round trip time, in microseconds, for the contact.
-	Uri           string `json:"uri"`                      // The location of the contact.
+	Aor           string `json:"aor"`                     // The Address of Record this contact belongs to.
+	ContactStatus string `json:"contact_status"`          // The current status of the contact.
+	RoundtripUsec string `json:"roundtrip_usec,omitzero"` // Current round trip time, in microseconds, for the contact.
+	Uri           string `json:"uri"`                     // The location of the contact.
 }
 
 // ContactStatusChange - "The state of a contact on an endpoint has changed."
@@ -609,12 +910,12 @@ type Dial struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Caller     ChannelData `json:"caller,omitempty"`     // The calling channel.
-	Dialstatus string      `json:"dialstatus"`           // Current status of the dialing attempt to the peer.
-	Dialstring string      `json:"dialstring,omitempty"` // The dial string for calling the peer channel.
-	Forward    string      `json:"forward,omitempty"`    // Forwarding target requested by the original dialed channel.
-	Forwarded  ChannelData `json:"forwarded,omitempty"`  // Channel that the caller has been forwarded to.
-	Peer       ChannelData `json:"peer"`                 // The dialed channel.
+	Caller     ChannelData `json:"caller,omitzero"`     // The calling channel.
+	Dialstatus string      `json:"dialstatus"`          // Current status of the dialing attempt to the peer.
+	Dialstring string      `json:"dialstring,omitzero"` // The dial string for calling the peer channel.
+	Forward    string      `json:"forward,omitzero"`    // Forwarding target requested by the original dialed channel.
+	Forwarded  ChannelData `json:"forwarded,omitzero"`  // Channel that the caller has been forwarded to.
+	Peer       ChannelData `json:"peer"`                // The dialed channel.
 }
 
 // EndpointStateChange - "Endpoint state changed."
@@ -644,11 +945,11 @@ type Peer struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Address    string `json:"address,omitempty"` // The IP address of the peer.
-	Cause      string `json:"cause,omitempty"`   // An optional reason associated with the change in peer_status.
-	PeerStatus string `json:"peer_status"`       // The current state of the peer. Note that the values of the status are dependent on the underlying peer technology.
-	Port       string `json:"port,omitempty"`    // The port of the peer.
-	Time       string `json:"time,omitempty"`    // The last known time the peer was contacted.
+	Address    string `json:"address,omitzero"` // The IP address of the peer.
+	Cause      string `json:"cause,omitzero"`   // An optional reason associated with the change in peer_status.
+	PeerStatus string `json:"peer_status"`      // The current state of the peer. Note that the values of the status are dependent on the underlying peer technology.
+	Port       string `json:"port,omitzero"`    // The port of the peer.
+	Time       string `json:"time,omitzero"`    // The last known time the peer was contacted.
 }
 
 // PeerStatusChange - "The state of a peer associated with an endpoint has changed."
@@ -692,6 +993,27 @@ type PlaybackStarted struct {
 	Playback PlaybackData `json:"playback"` // Playback control object
 }
 
+// RESTResponse - "REST over Websocket Response."
+type RESTResponse struct {
+	EventData `json:",inline"`
+
+	// Header describes any transport-related metadata
+	Header Header `json:"-"`
+
+	ContentType   string `json:"content_type,omitzero"` // The Content-Type of the message body.
+	MessageBody   string `json:"message_body,omitzero"` // Response message body
+	ReasonPhrase  string `json:"reason_phrase"`         // HTTP reason phrase
+	RequestId     string `json:"request_id"`            // Opaque request id.  Will be whatever was specified on the original request.
+	StatusCode    int    `json:"status_code"`           // HTTP status code
+	TransactionId string `json:"transaction_id"`        // Opaque transaction id.  Will be whatever was specified on the original request.
+	Uri           string `json:"uri"`                   // Resource URI
+}
+
+// Keys returns the list of keys associated with this event
+func (evt *RESTResponse) Keys() (sx Keys) {
+	return
+}
+
 // RecordingFailed - "Event showing failure of a recording operation."
 type RecordingFailed struct {
 	EventData `json:",inline"`
@@ -741,7 +1063,7 @@ type StasisStart struct {
 
 	Args           []string    `json:"args"` // Arguments to the application
 	Channel        ChannelData `json:"channel"`
-	ReplaceChannel ChannelData `json:"replace_channel,omitempty"`
+	ReplaceChannel ChannelData `json:"replace_channel,omitzero"`
 }
 
 // TextMessageReceived - "A text message was received from an endpoint."
@@ -751,6 +1073,34 @@ type TextMessageReceived struct {
 	// Header describes any transport-related metadata
 	Header Header `json:"-"`
 
-	Endpoint EndpointData    `json:"endpoint,omitempty"`
+	Endpoint EndpointData    `json:"endpoint,omitzero"`
 	Message  TextMessageData `json:"message"`
 }
+
+// AdditionalParam - "Protocol specific additional parameter"
+type AdditionalParam struct {
+	ParameterName  string `json:"parameter_name"`  // Name of the parameter
+	ParameterValue string `json:"parameter_value"` // Value of the parameter
+}
+
+// ReferTo - "transfer destination requested by transferee"
+type ReferTo struct {
+	Bridge               BridgeData          `json:"bridge,omitzero"`              // Bridge connecting both destination channels
+	ConnectedChannel     ChannelData         `json:"connected_channel,omitzero"`   // Channel, connected to the to be replaced channel
+	DestinationChannel   ChannelData         `json:"destination_channel,omitzero"` // The Channel Object, that is to be replaced
+	RequestedDestination RequiredDestination `json:"requested_destination"`
+}
+
+// ReferredBy - "transfer destination requested by transferee"
+type ReferredBy struct {
+	Bridge           BridgeData  `json:"bridge,omitzero"`            // Bridge connecting both Channels
+	ConnectedChannel ChannelData `json:"connected_channel,omitzero"` // Channel, Connected to the channel, receiving the transfer request on.
+	SourceChannel    ChannelData `json:"source_channel"`             // The channel on which the refer was received
+}
+
+// RequiredDestination - "Information about the requested destination"
+type RequiredDestination struct {
+	AdditionalProtocolParams []AdditionalParam `json:"additional_protocol_params,omitzero"` // List of additional protocol specific information
+	Destination              string            `json:"destination,omitzero"`                // Destination User Part. Only for Blind transfer. Mutually exclusive to protocol_id
+	ProtocolId               string            `json:"protocol_id,omitzero"`                // the requested protocol-id by the referee in case of SIP channel, this is a SIP Call ID, Mutually exclusive to destination
+}
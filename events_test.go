@@ -0,0 +1,206 @@
+package ari
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// eventTypeNames returns the names of all generated event types
+func eventTypeNames() (names []string) {
+	v := reflect.ValueOf(Events)
+	for i := 0; i < v.NumField(); i++ {
+		if n := v.Field(i).String(); n != Events.All {
+			names = append(names, n)
+		}
+	}
+
+	return
+}
+
+// withoutNullKeys removes the "key" members which are null.  Asterisk does
+// not send keys, so those of the resources in a decoded event are encoded as
+// null.
+func withoutNullKeys(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, m := range x {
+			if k == "key" && m == nil {
+				delete(x, k)
+				continue
+			}
+
+			x[k] = withoutNullKeys(m)
+		}
+	case []any:
+		for i, m := range x {
+			x[i] = withoutNullKeys(m)
+		}
+	}
+
+	return v
+}
+
+func jsonEqual(t *testing.T, expected, actual []byte) bool {
+	var e, a any
+
+	if err := json.Unmarshal(expected, &e); err != nil {
+		t.Fatalf("failed to decode expected JSON: %v", err)
+	}
+
+	if err := json.Unmarshal(actual, &a); err != nil {
+		t.Fatalf("failed to decode actual JSON: %v", err)
+	}
+
+	return reflect.DeepEqual(e, withoutNullKeys(a))
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	for _, name := range eventTypeNames() {
+		t.Run(name, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", "events", name+".json"))
+			if err != nil {
+				t.Fatalf("missing golden sample: %v", err)
+			}
+
+			e, err := DecodeEvent(golden)
+			if err != nil {
+				t.Fatalf("failed to decode event: %v", err)
+			}
+
+			if e.GetType() != name {
+				t.Errorf("Expected type '%s', got '%s'", name, e.GetType())
+			}
+
+			data, err := EncodeEvent(e)
+			if err != nil {
+				t.Fatalf("failed to encode event: %v", err)
+			}
+
+			if !jsonEqual(t, golden, data) {
+				t.Errorf("Encoded event differs from source:\nexpected: %s\nactual:   %s", golden, data)
+			}
+
+			e2, err := DecodeEvent(data)
+			if err != nil {
+				t.Fatalf("failed to decode re-encoded event: %v", err)
+			}
+
+			if !reflect.DeepEqual(e, e2) {
+				t.Errorf("Decoded events differ:\nexpected: %#v\nactual:   %#v", e, e2)
+			}
+		})
+	}
+}
+
+func TestEncodeEventDialog(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "events", "StasisStart.json"))
+	if err != nil {
+		t.Fatalf("missing golden sample: %v", err)
+	}
+
+	e, err := DecodeEvent(golden)
+	if err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+
+	e.SetDialog("dialog1")
+
+	data, err := EncodeEvent(e)
+	if err != nil {
+		t.Fatalf("failed to encode event: %v", err)
+	}
+
+	e2, err := DecodeEvent(data)
+	if err != nil {
+		t.Fatalf("failed to decode re-encoded event: %v", err)
+	}
+
+	if e2.GetDialog() != "dialog1" {
+		t.Errorf("Expected dialog '%s', got '%s'", "dialog1", e2.GetDialog())
+	}
+
+	if e2.GetNode() != e.GetNode() {
+		t.Errorf("Expected node '%s', got '%s'", e.GetNode(), e2.GetNode())
+	}
+}
+
+func TestEncodeEventNoType(t *testing.T) {
+	if _, err := EncodeEvent(&StasisStart{}); err == nil {
+		t.Errorf("Expected error encoding event without type")
+	}
+
+	if _, err := EncodeEvent(nil); err == nil {
+		t.Errorf("Expected error encoding nil event")
+	}
+}
+
+func TestChannelTransferKeys(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "events", "ChannelTransfer.json"))
+	if err != nil {
+		t.Fatalf("missing golden sample: %v", err)
+	}
+
+	e, err := DecodeEvent(golden)
+	if err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+
+	if n := len(e.Keys().Channels()); n != 2 {
+		t.Errorf("Expected 2 channel keys, got %d", n)
+	}
+
+	if n := len(e.Keys().Bridges()); n != 1 {
+		t.Errorf("Expected 1 bridge key, got %d", n)
+	}
+
+	evt, ok := e.(*ChannelTransfer)
+	if !ok {
+		t.Fatalf("Expected *ChannelTransfer, got %T", e)
+	}
+
+	if ids := evt.GetChannelIDs(); len(ids) != 2 || ids[1] != "1709666551.42" {
+		t.Errorf("Unexpected channel IDs: %v", ids)
+	}
+}
+
+func TestChannelDataCreationtimeZone(t *testing.T) {
+	for _, ct := range []string{"2024-03-05T14:22:30.118-0500", "2024-03-05T19:22:30.118+0000", "2024-03-06T01:07:30.118+0545"} {
+		var d ChannelData
+
+		if err := json.Unmarshal([]byte(`{"id":"ch1","creationtime":"`+ct+`"}`), &d); err != nil {
+			t.Fatalf("failed to decode channel data: %v", err)
+		}
+
+		// The zone survives protobuf encoding, such as by a proxy
+		pb, err := d.Marshal()
+		if err != nil {
+			t.Fatalf("failed to encode channel data to protobuf: %v", err)
+		}
+
+		var p ChannelData
+
+		if err := p.Unmarshal(pb); err != nil {
+			t.Fatalf("failed to decode channel data from protobuf: %v", err)
+		}
+
+		data, err := json.Marshal(&p)
+		if err != nil {
+			t.Fatalf("failed to encode channel data: %v", err)
+		}
+
+		var out struct {
+			Creationtime string `json:"creationtime"`
+		}
+
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatalf("failed to decode encoded channel data: %v", err)
+		}
+
+		if out.Creationtime != ct {
+			t.Errorf("Expected creationtime '%s', got '%s'", ct, out.Creationtime)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package audiouri
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed" // for the built-in rules
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoRules indicates that there are no say rules for a language
+var ErrNoRules = errors.New("no say rules for language")
+
+// ErrNoRule indicates that no say rule matches a value
+var ErrNoRule = errors.New("no say rule matches")
+
+// maxDepth is the maximum depth of rule references, beyond which the rules
+// are considered to loop
+const maxDepth = 32
+
+//go:embed say.conf
+var builtinRules []byte
+
+// Rule is a say rule, in the manner of Asterisk's say.conf.  The rule
+// matches a key, of the form "mode:value" (ex. "num:42", "hour:2"), and
+// says it as a list of entries.
+//
+// The pattern is either literal, or, if it begins with an underscore, an
+// Asterisk extension pattern, in which X matches any digit, Z any digit but
+// 0, N any digit but 0 or 1, [...] any of a set of characters, "." one or
+// more further characters, and "!" zero or more.  Unlike Asterisk, only the
+// upper-case X, Z, and N are special, so modes may be written plainly.
+//
+// Each entry is expanded, replacing ${SAY} with the value of the key and
+// ${SAY:offset} or ${SAY:offset:length} with a substring of it (as in
+// Asterisk, a negative offset counts from the end and a negative length
+// omits that many characters from the end).  An entry of the form
+// "mode:value" is said by the rules in turn; an entry whose value is empty
+// is silent.  Any other entry is the name of a sound.
+type Rule struct {
+	Pattern string
+	Say     []string
+}
+
+// Rules is a table of say rules.  The first rule which matches a key is
+// used; if none matches, the rules of the base table, if any, are tried.
+type Rules struct {
+	// Base is the name of the table whose rules apply after these
+	Base string
+
+	Rules []Rule
+}
+
+var (
+	tablesMu sync.RWMutex
+	tables   = make(map[string]*Rules)
+)
+
+func init() {
+	if err := LoadRules(bytes.NewReader(builtinRules)); err != nil {
+		panic("invalid built-in say rules: " + err.Error())
+	}
+}
+
+// RegisterRules registers a table of say rules under the given name,
+// usually a language (ex. "en", "fr_CA"), replacing any existing table.
+func RegisterRules(name string, r *Rules) {
+	tablesMu.Lock()
+	tables[name] = r
+	tablesMu.Unlock()
+}
+
+// LoadRules parses (see ParseRules) and registers the say rules of the given configuration
+func LoadRules(r io.Reader) error {
+	list, err := ParseRules(r)
+	if err != nil {
+		return err
+	}
+
+	for name, rules := range list {
+		RegisterRules(name, rules)
+	}
+
+	return nil
+}
+
+// ParseRules parses say rules from a configuration in the format of
+// Asterisk's say.conf:  each section, named by its language, holds one rule
+// per line, as "pattern => entry, entry, ...".  A section may name its base
+// table in parentheses (ex. "[fr_CA](fr)").  Comments begin with a
+// semicolon.
+func ParseRules(r io.Reader) (map[string]*Rules, error) {
+	ret := make(map[string]*Rules)
+
+	var current *Rules
+
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), ";")
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, rest, ok := strings.Cut(line[1:], "]")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("line %d: invalid section %q", n, line)
+			}
+
+			current = new(Rules)
+
+			if base, ok := strings.CutPrefix(rest, "("); ok {
+				current.Base = strings.TrimSuffix(base, ")")
+			}
+
+			ret[name] = current
+
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: rule outside of a section", n)
+		}
+
+		pattern, say, ok := strings.Cut(line, "=>")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid rule %q", n, line)
+		}
+
+		rule := Rule{Pattern: strings.TrimSpace(pattern)}
+
+		for _, e := range strings.Split(say, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				rule.Say = append(rule.Say, e)
+			}
+		}
+
+		current.Rules = append(current.Rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// lookupRules returns the table of say rules for the given language.  A
+// regional language (ex. "fr_CA") without rules of its own uses those of
+// its base language.
+func lookupRules(language string) (*Rules, error) {
+	tablesMu.RLock()
+	defer tablesMu.RUnlock()
+
+	for l := language; l != ""; {
+		if r, ok := tables[l]; ok {
+			return r, nil
+		}
+
+		i := strings.LastIndexAny(l, "_-")
+		if i < 0 {
+			break
+		}
+
+		l = l[:i]
+	}
+
+	return nil, fmt.Errorf("%w %q", ErrNoRules, language)
+}
+
+// find returns the first rule of the table, or of its bases, which matches the given key
+func (r *Rules) find(key string) *Rule {
+	tablesMu.RLock()
+	defer tablesMu.RUnlock()
+
+	for i := 0; r != nil && i < maxDepth; i++ {
+		for j := range r.Rules {
+			if match(r.Rules[j].Pattern, key) {
+				return &r.Rules[j]
+			}
+		}
+
+		r = tables[r.Base]
+	}
+
+	return nil
+}
+
+// say appends the sounds for the given key to ret
+func (r *Rules) say(ret []string, key string, depth int) ([]string, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("say rules loop at %q", key)
+	}
+
+	mode, value, _ := strings.Cut(key, ":")
+
+	rule := r.find(key)
+	if rule == nil {
+		// A gendered mode (ex. "num-f") falls back to its plain mode
+		if base, g, ok := cutLast(mode, "-"); ok && (g == "m" || g == "f" || g == "n") {
+			return r.say(ret, base+":"+value, depth+1)
+		}
+
+		return nil, fmt.Errorf("%w %q", ErrNoRule, key)
+	}
+
+	for _, e := range rule.Say {
+		e = expand(e, value)
+
+		if m, v, ok := strings.Cut(e, ":"); ok {
+			if v == "" {
+				continue
+			}
+
+			var err error
+
+			ret, err = r.say(ret, m+":"+v, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if e != "" {
+			ret = append(ret, "sound:"+e)
+		}
+	}
+
+	return ret, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+var sayVar = regexp.MustCompile(`\$\{SAY(?::(-?\d+))?(?::(-?\d+))?\}`)
+
+// expand replaces the ${SAY} references of a rule entry with the value
+func expand(entry, value string) string {
+	return sayVar.ReplaceAllStringFunc(entry, func(ref string) string {
+		m := sayVar.FindStringSubmatch(ref)
+
+		offset, _ := strconv.Atoi(m[1]) // nolint: errcheck  an empty offset is 0
+
+		length := len(value)
+		if m[2] != "" {
+			length, _ = strconv.Atoi(m[2]) // nolint: errcheck
+		}
+
+		return substring(value, offset, length)
+	})
+}
+
+// substring returns the substring of s in the manner of Asterisk's ${VAR:offset:length}
+func substring(s string, offset, length int) string {
+	if offset < 0 {
+		offset += len(s)
+	}
+
+	offset = max(0, min(offset, len(s)))
+
+	end := offset + length
+	if length < 0 {
+		end = len(s) + length
+	}
+
+	end = max(offset, min(end, len(s)))
+
+	return s[offset:end]
+}
+
+// match indicates whether the key matches the pattern of a rule
+func match(pattern, key string) bool {
+	p, ok := strings.CutPrefix(pattern, "_")
+	if !ok {
+		return pattern == key
+	}
+
+	for p != "" {
+		switch p[0] {
+		case '.':
+			return key != ""
+		case '!':
+			return true
+		}
+
+		if key == "" {
+			return false
+		}
+
+		c := key[0]
+
+		switch p[0] {
+		case 'X':
+			ok = c >= '0' && c <= '9'
+		case 'Z':
+			ok = c >= '1' && c <= '9'
+		case 'N':
+			ok = c >= '2' && c <= '9'
+		case '[':
+			set, rest, found := strings.Cut(p[1:], "]")
+			if !found {
+				return false
+			}
+
+			ok, p = inSet(set, c), "]"+rest
+		default:
+			ok = p[0] == c
+		}
+
+		if !ok {
+			return false
+		}
+
+		p, key = p[1:], key[1:]
+	}
+
+	return key == ""
+}
+
+// inSet indicates whether the character is in the set of a pattern (ex. "2-9", "abc")
+func inSet(set string, c byte) bool {
+	for i := 0; i < len(set); i++ {
+		if i+2 < len(set) && set[i+1] == '-' {
+			if c >= set[i] && c <= set[i+2] {
+				return true
+			}
+
+			i += 2
+
+			continue
+		}
+
+		if set[i] == c {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,245 @@
+package audiouri
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CurrencyDecimals is the number of decimal places of the minor unit of
+// each currency, by ISO 4217 code, where it is not 2
+var CurrencyDecimals = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"ISK": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+// Gender is the grammatical gender of the noun which a number counts or orders
+type Gender int
+
+const (
+	// NoGender indicates that the number stands alone
+	NoGender Gender = iota
+
+	// Masculine indicates that the number agrees with a masculine noun
+	Masculine
+
+	// Feminine indicates that the number agrees with a feminine noun
+	Feminine
+
+	// Neuter indicates that the number agrees with a neuter noun
+	Neuter
+)
+
+// suffix returns the suffix of a mode for the gender
+func (g Gender) suffix() string {
+	switch g {
+	case Masculine:
+		return "-m"
+	case Feminine:
+		return "-f"
+	case Neuter:
+		return "-n"
+	default:
+		return ""
+	}
+}
+
+// Builder builds the set of media URIs to say a phrase in a language, by
+// the say rules of the language (see Rule).  The methods of a Builder may
+// be chained, and the first error encountered is reported by URIs:
+//
+//	uris, err := audiouri.Say("fr").Sound("vm-youhave").Money(1250, "EUR").URIs()
+type Builder struct {
+	rules *Rules
+	uris  []string
+	err   error
+}
+
+// Say returns a Builder for phrases in the given language (ex. "en", "es",
+// "fr_CA").  Rules are built in for English, Spanish, French, and German;
+// others may be added with RegisterRules or LoadRules.
+func Say(language string) *Builder {
+	r, err := lookupRules(language)
+
+	return &Builder{
+		rules: r,
+		err:   err,
+	}
+}
+
+// URIs returns the media URIs of the phrase, ready for play.URI
+func (b *Builder) URIs() ([]string, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.uris, nil
+}
+
+// Rule says the given value by the rules of the given mode (ex. "num", "hour")
+func (b *Builder) Rule(mode, value string) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.uris, b.err = b.rules.say(b.uris, mode+":"+value, 0)
+
+	return b
+}
+
+// Sound adds the given sounds (ex. "vm-youhave") to the phrase
+func (b *Builder) Sound(names ...string) *Builder {
+	for _, n := range names {
+		b.uris = append(b.uris, "sound:"+n)
+	}
+
+	return b
+}
+
+// URI adds the given media URIs to the phrase
+func (b *Builder) URI(uris ...string) *Builder {
+	b.uris = append(b.uris, uris...)
+
+	return b
+}
+
+// Number says the given cardinal number, agreeing with a noun of the given gender
+func (b *Builder) Number(n int, g Gender) *Builder {
+	return b.Rule("num"+g.suffix(), strconv.Itoa(n))
+}
+
+// Ordinal says the given ordinal number (ex. "third"), agreeing with a noun of the given gender
+func (b *Builder) Ordinal(n int, g Gender) *Builder {
+	return b.Rule("ord"+g.suffix(), strconv.Itoa(n))
+}
+
+// Money says the given amount, in the minor unit of the currency (ex.
+// cents), with its currency (ex. "USD"), such as "twelve dollars and fifty
+// cents".  Parts which are zero are not said, unless the whole amount is
+// zero.
+func (b *Builder) Money(amount int64, currency string) *Builder {
+	code := strings.ToLower(currency)
+
+	if amount < 0 {
+		b.Rule("word", "minus")
+
+		amount = -amount
+	}
+
+	decimals, ok := CurrencyDecimals[strings.ToUpper(currency)]
+	if !ok {
+		decimals = 2
+	}
+
+	unit := int64(1)
+	for i := 0; i < decimals; i++ {
+		unit *= 10
+	}
+
+	major, minor := amount/unit, amount%unit
+
+	if major > 0 || minor == 0 {
+		b.Rule(code, strconv.FormatInt(major, 10))
+	}
+
+	if major > 0 && minor > 0 {
+		b.Rule("word", "and")
+	}
+
+	if minor > 0 {
+		b.Rule(code+"-minor", strconv.FormatInt(minor, 10))
+	}
+
+	return b
+}
+
+// Duration says the given duration in days, hours, minutes, and seconds,
+// such as "two hours and five minutes".  Parts which are zero are not
+// said, unless the whole duration is less than a second.
+func (b *Builder) Duration(d time.Duration) *Builder {
+	d = d.Round(time.Second)
+
+	parts := []struct {
+		mode  string
+		count int64
+	}{
+		{"day", int64(d / (24 * time.Hour))},
+		{"hour", int64(d/time.Hour) % 24},
+		{"minute", int64(d/time.Minute) % 60},
+		{"second", int64(d/time.Second) % 60},
+	}
+
+	var said []int
+
+	for i, p := range parts {
+		if p.count > 0 {
+			said = append(said, i)
+		}
+	}
+
+	if len(said) == 0 {
+		return b.Rule("second", "0")
+	}
+
+	for i, p := range said {
+		if i > 0 && i == len(said)-1 {
+			b.Rule("word", "and")
+		}
+
+		b.Rule(parts[p].mode, strconv.FormatInt(parts[p].count, 10))
+	}
+
+	return b
+}
+
+// Phone says the given national telephone number, grouped as is usual in
+// the language.  Any characters other than digits (ex. spaces, dashes,
+// parentheses) are ignored.
+func (b *Builder) Phone(number string) *Builder {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+
+		return -1
+	}, number)
+
+	if digits == "" {
+		return b
+	}
+
+	return b.Rule("phone", digits)
+}
+
+// Spell spells out the given alphanumeric code, character by character.
+// Letters are said without regard to case, and spaces are skipped.
+// Characters for which the language has no rule (ex. "é") are left to
+// Asterisk, as "characters:" URIs.
+func (b *Builder) Spell(code string) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	for _, r := range strings.ToLower(code) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		if b.rules.find("char:"+string(r)) == nil {
+			b.uris = append(b.uris, "characters:"+string(r))
+			continue
+		}
+
+		b.Rule("char", string(r))
+	}
+
+	return b
+}
@@ -0,0 +1,184 @@
+package audiouri
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sounds returns the media URIs for the given space-separated sounds
+func sounds(list string) []string {
+	var ret []string
+
+	for _, s := range strings.Fields(list) {
+		ret = append(ret, "sound:"+s)
+	}
+
+	return ret
+}
+
+func TestSay(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        *Builder
+		expected string
+	}{
+		{"en zero", Say("en").Number(0, NoGender), "digits/0"},
+		{"en 105", Say("en").Number(105, NoGender), "digits/1 digits/hundred digits/5"},
+		{"en 21000", Say("en").Number(21000, NoGender), "digits/20 digits/1 digits/thousand"},
+		{"en 1000001", Say("en").Number(1000001, NoGender), "digits/1 digits/million digits/1"},
+		{"en negative", Say("en").Number(-42, NoGender), "digits/minus digits/40 digits/2"},
+		{"en ordinal", Say("en").Ordinal(23, NoGender), "digits/20 digits/h-3"},
+		{"en ordinal hundred", Say("en").Ordinal(300, NoGender), "digits/3 digits/h-hundred"},
+		{"en money", Say("en").Money(1250, "USD"), "digits/12 dollars vm-and digits/50 cents"},
+		{"en money one", Say("en").Money(101, "USD"), "digits/1 dollar vm-and digits/1 cent"},
+		{"en money zero", Say("en").Money(0, "GBP"), "digits/0 pounds"},
+		{"en duration", Say("en").Duration(2*time.Hour + 5*time.Minute), "digits/2 time/hours vm-and digits/5 time/minutes"},
+		{"en duration three", Say("en").Duration(24*time.Hour + time.Hour + 30*time.Second), "digits/1 time/day digits/1 time/hour vm-and digits/30 time/seconds"},
+		{"en phone", Say("en").Phone("(256) 555-0100"), "digits/2 digits/5 digits/6 silence/1 digits/5 digits/5 digits/5 silence/1 digits/0 digits/1 digits/0 digits/0"},
+		{"en spell", Say("en").Spell("A1-b"), "letters/a digits/1 letters/dash letters/b"},
+		{"es 21 euros", Say("es").Money(2100, "EUR"), "digits/21M euros"},
+		{"es una hora", Say("es").Duration(time.Hour), "digits/1F time/hour"},
+		{"es 21 horas", Say("es").Number(21, Feminine), "digits/21F"},
+		{"es 35", Say("es").Number(35, NoGender), "digits/30 digits/y digits/5"},
+		{"es 100", Say("es").Number(100, NoGender), "digits/100"},
+		{"es 101", Say("es").Number(101, Masculine), "digits/ciento digits/1M"},
+		{"es 200 f", Say("es").Number(200, Feminine), "digits/200F"},
+		{"es 21000", Say("es").Number(21000, NoGender), "digits/21M digits/thousand"},
+		{"es ordinal", Say("es").Ordinal(13, Feminine), "digits/h-10F digits/h-3F"},
+		{"es ordinal 100", Say("es").Ordinal(100, NoGender), "digits/h-100"},
+		{"es ordinal 142", Say("es").Ordinal(142, NoGender), "digits/h-100 digits/h-40 digits/h-2"},
+		{"es ordinal 2000", Say("es").Ordinal(2000, Feminine), "digits/2 digits/h-1000F"},
+		{"es ordinal 1305", Say("es").Ordinal(1305, NoGender), "digits/h-1000 digits/h-300 digits/h-5"},
+		{"fr 71", Say("fr").Number(71, NoGender), "digits/60 digits/et digits/11"},
+		{"fr 80", Say("fr").Number(80, NoGender), "digits/80"},
+		{"fr 97", Say("fr").Number(97, NoGender), "digits/80 digits/17"},
+		{"fr 21 f", Say("fr").Number(21, Feminine), "digits/20 digits/et digits/1F"},
+		{"fr 1001 f", Say("fr").Number(1001, Feminine), "digits/thousand digits/1F"},
+		{"fr phone", Say("fr").Phone("01 23 45 67 89"), "digits/0 digits/1 digits/20 digits/3 digits/40 digits/5 digits/60 digits/7 digits/80 digits/9"},
+		{"fr heures", Say("fr").Duration(21 * time.Hour), "digits/20 digits/et digits/1F time/hours"},
+		{"fr ordinal 1", Say("fr").Ordinal(1, Feminine), "digits/h-1F"},
+		{"fr ordinal 41", Say("fr").Ordinal(41, NoGender), "digits/40 digits/et digits/h-unieme"},
+		{"fr ordinal 42", Say("fr").Ordinal(42, NoGender), "digits/40 digits/h-2"},
+		{"fr ordinal 71", Say("fr").Ordinal(71, NoGender), "digits/60 digits/et digits/h-11"},
+		{"fr ordinal 81", Say("fr").Ordinal(81, NoGender), "digits/80 digits/h-unieme"},
+		{"fr ordinal 99", Say("fr").Ordinal(99, NoGender), "digits/80 digits/h-19"},
+		{"fr ordinal 101", Say("fr").Ordinal(101, NoGender), "digits/100 digits/h-unieme"},
+		{"fr ordinal 300", Say("fr").Ordinal(300, NoGender), "digits/3 digits/h-100"},
+		{"fr ordinal 2021", Say("fr").Ordinal(2021, NoGender), "digits/2 digits/thousand digits/h-21"},
+		{"fr_CA", Say("fr_CA").Number(2, NoGender), "digits/2"},
+		{"de 21", Say("de").Number(21, NoGender), "digits/1N digits/und digits/20"},
+		{"de 1", Say("de").Number(1, NoGender), "digits/1"},
+		{"de eine", Say("de").Number(1, Feminine), "digits/1F"},
+		{"de 101", Say("de").Number(101, NoGender), "digits/1N digits/hundred digits/1"},
+		{"de ordinal 42", Say("de").Ordinal(42, NoGender), "digits/2 digits/und digits/h-40"},
+		{"de ordinal 41", Say("de").Ordinal(41, NoGender), "digits/1N digits/und digits/h-40"},
+		{"de ordinal 100", Say("de").Ordinal(100, NoGender), "digits/1N digits/h-hundred"},
+		{"de ordinal 103", Say("de").Ordinal(103, NoGender), "digits/1N digits/hundred digits/h-3"},
+		{"de ordinal 5000", Say("de").Ordinal(5000, NoGender), "digits/5 digits/h-thousand"},
+		{"de euro", Say("de").Money(150, "EUR"), "digits/1N euro digits/und digits/50 cent"},
+		{"en yen", Say("en").Money(2500, "JPY"), "digits/2 digits/thousand digits/5 digits/hundred currency/jpy"},
+		{"en dinars", Say("en").Money(1500, "KWD"), "digits/1 currency/kwd vm-and digits/5 digits/hundred currency/kwd-minor"},
+		{"fr yen", Say("fr").Money(3, "JPY"), "digits/3 currency/jpy"},
+		{"compose", Say("en").Sound("vm-youhave").Number(3, NoGender).Sound("vm-messages"), "vm-youhave digits/3 vm-messages"},
+	}
+
+	for _, tc := range tests {
+		uris, err := tc.b.URIs()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		if expected := sounds(tc.expected); !reflect.DeepEqual(uris, expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, expected, uris)
+		}
+	}
+}
+
+func TestSayErrors(t *testing.T) {
+	if _, err := Say("xx").Number(1, NoGender).URIs(); !errors.Is(err, ErrNoRules) {
+		t.Errorf("expected ErrNoRules, got %v", err)
+	}
+
+	if _, err := Say("en").Money(100, "XYZ").URIs(); !errors.Is(err, ErrNoRule) {
+		t.Errorf("expected ErrNoRule, got %v", err)
+	}
+
+	if _, err := Say("en").Number(1234567890, NoGender).URIs(); !errors.Is(err, ErrNoRule) {
+		t.Errorf("expected ErrNoRule for an unsupported number, got %v", err)
+	}
+}
+
+func TestSpellCharacters(t *testing.T) {
+	uris, err := Say("fr").Spell("Aé1").URIs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"sound:letters/a", "characters:é", "sound:digits/1"}
+	if !reflect.DeepEqual(uris, expected) {
+		t.Errorf("expected %v, got %v", expected, uris)
+	}
+}
+
+const customRules = `
+[pirate](en) ; pirates count like the English, but have their own money
+doubloon:1 => digits/1, doubloon
+_doubloon:. => num:${SAY}, doubloons
+
+[loop]
+_num:. => num:${SAY}
+`
+
+func TestLoadRules(t *testing.T) {
+	if err := LoadRules(strings.NewReader(customRules)); err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+
+	uris, err := Say("pirate").Rule("doubloon", "40").URIs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := sounds("digits/40 doubloons"); !reflect.DeepEqual(uris, expected) {
+		t.Errorf("expected %v, got %v", expected, uris)
+	}
+
+	if _, err := Say("loop").Number(1, NoGender).URIs(); err == nil {
+		t.Error("expected error for looping rules")
+	}
+
+	if _, err := ParseRules(strings.NewReader("_num:X => digits/${SAY}")); err == nil {
+		t.Error("expected error for rule outside of a section")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		ok      bool
+	}{
+		{"num:1", "num:1", true},
+		{"num:1", "num:12", false},
+		{"_num:X", "num:7", true},
+		{"_num:X", "num:a", false},
+		{"_num:Z", "num:0", false},
+		{"_num:N", "num:1", false},
+		{"_num:[2-9]X", "num:42", true},
+		{"_num:[2-9]X", "num:12", false},
+		{"_num:0.", "num:0", false},
+		{"_num:0.", "num:05", true},
+		{"_num:0!", "num:0", true},
+		{"_char:[a-z]", "char:q", true},
+	}
+
+	for _, tc := range tests {
+		if ok := match(tc.pattern, tc.key); ok != tc.ok {
+			t.Errorf("match(%q, %q): expected %v", tc.pattern, tc.key, tc.ok)
+		}
+	}
+}
@@ -6,15 +6,19 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
 )
 
 // SupportedPlaybackPrefixes is a list of valid prefixes
 // for media URIs.
-var SupportedPlaybackPrefixes = []string{
-	"sound", "recording", "number", "digits", "characters", "tone",
-}
+var SupportedPlaybackPrefixes []string
 
 func init() {
+	for _, s := range ari.MediaSchemes {
+		SupportedPlaybackPrefixes = append(SupportedPlaybackPrefixes, string(s))
+	}
+
 	sort.Strings(SupportedPlaybackPrefixes)
 }
 
@@ -206,16 +210,9 @@ func ToneURI(name string) string {
 	return "tone:" + name
 }
 
-// Check checks if the audio URI is formatted properly
+// Check checks if the audio URI is formatted properly (see ari.ParseMediaURI)
 func Check(uri string) error {
-	l := strings.Split(uri, ":")
-	if len(l) != 2 {
-		return fmt.Errorf("audio URI %s is not formatted properly", uri)
-	}
-
-	if sort.SearchStrings(SupportedPlaybackPrefixes, l[0]) == len(SupportedPlaybackPrefixes) {
-		return fmt.Errorf("audio URI prefix %s not supported", l[0])
-	}
+	_, err := ari.ParseMediaURI(uri)
 
-	return nil
+	return err
 }
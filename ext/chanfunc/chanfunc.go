@@ -0,0 +1,210 @@
+// Package chanfunc provides typed access to the channel variables and dialplan
+// functions most commonly used in call control:  SIP headers (PJSIP_HEADER),
+// channel fields (CHANNEL), and caller ID and connected line information
+// (CALLERID and CONNECTEDLINE).
+//
+// Each helper operates on a `Variabler`, such as an `*ari.ChannelHandle`.
+// Values which Asterisk reports as missing are returned as a `*NotFoundError`.
+package chanfunc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// Variabler describes an entity whose variables may be read and written, such
+// as an `*ari.ChannelHandle`
+type Variabler interface {
+	// ID returns the unique identifier of the entity
+	ID() string
+
+	// GetVariable returns the value of a variable or dialplan function
+	GetVariable(name string) (string, error)
+
+	// SetVariable sets the value of a variable or dialplan function
+	SetVariable(name, value string) error
+}
+
+// NotFoundError indicates that the requested variable, dialplan function
+// value, or SIP header was not found on the channel
+type NotFoundError struct {
+	// Channel is the ID of the channel
+	Channel string
+
+	// Name is the variable or dialplan function expression which was requested
+	Name string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found on channel %s", e.Name, e.Channel)
+}
+
+// Unwrap returns the underlying error
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound indicates whether the given error is a *NotFoundError
+func IsNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf)
+}
+
+// coder is implemented by the request errors of the native client, which carry
+// the HTTP status code of the response
+type coder interface {
+	Code() int
+}
+
+// Get returns the value of the given variable or dialplan function expression.
+//
+// Asterisk reports a missing variable with a 404.  It reports a failure to
+// read a dialplan function (most commonly because a requested SIP header is
+// not present) as a server error, which is also mapped to a *NotFoundError.
+func Get(v Variabler, name string) (string, error) {
+	val, err := v.GetVariable(name)
+	if err == nil {
+		return val, nil
+	}
+
+	var c coder
+	if errors.As(err, &c) {
+		if c.Code() == http.StatusNotFound || (isFunction(name) && c.Code() == http.StatusInternalServerError) {
+			return "", &NotFoundError{Channel: v.ID(), Name: name, Err: err}
+		}
+	}
+
+	return "", err
+}
+
+// Set sets the value of the given variable or dialplan function expression
+func Set(v Variabler, name, value string) error {
+	return v.SetVariable(name, value)
+}
+
+// Function returns the dialplan function expression for the given function
+// and arguments, such as `CHANNEL(pjsip,remote_addr)`.  An error is returned
+// if any argument contains a character which cannot be represented in a
+// function argument (a comma, parenthesis, quote, or control character).
+func Function(fn string, args ...string) (string, error) {
+	if fn == "" || !validArg(fn) {
+		return "", errors.New("invalid dialplan function name: " + fn)
+	}
+
+	for _, a := range args {
+		if !validArg(a) {
+			return "", fmt.Errorf("invalid argument %q to dialplan function %s", a, fn)
+		}
+	}
+
+	return fn + "(" + strings.Join(args, ",") + ")", nil
+}
+
+func validArg(s string) bool {
+	for _, r := range s {
+		if r < ' ' || r == 0x7f || strings.ContainsRune(`,()"'\`, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isFunction(name string) bool {
+	return strings.HasSuffix(name, ")")
+}
+
+// getFunction returns the value of the given dialplan function
+func getFunction(v Variabler, fn string, args ...string) (string, error) {
+	name, err := Function(fn, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return Get(v, name)
+}
+
+// setFunction sets the value of the given dialplan function
+func setFunction(v Variabler, value string, fn string, args ...string) error {
+	name, err := Function(fn, args...)
+	if err != nil {
+		return err
+	}
+
+	return v.SetVariable(name, value)
+}
+
+// ChannelField returns the value of the given CHANNEL() field, with any
+// technology-specific arguments, such as `ChannelField(h, "pjsip", "remote_addr")`
+func ChannelField(v Variabler, field string, args ...string) (string, error) {
+	return getFunction(v, "CHANNEL", append([]string{field}, args...)...)
+}
+
+// SetChannelField sets the value of the given (writable) CHANNEL() field, such as "language"
+func SetChannelField(v Variabler, field, value string) error {
+	return setFunction(v, value, "CHANNEL", field)
+}
+
+// CallerID returns the caller ID name and number of the channel
+func CallerID(v Variabler) (*ari.CallerID, error) {
+	return party(v, "CALLERID")
+}
+
+// SetCallerID sets the caller ID name and number of the channel
+func SetCallerID(v Variabler, cid *ari.CallerID) error {
+	return setFunction(v, cid.String(), "CALLERID", "all")
+}
+
+// CallerIDField returns the given CALLERID() field, such as "num", "name" or "ani-num"
+func CallerIDField(v Variabler, field string) (string, error) {
+	return getFunction(v, "CALLERID", field)
+}
+
+// SetCallerIDField sets the given CALLERID() field
+func SetCallerIDField(v Variabler, field, value string) error {
+	return setFunction(v, value, "CALLERID", field)
+}
+
+// ConnectedLine returns the connected line name and number of the channel
+func ConnectedLine(v Variabler) (*ari.CallerID, error) {
+	return party(v, "CONNECTEDLINE")
+}
+
+// SetConnectedLine sets the connected line name and number of the channel
+func SetConnectedLine(v Variabler, cid *ari.CallerID) error {
+	return setFunction(v, cid.String(), "CONNECTEDLINE", "all")
+}
+
+// ConnectedLineField returns the given CONNECTEDLINE() field, such as "num" or "name"
+func ConnectedLineField(v Variabler, field string) (string, error) {
+	return getFunction(v, "CONNECTEDLINE", field)
+}
+
+// SetConnectedLineField sets the given CONNECTEDLINE() field
+func SetConnectedLineField(v Variabler, field, value string) error {
+	return setFunction(v, value, "CONNECTEDLINE", field)
+}
+
+// party reads the name and number of a CALLERID-like function.  They are
+// read separately, since the "all" form does not escape the name.
+func party(v Variabler, fn string) (*ari.CallerID, error) {
+	name, err := getFunction(v, fn, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := getFunction(v, fn, "num")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ari.CallerID{Name: name, Number: number}, nil
+}
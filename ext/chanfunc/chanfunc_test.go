@@ -0,0 +1,198 @@
+package chanfunc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return "request failed"
+}
+
+func (e *codeError) Code() int {
+	return e.code
+}
+
+// fakeChannel stores variables, failing reads of missing values in the way
+// Asterisk does
+type fakeChannel struct {
+	vars map[string]string
+}
+
+func (f *fakeChannel) ID() string {
+	return "ch1"
+}
+
+func (f *fakeChannel) GetVariable(name string) (string, error) {
+	v, ok := f.vars[name]
+	if !ok {
+		if strings.HasSuffix(name, ")") {
+			return "", &codeError{code: 500}
+		}
+
+		return "", &codeError{code: 404}
+	}
+
+	return v, nil
+}
+
+func (f *fakeChannel) SetVariable(name, value string) error {
+	f.vars[name] = value
+	return nil
+}
+
+func TestSIPHeaders(t *testing.T) {
+	ch := &fakeChannel{vars: map[string]string{"PJSIP_HEADER(read,X-Foo)": "bar"}}
+
+	if v, err := SIPHeader(ch, "X-Foo"); err != nil || v != "bar" {
+		t.Errorf("Expected 'bar', got '%s' (%v)", v, err)
+	}
+
+	_, err := SIPHeader(ch, "X-Missing")
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	var nf *NotFoundError
+	if errors.As(err, &nf) && (nf.Channel != "ch1" || nf.Name != "PJSIP_HEADER(read,X-Missing)") {
+		t.Errorf("Unexpected not found error: %+v", nf)
+	}
+
+	if err := AddSIPHeader(ch, "X-Added", "a,b (c)"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := UpdateSIPHeader(ch, "X-Added", "d"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := RemoveSIPHeader(ch, "X-*"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"PJSIP_HEADER(add,X-Added)", "PJSIP_HEADER(update,X-Added)", "PJSIP_HEADER(remove,X-*)"} {
+		if _, ok := ch.vars[name]; !ok {
+			t.Errorf("Expected variable %s to be set", name)
+		}
+	}
+
+	if ch.vars["PJSIP_HEADER(add,X-Added)"] != "a,b (c)" {
+		t.Errorf("Expected value to be passed unaltered, got '%s'", ch.vars["PJSIP_HEADER(add,X-Added)"])
+	}
+
+	for _, bad := range []string{"", "X-Foo)", "X Foo", "X-Foo,Bar", "X-\"Foo\""} {
+		if err := AddSIPHeader(ch, bad, "x"); err == nil {
+			t.Errorf("Expected error for header name '%s'", bad)
+		}
+	}
+}
+
+func TestChannelField(t *testing.T) {
+	ch := &fakeChannel{vars: map[string]string{"CHANNEL(pjsip,remote_addr)": "10.0.0.5:5060"}}
+
+	if v, err := ChannelField(ch, "pjsip", "remote_addr"); err != nil || v != "10.0.0.5:5060" {
+		t.Errorf("Expected remote address, got '%s' (%v)", v, err)
+	}
+
+	if err := SetChannelField(ch, "language", "fr"); err != nil || ch.vars["CHANNEL(language)"] != "fr" {
+		t.Errorf("Expected language to be set, got '%s' (%v)", ch.vars["CHANNEL(language)"], err)
+	}
+
+	if _, err := ChannelField(ch, "bad,field"); err == nil || IsNotFound(err) {
+		t.Errorf("Expected invalid argument error, got %v", err)
+	}
+}
+
+func TestParties(t *testing.T) {
+	ch := &fakeChannel{vars: map[string]string{
+		"CALLERID(name)":      `Jane "JD" Doe`,
+		"CALLERID(num)":       "100",
+		"CONNECTEDLINE(name)": "",
+		"CONNECTEDLINE(num)":  "200",
+	}}
+
+	cid, err := CallerID(ch)
+	if err != nil || cid.Name != `Jane "JD" Doe` || cid.Number != "100" {
+		t.Errorf("Unexpected caller ID: %v (%v)", cid, err)
+	}
+
+	cl, err := ConnectedLine(ch)
+	if err != nil || cl.Name != "" || cl.Number != "200" {
+		t.Errorf("Unexpected connected line: %v (%v)", cl, err)
+	}
+
+	if err := SetCallerID(ch, &ari.CallerID{Name: `Jane "JD"`, Number: "100"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if v := ch.vars["CALLERID(all)"]; v != `"Jane \"JD\"" <100>` {
+		t.Errorf("Unexpected CALLERID(all): %s", v)
+	}
+
+	if err := SetConnectedLineField(ch, "num", "300"); err != nil || ch.vars["CONNECTEDLINE(num)"] != "300" {
+		t.Errorf("Expected connected line number to be set (%v)", err)
+	}
+}
+
+func TestGetVariableNotFound(t *testing.T) {
+	ch := &fakeChannel{vars: map[string]string{}}
+
+	if _, err := Get(ch, "MISSING"); !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	if err := Set(ch, "FOUND", "1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if v, err := Get(ch, "FOUND"); err != nil || v != "1" {
+		t.Errorf("Expected '1', got '%s' (%v)", v, err)
+	}
+}
+
+func TestTalkDetect(t *testing.T) {
+	ch := &fakeChannel{vars: map[string]string{}}
+
+	if _, enabled, err := TalkDetect(ch); err != nil || enabled {
+		t.Errorf("Expected talk detection to be disabled, got %v (%v)", enabled, err)
+	}
+
+	if err := SetTalkDetect(ch, ""); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if v, ok := ch.vars["TALK_DETECT(set)"]; !ok || v != "" {
+		t.Errorf("Expected talk detection to be set with defaults, got '%s'", v)
+	}
+
+	if params, enabled, err := TalkDetect(ch); err != nil || !enabled || params != "2500,256" {
+		t.Errorf("Expected default talk detection, got '%s' %v (%v)", params, enabled, err)
+	}
+
+	if err := SetTalkDetect(ch, "1200,300"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if params, _, _ := TalkDetect(ch); params != "1200,300" {
+		t.Errorf("Expected '1200,300', got '%s'", params)
+	}
+
+	if err := RemoveTalkDetect(ch); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, ok := ch.vars["TALK_DETECT(remove)"]; !ok {
+		t.Errorf("Expected talk detection to be removed")
+	}
+
+	if _, enabled, err := TalkDetect(ch); err != nil || enabled {
+		t.Errorf("Expected talk detection to be disabled, got %v (%v)", enabled, err)
+	}
+}
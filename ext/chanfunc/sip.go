@@ -0,0 +1,103 @@
+package chanfunc
+
+import (
+	"errors"
+	"strings"
+)
+
+// SIPHeaderAction is an action of the PJSIP_HEADER dialplan function
+type SIPHeaderAction string
+
+const (
+	// SIPHeaderRead reads a header of the request which created the channel
+	SIPHeaderRead SIPHeaderAction = "read"
+
+	// SIPHeaderAdd adds a header to the outbound request of the channel
+	SIPHeaderAdd SIPHeaderAction = "add"
+
+	// SIPHeaderUpdate replaces a header previously added to the outbound request of the channel
+	SIPHeaderUpdate SIPHeaderAction = "update"
+
+	// SIPHeaderRemove removes a header previously added to the outbound request of the channel
+	SIPHeaderRemove SIPHeaderAction = "remove"
+)
+
+// tokenChars are the characters, other than alphanumerics, which may appear
+// in a SIP header name (an RFC 3261 token, less the quote, which may not
+// appear in a dialplan function argument)
+const tokenChars = "-.!%*_+`~"
+
+// SIPHeaderVariable returns the name of the variable by which the given
+// action is applied to the named SIP header, such as
+// `PJSIP_HEADER(add,X-Foo)`.  It may be used directly in the Variables of an
+// OriginateRequest, to add headers to the outbound INVITE.
+func SIPHeaderVariable(action SIPHeaderAction, header string) (string, error) {
+	if !validHeaderName(header) {
+		return "", errors.New("invalid SIP header name: " + header)
+	}
+
+	switch action {
+	case SIPHeaderRead, SIPHeaderAdd, SIPHeaderUpdate, SIPHeaderRemove:
+	default:
+		return "", errors.New("invalid SIP header action: " + string(action))
+	}
+
+	return Function("PJSIP_HEADER", string(action), header)
+}
+
+// SIPHeader returns the value of the named header of the SIP request which
+// created the channel.  A missing header is returned as a *NotFoundError.
+func SIPHeader(v Variabler, header string) (string, error) {
+	name, err := SIPHeaderVariable(SIPHeaderRead, header)
+	if err != nil {
+		return "", err
+	}
+
+	return Get(v, name)
+}
+
+// AddSIPHeader adds the named header to the outbound SIP request of the
+// channel.  Headers may only be added before the request is sent, such as
+// from a pre-dial handler or before a created channel is dialed.
+func AddSIPHeader(v Variabler, header, value string) error {
+	return setSIPHeader(v, SIPHeaderAdd, header, value)
+}
+
+// UpdateSIPHeader replaces the value of the named header previously added to
+// the outbound SIP request of the channel
+func UpdateSIPHeader(v Variabler, header, value string) error {
+	return setSIPHeader(v, SIPHeaderUpdate, header, value)
+}
+
+// RemoveSIPHeader removes the named header previously added to the outbound
+// SIP request of the channel.  The header name may end in `*` to remove all
+// headers with the given prefix.
+func RemoveSIPHeader(v Variabler, header string) error {
+	return setSIPHeader(v, SIPHeaderRemove, header, "")
+}
+
+func setSIPHeader(v Variabler, action SIPHeaderAction, header, value string) error {
+	name, err := SIPHeaderVariable(action, header)
+	if err != nil {
+		return err
+	}
+
+	return v.SetVariable(name, value)
+}
+
+func validHeaderName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune(tokenChars, r):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
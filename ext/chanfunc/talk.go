@@ -0,0 +1,52 @@
+package chanfunc
+
+// TalkDetectVariable is the channel variable in which SetTalkDetect records
+// the parameters of the talk detection which it enables.  The TALK_DETECT
+// dialplan function cannot be read, so this is how those which enable talk
+// detection temporarily discover whether, and how, to restore it.
+const TalkDetectVariable = "ARI_TALK_DETECT"
+
+// defaultTalkDetect are the parameters of talk detection which Asterisk
+// applies when none are given
+const defaultTalkDetect = "2500,256"
+
+// TalkDetect returns the parameters of the talk detection enabled on the
+// channel by SetTalkDetect, and whether it is enabled
+func TalkDetect(v Variabler) (params string, enabled bool, err error) {
+	params, err = Get(v, TalkDetectVariable)
+	if IsNotFound(err) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return params, params != "", nil
+}
+
+// SetTalkDetect enables talk detection (TALK_DETECT) on the channel.  The
+// parameters are the duration of silence, in milliseconds, after which
+// talking is considered to have stopped and the average magnitude per
+// sample above which talking is detected, such as "2500,256".  If they are
+// empty, Asterisk's defaults are used.
+func SetTalkDetect(v Variabler, params string) error {
+	if err := v.SetVariable("TALK_DETECT(set)", params); err != nil {
+		return err
+	}
+
+	if params == "" {
+		params = defaultTalkDetect
+	}
+
+	return v.SetVariable(TalkDetectVariable, params)
+}
+
+// RemoveTalkDetect disables talk detection on the channel
+func RemoveTalkDetect(v Variabler) error {
+	if err := v.SetVariable("TALK_DETECT(remove)", ""); err != nil {
+		return err
+	}
+
+	return v.SetVariable(TalkDetectVariable, "")
+}
@@ -0,0 +1,331 @@
+package chanmon
+
+import (
+	"sync"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// monitoredEvents lists the channel events which update the monitor
+var monitoredEvents = []string{
+	ari.Events.ChannelStateChange,
+	ari.Events.ChannelVarset,
+	ari.Events.ChannelCallerID,
+	ari.Events.ChannelConnectedLine,
+	ari.Events.ChannelDialplan,
+	ari.Events.ChannelHold,
+	ari.Events.ChannelUnhold,
+	ari.Events.ChannelTalkingStarted,
+	ari.Events.ChannelTalkingFinished,
+	ari.Events.ChannelDestroyed,
+}
+
+// Snapshot is a consistent view of the monitored channel at a point in time:
+// its data along with the state derived from its events
+type Snapshot struct {
+	// Data is the channel data
+	Data *ari.ChannelData
+
+	// AnsweredAt is the time at which the channel was seen to be answered.
+	// It is zero if the channel has not been answered, and is approximate if
+	// the channel was already answered when the monitor was created.
+	AnsweredAt time.Time
+
+	// OnHold indicates that the channel has been placed on hold
+	OnHold bool
+
+	// MusicClass is the music on hold class requested by the hold, if any
+	MusicClass string
+
+	// Talking indicates that talking is detected on the channel.  Talk
+	// detection must be enabled on the channel (TALK_DETECT) for this to be
+	// reported.
+	Talking bool
+
+	// Destroyed indicates that the channel has been destroyed
+	Destroyed bool
+
+	// Cause is the hangup cause of the channel, once it has been destroyed
+	Cause ari.Cause
+}
+
+// Monitor is a channel monitor, which maintains channel data.  It monitors an
+// ARI channel for events and keeps an internal cache of the channel's data.
+type Monitor struct {
+	h *ari.ChannelHandle
+
+	snap Snapshot
+
+	sub    ari.Subscription
+	closed bool
+
+	watchers  []chan *ari.ChannelData
+	watcherMu sync.Mutex
+
+	mu sync.Mutex
+}
+
+// New returns a new channel monitor
+func New(h *ari.ChannelHandle) *Monitor {
+	sub := h.Subscribe(monitoredEvents...)
+
+	m := &Monitor{
+		h:   h,
+		sub: sub,
+	}
+
+	// Load the initial channel data before monitoring events, so that the
+	// data of the events always supersedes it.  This may fail if the channel
+	// has only been staged, so ignore errors here.
+	data, _ := h.Data() // nolint
+	m.update(data, func(s *Snapshot) {
+		if data.IsAnswered() {
+			s.AnsweredAt = time.Now()
+		}
+	})
+
+	// Monitor channel events to keep data in sync
+	go m.monitor()
+
+	return m
+}
+
+func (m *Monitor) monitor() {
+	defer m.Close()
+
+	for v := range m.sub.Events() {
+		if v == nil {
+			continue
+		}
+
+		switch e := v.(type) {
+		case *ari.ChannelStateChange:
+			m.update(&e.Channel, func(s *Snapshot) {
+				if e.Channel.IsAnswered() && s.AnsweredAt.IsZero() {
+					s.AnsweredAt = eventTime(e.Timestamp)
+				}
+			})
+		case *ari.ChannelVarset:
+			m.updateVariable(e)
+		case *ari.ChannelCallerID:
+			m.update(&e.Channel, nil)
+		case *ari.ChannelConnectedLine:
+			m.update(&e.Channel, nil)
+		case *ari.ChannelDialplan:
+			m.update(&e.Channel, nil)
+		case *ari.ChannelHold:
+			m.update(&e.Channel, func(s *Snapshot) {
+				s.OnHold = true
+				s.MusicClass = e.Musicclass
+			})
+		case *ari.ChannelUnhold:
+			m.update(&e.Channel, func(s *Snapshot) {
+				s.OnHold = false
+				s.MusicClass = ""
+			})
+		case *ari.ChannelTalkingStarted:
+			m.update(&e.Channel, func(s *Snapshot) {
+				s.Talking = true
+			})
+		case *ari.ChannelTalkingFinished:
+			m.update(&e.Channel, func(s *Snapshot) {
+				s.Talking = false
+			})
+		case *ari.ChannelDestroyed:
+			m.update(&e.Channel, func(s *Snapshot) {
+				s.Talking = false
+				s.Destroyed = true
+				s.Cause = e.HangupCause()
+			})
+
+			return // channel is destroyed; there will be no more events
+		}
+	}
+}
+
+// eventTime returns the time of an event, or the current time if the event has no timestamp
+func eventTime(ts ari.DateTime) time.Time {
+	if t := time.Time(ts); !t.IsZero() {
+		return t
+	}
+
+	return time.Now()
+}
+
+// updateVariable records the change of a channel variable
+func (m *Monitor) updateVariable(e *ari.ChannelVarset) {
+	m.mu.Lock()
+
+	var data ari.ChannelData
+
+	switch {
+	case e.Channel.ID != "":
+		data = e.Channel
+	case m.snap.Data != nil:
+		data = *m.snap.Data
+	default:
+		// a global variable, with no channel data from which to build a snapshot
+		m.mu.Unlock()
+		return
+	}
+
+	// Events only carry those channel variables which Asterisk is configured
+	// to include, so the variable is added to those already known.
+	vars := make(map[string]string)
+
+	if m.snap.Data != nil {
+		for k, v := range m.snap.Data.ChannelVars {
+			vars[k] = v
+		}
+	}
+
+	for k, v := range data.ChannelVars {
+		vars[k] = v
+	}
+
+	vars[e.Variable] = e.Value
+	data.ChannelVars = vars
+
+	m.mu.Unlock()
+
+	m.update(&data, nil)
+}
+
+// update stores the given channel data, applies the given change to the
+// derived state, and distributes the new data to any watchers
+func (m *Monitor) update(data *ari.ChannelData, change func(s *Snapshot)) {
+	if data == nil || data.ID == "" {
+		return
+	}
+
+	// Populate the channel key in the channel data, since Asterisk does not populate this field.
+	if data.Key == nil {
+		data.Key = m.h.Key()
+	}
+
+	m.mu.Lock()
+
+	// Retain any known channel variables which the new data does not carry
+	if data.ChannelVars == nil && m.snap.Data != nil {
+		data.ChannelVars = m.snap.Data.ChannelVars
+	}
+
+	m.snap.Data = data
+
+	if change != nil {
+		change(&m.snap)
+	}
+
+	m.mu.Unlock()
+
+	// Distribute new data to any watchers
+	m.watcherMu.Lock()
+
+	for _, w := range m.watchers {
+		select {
+		case w <- data:
+		default:
+		}
+	}
+
+	m.watcherMu.Unlock()
+}
+
+// Data returns the current channel data
+func (m *Monitor) Data() *ari.ChannelData {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.snap.Data
+}
+
+// Snapshot returns the current channel data along with the state derived from
+// the channel's events
+func (m *Monitor) Snapshot() Snapshot {
+	if m == nil {
+		return Snapshot{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.snap
+}
+
+// Handle returns the ChannelHandle which was used to create the channel Monitor.
+func (m *Monitor) Handle() *ari.ChannelHandle {
+	if m == nil {
+		return nil
+	}
+
+	return m.h
+}
+
+// Key returns the key of the monitored channel
+func (m *Monitor) Key() *ari.Key {
+	if m == nil || m.h == nil {
+		return nil
+	}
+
+	return m.h.Key()
+}
+
+// Watch returns a channel on which channel data will be returned when events
+// occur.  This channel will be closed when the channel or the monitor is
+// destroyed.
+//
+// NOTE:  the user should NEVER close this channel directly.
+func (m *Monitor) Watch() <-chan *ari.ChannelData {
+	ch := make(chan *ari.ChannelData)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		close(ch)
+		return ch
+	}
+
+	m.watcherMu.Lock()
+	m.watchers = append(m.watchers, ch)
+	m.watcherMu.Unlock()
+
+	return ch
+}
+
+// Close shuts down a channel monitor
+func (m *Monitor) Close() {
+	if m == nil {
+		return
+	}
+
+	{
+		m.mu.Lock()
+
+		if !m.closed {
+			m.closed = true
+			if m.sub != nil {
+				m.sub.Cancel()
+			}
+		}
+
+		m.mu.Unlock()
+	}
+
+	{
+		m.watcherMu.Lock()
+
+		for _, w := range m.watchers {
+			close(w)
+		}
+
+		m.watchers = nil
+
+		m.watcherMu.Unlock()
+	}
+}
@@ -0,0 +1,162 @@
+package chanmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+type monitorTest struct {
+	key    *ari.Key
+	events chan ari.Event
+	sub    *arimocks.Subscription
+	m      *Monitor
+}
+
+func newMonitorTest(t *testing.T, initial *ari.ChannelData) *monitorTest {
+	mt := &monitorTest{
+		key:    ari.NewKey(ari.ChannelKey, "ch1"),
+		events: make(chan ari.Event),
+		sub:    &arimocks.Subscription{},
+	}
+
+	mt.sub.On("Events").Return((<-chan ari.Event)(mt.events))
+	mt.sub.On("Cancel").Return()
+
+	args := []interface{}{mt.key}
+	for _, n := range monitoredEvents {
+		args = append(args, n)
+	}
+
+	c := &arimocks.Channel{}
+	c.On("Subscribe", args...).Return(mt.sub)
+	c.On("Data", mt.key).Return(initial, nil)
+
+	mt.m = New(ari.NewChannelHandle(mt.key, c, nil))
+
+	t.Cleanup(mt.m.Close)
+
+	return mt
+}
+
+// waitFor waits for the snapshot of the monitor to satisfy the condition
+func (mt *monitorTest) waitFor(t *testing.T, desc string, cond func(s Snapshot) bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for !cond(mt.m.Snapshot()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	mt := newMonitorTest(t, &ari.ChannelData{
+		ID:          "ch1",
+		State:       "Ringing",
+		ChannelVars: map[string]string{"X_TENANT": "acme"},
+	})
+
+	if d := mt.m.Data(); d == nil || d.ID != "ch1" || !d.Key.Match(mt.key) {
+		t.Fatalf("unexpected initial data %v", d)
+	}
+
+	if s := mt.m.Snapshot(); !s.AnsweredAt.IsZero() {
+		t.Errorf("expected ringing channel to be unanswered")
+	}
+
+	answered := time.Date(2024, 3, 5, 14, 22, 31, 0, time.UTC)
+
+	mt.events <- &ari.ChannelStateChange{
+		EventData: ari.EventData{Type: ari.Events.ChannelStateChange, Timestamp: ari.DateTime(answered)},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+	}
+	mt.waitFor(t, "answer", func(s Snapshot) bool { return s.Data.IsAnswered() })
+
+	s := mt.m.Snapshot()
+	if !s.AnsweredAt.Equal(answered) {
+		t.Errorf("expected answer time %v, got %v", answered, s.AnsweredAt)
+	}
+
+	if s.Data.ChannelVars["X_TENANT"] != "acme" {
+		t.Errorf("expected channel variables to be retained, got %v", s.Data.ChannelVars)
+	}
+
+	mt.events <- &ari.ChannelVarset{
+		EventData: ari.EventData{Type: ari.Events.ChannelVarset},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+		Variable:  "FOO",
+		Value:     "bar",
+	}
+	mt.waitFor(t, "variable", func(s Snapshot) bool { return s.Data.ChannelVars["FOO"] == "bar" })
+
+	if v := mt.m.Data().ChannelVars["X_TENANT"]; v != "acme" {
+		t.Errorf("expected existing variable to be retained, got %q", v)
+	}
+
+	mt.events <- &ari.ChannelHold{
+		EventData:  ari.EventData{Type: ari.Events.ChannelHold},
+		Channel:    ari.ChannelData{ID: "ch1", State: "Up"},
+		Musicclass: "jazz",
+	}
+	mt.waitFor(t, "hold", func(s Snapshot) bool { return s.OnHold && s.MusicClass == "jazz" })
+
+	mt.events <- &ari.ChannelUnhold{
+		EventData: ari.EventData{Type: ari.Events.ChannelUnhold},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+	}
+	mt.waitFor(t, "unhold", func(s Snapshot) bool { return !s.OnHold })
+
+	mt.events <- &ari.ChannelTalkingStarted{
+		EventData: ari.EventData{Type: ari.Events.ChannelTalkingStarted},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+	}
+	mt.waitFor(t, "talking", func(s Snapshot) bool { return s.Talking })
+
+	mt.events <- &ari.ChannelTalkingFinished{
+		EventData: ari.EventData{Type: ari.Events.ChannelTalkingFinished},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+	}
+	mt.waitFor(t, "talking finished", func(s Snapshot) bool { return !s.Talking })
+
+	w := mt.m.Watch()
+
+	mt.events <- &ari.ChannelDestroyed{
+		EventData: ari.EventData{Type: ari.Events.ChannelDestroyed},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+		Cause:     int(ari.CauseUserBusy),
+	}
+
+	// the watch should be closed once the channel is destroyed
+	for range w {
+	}
+
+	s = mt.m.Snapshot()
+	if !s.Destroyed || s.Cause != ari.CauseUserBusy {
+		t.Errorf("expected destroyed channel with busy cause, got %v / %v", s.Destroyed, s.Cause)
+	}
+
+	if _, ok := <-mt.m.Watch(); ok {
+		t.Errorf("expected watch of closed monitor to be closed")
+	}
+}
+
+func TestMonitorStaged(t *testing.T) {
+	mt := newMonitorTest(t, nil)
+
+	if d := mt.m.Data(); d != nil {
+		t.Errorf("expected no data for staged channel, got %v", d)
+	}
+
+	mt.events <- &ari.ChannelStateChange{
+		EventData: ari.EventData{Type: ari.Events.ChannelStateChange},
+		Channel:   ari.ChannelData{ID: "ch1", State: "Up"},
+	}
+	mt.waitFor(t, "data", func(s Snapshot) bool { return s.Data != nil && !s.AnsweredAt.IsZero() })
+}
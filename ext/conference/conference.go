@@ -0,0 +1,787 @@
+// Package conference provides conference rooms, built on mixing bridges, with
+// participant roles, muting, talker detection, and a watchable roster.
+package conference
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/chanfunc"
+	"github.com/CyCoreSystems/ari/v6/rid"
+)
+
+var (
+	// ErrClosed indicates that the room has been closed
+	ErrClosed = errors.New("conference is closed")
+
+	// ErrLocked indicates that the room is locked to all but moderators
+	ErrLocked = errors.New("conference is locked")
+
+	// ErrFull indicates that the room has reached its maximum number of participants
+	ErrFull = errors.New("conference is full")
+
+	// ErrNotParticipant indicates that the channel is not a participant of the room
+	ErrNotParticipant = errors.New("channel is not a participant")
+)
+
+// Role describes the role of a participant in a conference
+type Role int
+
+const (
+	// Speaker is an ordinary participant, who may speak and listen
+	Speaker Role = iota
+
+	// Moderator is a participant who may join a locked room
+	Moderator
+
+	// Listener is a participant who may only listen.  Listeners are always muted.
+	Listener
+)
+
+// String implements fmt.Stringer
+func (r Role) String() string {
+	switch r {
+	case Speaker:
+		return "speaker"
+	case Moderator:
+		return "moderator"
+	case Listener:
+		return "listener"
+	}
+
+	return "unknown"
+}
+
+// Participant describes a participant of a conference
+type Participant struct {
+	// ID is the ID of the participant's channel
+	ID string
+
+	// Role is the role of the participant
+	Role Role
+
+	// Muted indicates that the participant's audio is not heard by the room
+	Muted bool
+
+	// Talking indicates that the participant is talking.  It is only
+	// reported if talk detection is enabled (see NoTalkDetection).
+	Talking bool
+
+	// JoinedAt is the time at which the participant joined the room
+	JoinedAt time.Time
+}
+
+// Roster describes the state of a conference room
+type Roster struct {
+	// Participants lists the participants of the room, in the order in which they joined
+	Participants []Participant
+
+	// Locked indicates that the room is locked to all but moderators
+	Locked bool
+
+	// Recording indicates that the room is being recorded
+	Recording bool
+
+	// Closed indicates that the room has been closed
+	Closed bool
+}
+
+// participant is a participant of a room, along with its channel
+type participant struct {
+	Participant
+
+	h   *ari.ChannelHandle
+	sub ari.Subscription
+
+	// joining indicates that the participant's channel is still being added
+	// to the room.  Joining participants hold their place in the room, but
+	// they are not yet listed in the roster.
+	joining bool
+}
+
+// release stops the talk detection of the participant, if it was enabled.
+// The channel may already be gone, so errors are ignored.
+func (p *participant) release() {
+	if p.sub == nil {
+		return
+	}
+
+	p.sub.Cancel()
+
+	_ = chanfunc.RemoveTalkDetect(p.h) // nolint
+}
+
+// Room is a conference room
+type Room struct {
+	client ari.Client
+	o      *Options
+
+	bridge *ari.BridgeHandle
+	sub    ari.Subscription
+
+	// mu guards the state of the room.  It is never held across requests to
+	// Asterisk, so that the bridge events are not held up behind them.
+	mu           sync.Mutex
+	participants []*participant
+	locked       bool
+	recording    *ari.LiveRecordingHandle
+	closed       bool
+	watchers     []chan Roster
+
+	// mohMu serializes the changes of the music on hold of the bridge
+	mohMu sync.Mutex
+	moh   bool
+
+	// recordMu serializes the starting and stopping of the recording
+	recordMu sync.Mutex
+}
+
+// New creates a new conference room, backed by a mixing bridge with the
+// given key.  If the key is nil, a new bridge ID is generated.
+func New(client ari.Client, key *ari.Key, opts ...OptionFunc) (*Room, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	if key == nil {
+		key = ari.NewKey(ari.BridgeKey, rid.New(rid.Bridge))
+	}
+
+	br, err := client.Bridge().Create(key, "mixing", key.ID)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create conference bridge")
+	}
+
+	r := &Room{
+		client: client,
+		o:      o,
+		bridge: br,
+		sub:    br.Subscribe(ari.Events.ChannelLeftBridge, ari.Events.BridgeDestroyed),
+	}
+
+	go r.monitor()
+
+	return r, nil
+}
+
+// monitor watches the bridge for participants which leave it, such as by hanging up
+func (r *Room) monitor() {
+	for v := range r.sub.Events() {
+		switch e := v.(type) {
+		case *ari.ChannelLeftBridge:
+			r.mu.Lock()
+			p := r.remove(e.Channel.ID)
+			r.mu.Unlock()
+
+			r.left(p)
+		case *ari.BridgeDestroyed:
+			r.mu.Lock()
+			gone := r.shutdown()
+			r.mu.Unlock()
+
+			release(gone)
+
+			return
+		}
+	}
+}
+
+// Bridge returns the bridge of the room
+func (r *Room) Bridge() *ari.BridgeHandle {
+	return r.bridge
+}
+
+// Join adds the channel to the room with the given role.  A locked room may
+// only be joined by moderators.
+func (r *Room) Join(ch *ari.ChannelHandle, role Role) error {
+	p, err := r.admit(ch, role)
+	if err != nil || p == nil {
+		return err
+	}
+
+	sub, err := r.enter(p)
+	if err != nil {
+		r.mu.Lock()
+		r.remove(p.ID)
+		r.mu.Unlock()
+
+		return err
+	}
+
+	r.mu.Lock()
+
+	if r.find(p.ID) != p {
+		// The participant left, or the room was closed, while it was joining
+		closed := r.closed
+		r.mu.Unlock()
+
+		release([]*participant{{h: ch, sub: sub}})
+
+		if closed {
+			return ErrClosed
+		}
+
+		return nil
+	}
+
+	p.sub = sub
+	p.joining = false
+
+	r.notify()
+	r.mu.Unlock()
+
+	if sub != nil {
+		go r.watchTalking(p, sub)
+	}
+
+	r.announce(r.o.joinSound)
+	r.updateMOH()
+
+	return nil
+}
+
+// admit checks that the channel may join the room and, if so, holds its
+// place in the room while it joins.  If the channel is already a
+// participant, no participant is returned.
+func (r *Room) admit(ch *ari.ChannelHandle, role Role) (*participant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.closed:
+		return nil, ErrClosed
+	case r.locked && role != Moderator:
+		return nil, ErrLocked
+	case r.o.maxParticipants > 0 && len(r.participants) >= r.o.maxParticipants:
+		return nil, ErrFull
+	case r.find(ch.ID()) != nil:
+		return nil, nil
+	}
+
+	p := &participant{
+		Participant: Participant{
+			ID:       ch.ID(),
+			Role:     role,
+			Muted:    role == Listener,
+			JoinedAt: time.Now(),
+		},
+		h:       ch,
+		joining: true,
+	}
+
+	r.participants = append(r.participants, p)
+
+	return p, nil
+}
+
+// enter adds the channel of the joining participant to the bridge, mutes
+// it if it is a listener, and enables its talk detection.  It returns the
+// subscription to the talking events of the participant, if there is one.
+func (r *Room) enter(p *participant) (ari.Subscription, error) {
+	if err := r.bridge.AddChannel(p.ID); err != nil {
+		return nil, eris.Wrap(err, "failed to add channel to conference bridge")
+	}
+
+	if p.Role == Listener {
+		if err := p.h.Mute(ari.DirectionIn); err != nil {
+			_ = r.bridge.RemoveChannel(p.ID) // nolint
+			return nil, eris.Wrap(err, "failed to mute listener")
+		}
+	}
+
+	if !r.o.talkDetection {
+		return nil, nil
+	}
+
+	sub := p.h.Subscribe(ari.Events.ChannelTalkingStarted, ari.Events.ChannelTalkingFinished)
+
+	// Talk detection is a nicety; the participant is still joined if it fails
+	_ = chanfunc.SetTalkDetect(p.h, "") // nolint
+
+	return sub, nil
+}
+
+// watchTalking tracks the talking state of the participant
+func (r *Room) watchTalking(p *participant, sub ari.Subscription) {
+	for v := range sub.Events() {
+		var talking bool
+
+		switch v.(type) {
+		case *ari.ChannelTalkingStarted:
+			talking = true
+		case *ari.ChannelTalkingFinished:
+		default:
+			continue
+		}
+
+		r.mu.Lock()
+
+		if p.Talking != talking {
+			p.Talking = talking
+			r.notify()
+		}
+
+		r.mu.Unlock()
+	}
+}
+
+// Leave removes the participant with the given channel ID from the room
+func (r *Room) Leave(id string) error {
+	r.mu.Lock()
+	p := r.member(id)
+	r.mu.Unlock()
+
+	if p == nil {
+		return ErrNotParticipant
+	}
+
+	if err := r.bridge.RemoveChannel(id); err != nil {
+		return eris.Wrap(err, "failed to remove channel from conference bridge")
+	}
+
+	r.mu.Lock()
+	p = r.remove(id)
+	r.mu.Unlock()
+
+	r.left(p)
+
+	return nil
+}
+
+// Kick removes the participant with the given channel ID from the room and hangs it up
+func (r *Room) Kick(id string) error {
+	r.mu.Lock()
+
+	p := r.member(id)
+	if p != nil {
+		r.remove(id)
+	}
+
+	r.mu.Unlock()
+
+	if p == nil {
+		return ErrNotParticipant
+	}
+
+	r.left(p)
+
+	if err := p.h.Hangup(); err != nil {
+		return eris.Wrap(err, "failed to hang up participant")
+	}
+
+	return nil
+}
+
+// Mute mutes the participant with the given channel ID, so that the room does not hear it
+func (r *Room) Mute(id string) error {
+	return r.setMuted(id, true)
+}
+
+// Unmute unmutes the participant with the given channel ID.  Listeners may not be unmuted.
+func (r *Room) Unmute(id string) error {
+	return r.setMuted(id, false)
+}
+
+func (r *Room) setMuted(id string, muted bool) error {
+	r.mu.Lock()
+
+	p := r.member(id)
+	if p == nil {
+		r.mu.Unlock()
+		return ErrNotParticipant
+	}
+
+	if !muted && p.Role == Listener {
+		r.mu.Unlock()
+		return errors.New("listeners may not be unmuted")
+	}
+
+	change := p.Muted != muted
+
+	r.mu.Unlock()
+
+	if !change {
+		return nil
+	}
+
+	if err := mute(p.h, muted); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.Muted = muted
+	r.notify()
+
+	return nil
+}
+
+// mute mutes or unmutes the channel of a participant
+func mute(h *ari.ChannelHandle, muted bool) error {
+	var err error
+
+	if muted {
+		err = h.Mute(ari.DirectionIn)
+	} else {
+		err = h.Unmute(ari.DirectionIn)
+	}
+
+	if err != nil {
+		return eris.Wrap(err, "failed to change mute of participant")
+	}
+
+	return nil
+}
+
+// SetRole changes the role of the participant with the given channel ID.
+// Participants which become listeners are muted; listeners which become
+// speakers or moderators are unmuted.
+func (r *Room) SetRole(id string, role Role) error {
+	r.mu.Lock()
+
+	p := r.member(id)
+	if p == nil {
+		r.mu.Unlock()
+		return ErrNotParticipant
+	}
+
+	muted := p.Muted
+
+	switch {
+	case role == Listener:
+		muted = true
+	case p.Role == Listener:
+		muted = false
+	}
+
+	change := p.Muted != muted
+
+	r.mu.Unlock()
+
+	if change {
+		if err := mute(p.h, muted); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.Role = role
+	p.Muted = muted
+	r.notify()
+
+	return nil
+}
+
+// Lock locks the room, so that only moderators may join
+func (r *Room) Lock() {
+	r.setLocked(true)
+}
+
+// Unlock unlocks the room, so that anyone may join
+func (r *Room) Unlock() {
+	r.setLocked(false)
+}
+
+func (r *Room) setLocked(locked bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.locked != locked {
+		r.locked = locked
+		r.notify()
+	}
+}
+
+// Record begins the recording of the room to the given name
+func (r *Room) Record(name string, opts *ari.RecordingOptions) (*ari.LiveRecordingHandle, error) {
+	r.recordMu.Lock()
+	defer r.recordMu.Unlock()
+
+	r.mu.Lock()
+	closed, recording := r.closed, r.recording != nil
+	r.mu.Unlock()
+
+	switch {
+	case closed:
+		return nil, ErrClosed
+	case recording:
+		return nil, errors.New("conference is already being recorded")
+	}
+
+	rec, err := r.bridge.Record(name, opts)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to record conference")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recording = rec
+	r.notify()
+
+	return rec, nil
+}
+
+// StopRecording stops the recording of the room, if there is one
+func (r *Room) StopRecording() error {
+	r.recordMu.Lock()
+	defer r.recordMu.Unlock()
+
+	r.mu.Lock()
+	rec := r.recording
+	r.mu.Unlock()
+
+	if rec == nil {
+		return nil
+	}
+
+	if err := rec.Stop(); err != nil {
+		return eris.Wrap(err, "failed to stop conference recording")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recording = nil
+	r.notify()
+
+	return nil
+}
+
+// Roster returns the current state of the room
+func (r *Room) Roster() Roster {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.roster()
+}
+
+// Watch returns a channel over which the roster of the room is sent
+// whenever it changes, beginning with the current roster.  Only the latest
+// roster is retained for a slow receiver.  The channel is closed when the
+// room is closed.
+func (r *Room) Watch() <-chan Roster {
+	ch := make(chan Roster, 1)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ch <- r.roster()
+
+	if r.closed {
+		close(ch)
+		return ch
+	}
+
+	r.watchers = append(r.watchers, ch)
+
+	return ch
+}
+
+// Close closes the room, deleting its bridge.  The participants are removed
+// from the bridge, but they are not hung up.
+func (r *Room) Close() error {
+	r.mu.Lock()
+	closed := r.closed
+	r.mu.Unlock()
+
+	if closed {
+		return nil
+	}
+
+	err := r.bridge.Delete()
+
+	r.mu.Lock()
+	gone := r.shutdown()
+	r.mu.Unlock()
+
+	release(gone)
+
+	if err != nil {
+		return eris.Wrap(err, "failed to delete conference bridge")
+	}
+
+	return nil
+}
+
+// shutdown marks the room closed and returns the participants which it
+// had, to be released once the lock is dropped.  It must be called with the
+// lock held.
+func (r *Room) shutdown() []*participant {
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	r.sub.Cancel()
+
+	gone := r.participants
+
+	r.participants = nil
+	r.recording = nil
+
+	r.notify()
+
+	for _, w := range r.watchers {
+		close(w)
+	}
+
+	r.watchers = nil
+
+	return gone
+}
+
+// release releases the given participants which are no longer in the room
+func release(gone []*participant) {
+	for _, p := range gone {
+		p.release()
+	}
+}
+
+// find returns the participant with the given channel ID.  It must be called with the lock held.
+func (r *Room) find(id string) *participant {
+	for _, p := range r.participants {
+		if p.ID == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// member returns the participant with the given channel ID, if it has
+// finished joining.  It must be called with the lock held.
+func (r *Room) member(id string) *participant {
+	if p := r.find(id); p != nil && !p.joining {
+		return p
+	}
+
+	return nil
+}
+
+// remove removes the participant with the given channel ID from the roster,
+// if it is present, and returns it.  It must be called with the lock held.
+// Unless the participant was still joining, the caller must pass it to left
+// once the lock is dropped.
+func (r *Room) remove(id string) *participant {
+	for i, p := range r.participants {
+		if p.ID != id {
+			continue
+		}
+
+		r.participants = append(r.participants[:i], r.participants[i+1:]...)
+
+		if p.joining {
+			// Join cleans up after the participant
+			return nil
+		}
+
+		r.notify()
+
+		return p
+	}
+
+	return nil
+}
+
+// left releases a participant which was removed from the room and updates
+// the room for its departure.  It must be called without the lock held.
+func (r *Room) left(p *participant) {
+	if p == nil {
+		return
+	}
+
+	p.release()
+
+	r.announce(r.o.leaveSound)
+	r.updateMOH()
+}
+
+// announce plays the given sound, if any, to the room
+func (r *Room) announce(uri string) {
+	if uri == "" {
+		return
+	}
+
+	r.mu.Lock()
+	empty := r.count() == 0
+	r.mu.Unlock()
+
+	if empty {
+		return
+	}
+
+	_, _ = r.bridge.Play(rid.New(rid.Playback), uri) // nolint
+}
+
+// updateMOH plays music on hold to the room while it has a single participant
+func (r *Room) updateMOH() {
+	r.mohMu.Lock()
+	defer r.mohMu.Unlock()
+
+	r.mu.Lock()
+	closed := r.closed
+	alone := r.o.aloneMOH && r.count() == 1
+	r.mu.Unlock()
+
+	switch {
+	case closed:
+	case alone && !r.moh:
+		r.moh = r.bridge.MOH(r.o.mohClass) == nil
+	case !alone && r.moh:
+		_ = r.bridge.StopMOH() // nolint
+		r.moh = false
+	}
+}
+
+// count returns the number of participants which have joined the room.  It
+// must be called with the lock held.
+func (r *Room) count() (n int) {
+	for _, p := range r.participants {
+		if !p.joining {
+			n++
+		}
+	}
+
+	return n
+}
+
+// roster returns the current roster.  It must be called with the lock held.
+func (r *Room) roster() Roster {
+	ret := Roster{
+		Participants: make([]Participant, 0, len(r.participants)),
+		Locked:       r.locked,
+		Recording:    r.recording != nil,
+		Closed:       r.closed,
+	}
+
+	for _, p := range r.participants {
+		if !p.joining {
+			ret.Participants = append(ret.Participants, p.Participant)
+		}
+	}
+
+	return ret
+}
+
+// notify sends the current roster to the watchers, replacing any roster
+// which they have not yet received.  It must be called with the lock held.
+func (r *Room) notify() {
+	roster := r.roster()
+
+	for _, w := range r.watchers {
+		select {
+		case <-w:
+		default:
+		}
+
+		w <- roster
+	}
+}
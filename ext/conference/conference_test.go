@@ -0,0 +1,327 @@
+package conference
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+type roomTest struct {
+	client  *arimocks.Client
+	channel *arimocks.Channel
+	bridge  *arimocks.Bridge
+
+	// bridgeEvents receives the events of the conference bridge
+	bridgeEvents chan ari.Event
+
+	// talking receives the talk detection events of each channel, by ID
+	talking map[string]chan ari.Event
+
+	key *ari.Key
+
+	// adding, if set, is called as each channel is added to the bridge
+	adding func(id string)
+}
+
+func newRoomTest() *roomTest {
+	rt := &roomTest{
+		client:       &arimocks.Client{},
+		channel:      &arimocks.Channel{},
+		bridge:       &arimocks.Bridge{},
+		bridgeEvents: make(chan ari.Event, 10),
+		talking:      make(map[string]chan ari.Event),
+		key:          ari.NewKey(ari.BridgeKey, "conf"),
+	}
+
+	rt.client.On("Channel").Return(rt.channel)
+	rt.client.On("Bridge").Return(rt.bridge)
+
+	rt.bridge.On("Create", rt.key, "mixing", "conf").Return(ari.NewBridgeHandle(rt.key, rt.bridge, nil), nil)
+	rt.bridge.On("Subscribe", rt.key, ari.Events.ChannelLeftBridge, ari.Events.BridgeDestroyed).
+		Return(subscription(rt.bridgeEvents))
+	rt.bridge.On("AddChannel", rt.key, mock.Anything).Return(func(_ *ari.Key, id string) error {
+		if rt.adding != nil {
+			rt.adding(id)
+		}
+
+		return nil
+	})
+	rt.bridge.On("RemoveChannel", rt.key, mock.Anything).Return(nil)
+	rt.bridge.On("MOH", rt.key, mock.Anything).Return(nil)
+	rt.bridge.On("StopMOH", rt.key).Return(nil)
+	rt.bridge.On("Play", rt.key, mock.Anything, mock.Anything).Return(nil, nil)
+	rt.bridge.On("Delete", rt.key).Return(nil)
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		events := make(chan ari.Event, 2)
+		rt.talking[id] = events
+		rt.channel.On("Subscribe", ari.NewKey(ari.ChannelKey, id),
+			ari.Events.ChannelTalkingStarted, ari.Events.ChannelTalkingFinished).Return(subscription(events))
+	}
+
+	rt.channel.On("SetVariable", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	rt.channel.On("Mute", mock.Anything, ari.DirectionIn).Return(nil)
+	rt.channel.On("Unmute", mock.Anything, ari.DirectionIn).Return(nil)
+	rt.channel.On("Hangup", mock.Anything, string(ari.HangupNormal)).Return(nil)
+
+	return rt
+}
+
+func subscription(events chan ari.Event) *arimocks.Subscription {
+	sub := &arimocks.Subscription{}
+	sub.On("Events").Return((<-chan ari.Event)(events))
+	sub.On("Cancel").Return()
+
+	return sub
+}
+
+func (rt *roomTest) handle(id string) *ari.ChannelHandle {
+	return ari.NewChannelHandle(ari.NewKey(ari.ChannelKey, id), rt.channel, nil)
+}
+
+// waitFor waits for the roster of the room to satisfy the given condition
+func waitFor(t *testing.T, r *Room, cond func(Roster) bool) Roster {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		roster := r.Roster()
+		if cond(roster) {
+			return roster
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for roster; last was %+v", roster)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJoinAndLeave(t *testing.T) {
+	rt := newRoomTest()
+
+	r, err := New(rt.client, rt.key, JoinSound("sound:join"), LeaveSound("sound:leave"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Join(rt.handle("alice"), Moderator); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rt.bridge.AssertCalled(t, "MOH", rt.key, "")
+
+	if err := r.Join(rt.handle("bob"), Speaker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rt.bridge.AssertCalled(t, "StopMOH", rt.key)
+	rt.bridge.AssertCalled(t, "Play", rt.key, mock.Anything, "sound:join")
+
+	roster := r.Roster()
+	if len(roster.Participants) != 2 || roster.Participants[0].ID != "alice" || roster.Participants[1].Role != Speaker {
+		t.Fatalf("unexpected roster %+v", roster)
+	}
+
+	// bob hangs up
+	rt.bridgeEvents <- &ari.ChannelLeftBridge{Channel: ari.ChannelData{ID: "bob"}}
+
+	waitFor(t, r, func(r Roster) bool { return len(r.Participants) == 1 })
+
+	rt.bridge.AssertCalled(t, "Play", rt.key, mock.Anything, "sound:leave")
+	rt.bridge.AssertNumberOfCalls(t, "MOH", 2)
+
+	rt.channel.AssertCalled(t, "SetVariable", ari.NewKey(ari.ChannelKey, "bob"), "TALK_DETECT(set)", "")
+	rt.channel.AssertCalled(t, "SetVariable", ari.NewKey(ari.ChannelKey, "bob"), "TALK_DETECT(remove)", "")
+	rt.channel.AssertNotCalled(t, "SetVariable", ari.NewKey(ari.ChannelKey, "alice"), "TALK_DETECT(remove)", "")
+
+	if err := r.Leave("bob"); err != ErrNotParticipant {
+		t.Errorf("expected ErrNotParticipant, got %v", err)
+	}
+}
+
+func TestListener(t *testing.T) {
+	rt := newRoomTest()
+
+	r, err := New(rt.client, rt.key, NoTalkDetection())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Join(rt.handle("carol"), Listener); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rt.channel.AssertCalled(t, "Mute", ari.NewKey(ari.ChannelKey, "carol"), ari.DirectionIn)
+	rt.channel.AssertNotCalled(t, "SetVariable", mock.Anything, mock.Anything, mock.Anything)
+
+	if err := r.Unmute("carol"); err == nil {
+		t.Error("expected error unmuting listener")
+	}
+
+	if err := r.SetRole("carol", Speaker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rt.channel.AssertCalled(t, "Unmute", ari.NewKey(ari.ChannelKey, "carol"), ari.DirectionIn)
+
+	if p := r.Roster().Participants[0]; p.Muted || p.Role != Speaker {
+		t.Errorf("unexpected participant %+v", p)
+	}
+}
+
+func TestLockedAndFull(t *testing.T) {
+	rt := newRoomTest()
+
+	r, err := New(rt.client, rt.key, MaxParticipants(2), NoAloneMusic())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r.Lock()
+
+	if err := r.Join(rt.handle("alice"), Speaker); err != ErrLocked {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+
+	if err := r.Join(rt.handle("alice"), Moderator); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r.Unlock()
+
+	if err := r.Join(rt.handle("bob"), Speaker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Join(rt.handle("carol"), Speaker); err != ErrFull {
+		t.Errorf("expected ErrFull, got %v", err)
+	}
+
+	rt.bridge.AssertNotCalled(t, "MOH", mock.Anything, mock.Anything)
+}
+
+func TestJoinDoesNotBlockRoom(t *testing.T) {
+	rt := newRoomTest()
+
+	adding, hold := make(chan struct{}), make(chan struct{})
+
+	rt.adding = func(string) {
+		close(adding)
+		<-hold
+	}
+
+	r, err := New(rt.client, rt.key, NoTalkDetection())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	joined := make(chan error, 1)
+
+	go func() {
+		joined <- r.Join(rt.handle("alice"), Speaker)
+	}()
+
+	<-adding
+
+	done := make(chan Roster, 1)
+
+	go func() {
+		r.Lock()
+		done <- r.Roster()
+	}()
+
+	select {
+	case roster := <-done:
+		if len(roster.Participants) != 0 || !roster.Locked {
+			t.Errorf("unexpected roster while joining %+v", roster)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("room was blocked by a joining channel")
+	}
+
+	close(hold)
+
+	if err := <-joined; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := len(r.Roster().Participants); n != 1 {
+		t.Errorf("expected one participant, got %d", n)
+	}
+}
+
+func TestKick(t *testing.T) {
+	rt := newRoomTest()
+
+	r, err := New(rt.client, rt.key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Join(rt.handle("alice"), Speaker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Kick("alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rt.channel.AssertCalled(t, "Hangup", ari.NewKey(ari.ChannelKey, "alice"), string(ari.HangupNormal))
+
+	if n := len(r.Roster().Participants); n != 0 {
+		t.Errorf("expected empty roster, got %d participants", n)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	rt := newRoomTest()
+
+	r, err := New(rt.client, rt.key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := r.Watch()
+
+	if roster := <-w; len(roster.Participants) != 0 {
+		t.Fatalf("unexpected initial roster %+v", roster)
+	}
+
+	if err := r.Join(rt.handle("alice"), Speaker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rt.talking["alice"] <- &ari.ChannelTalkingStarted{Channel: ari.ChannelData{ID: "alice"}}
+
+	timeout := time.After(time.Second)
+
+	for talking := false; !talking; {
+		select {
+		case roster := <-w:
+			talking = len(roster.Participants) == 1 && roster.Participants[0].Talking
+		case <-timeout:
+			t.Fatal("timed out waiting for talking participant")
+		}
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var last Roster
+
+	for roster := range w {
+		last = roster
+	}
+
+	if !last.Closed {
+		t.Errorf("expected closed roster, got %+v", last)
+	}
+}
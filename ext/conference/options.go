@@ -0,0 +1,80 @@
+package conference
+
+// Options describes the options for a conference Room
+type Options struct {
+	maxParticipants int
+
+	joinSound  string
+	leaveSound string
+
+	aloneMOH bool
+	mohClass string
+
+	talkDetection bool
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		aloneMOH:      true,
+		talkDetection: true,
+	}
+}
+
+// Apply applies a set of options for the conference Room
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// MaxParticipants limits the number of participants in the room.  Zero, the
+// default, means no limit.
+func MaxParticipants(n int) OptionFunc {
+	return func(o *Options) {
+		o.maxParticipants = n
+	}
+}
+
+// JoinSound sets the media URI (ex. "sound:confbridge-join") which is
+// played to the room when a participant joins
+func JoinSound(uri string) OptionFunc {
+	return func(o *Options) {
+		o.joinSound = uri
+	}
+}
+
+// LeaveSound sets the media URI (ex. "sound:confbridge-leave") which is
+// played to the room when a participant leaves
+func LeaveSound(uri string) OptionFunc {
+	return func(o *Options) {
+		o.leaveSound = uri
+	}
+}
+
+// AloneMusic sets the music on hold class which is played while a single
+// participant is in the room.  By default, the default class is played.
+func AloneMusic(class string) OptionFunc {
+	return func(o *Options) {
+		o.aloneMOH = true
+		o.mohClass = class
+	}
+}
+
+// NoAloneMusic disables the music on hold which is played while a single
+// participant is in the room
+func NoAloneMusic() OptionFunc {
+	return func(o *Options) {
+		o.aloneMOH = false
+	}
+}
+
+// NoTalkDetection disables talk detection on the participants.  By default,
+// talk detection (TALK_DETECT) is enabled on each participant as it joins.
+func NoTalkDetection() OptionFunc {
+	return func(o *Options) {
+		o.talkDetection = false
+	}
+}
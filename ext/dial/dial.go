@@ -0,0 +1,394 @@
+// Package dial provides outbound call placement, tracking the progress of
+// the call and reporting its outcome.
+package dial
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// ProgressBufferSize is the number of progress notifications buffered for
+// the session before further notifications are dropped
+var ProgressBufferSize = 4
+
+// Progress describes an intermediate stage of an outbound call
+type Progress int
+
+const (
+	// Ringing indicates that the dialed party is ringing
+	Ringing Progress = iota
+
+	// EarlyMedia indicates that the dialed party has indicated progress,
+	// typically with media (tones or announcements) before any answer
+	EarlyMedia
+)
+
+// String implements fmt.Stringer
+func (p Progress) String() string {
+	switch p {
+	case Ringing:
+		return "ringing"
+	case EarlyMedia:
+		return "early media"
+	}
+
+	return "unknown"
+}
+
+// Outcome describes the final result of an outbound call
+type Outcome int
+
+const (
+	// InProgress indicates that the call has not yet completed
+	InProgress Outcome = iota
+
+	// Answered indicates that the dialed party answered
+	Answered
+
+	// Busy indicates that the dialed party was busy
+	Busy
+
+	// NoAnswer indicates that the dialed party did not answer before the ring timeout
+	NoAnswer
+
+	// Congestion indicates that the dialed party could not be reached due to network congestion
+	Congestion
+
+	// Cancelled indicates that the call was cancelled before it was answered
+	Cancelled
+
+	// Failed indicates that the call failed for any other reason.  The cause
+	// of the Result describes the failure.
+	Failed
+)
+
+// String implements fmt.Stringer
+func (o Outcome) String() string {
+	switch o {
+	case InProgress:
+		return "in progress"
+	case Answered:
+		return "answered"
+	case Busy:
+		return "busy"
+	case NoAnswer:
+		return "no answer"
+	case Congestion:
+		return "congestion"
+	case Cancelled:
+		return "cancelled"
+	case Failed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+// Result describes the result of an outbound call
+type Result struct {
+	// Outcome is the outcome of the call
+	Outcome Outcome
+
+	// Cause is the hangup cause of the call, if it was not answered
+	Cause ari.Cause
+
+	// DialStatus is the last dial status reported by Asterisk, if any
+	DialStatus ari.DialStatus
+
+	// Duration is the time from the placement of the call to its outcome
+	Duration time.Duration
+
+	// Error indicates any error which caused the failure of the call
+	Error error
+}
+
+// Session describes an outbound call in progress
+type Session interface {
+	// Handle returns the handle of the dialed channel
+	Handle() *ari.ChannelHandle
+
+	// Progress returns a channel over which the progress of the call is
+	// reported.  It is closed when the call reaches its outcome.
+	Progress() <-chan Progress
+
+	// Done returns a channel which is closed when the call reaches its outcome
+	Done() <-chan struct{}
+
+	// Result waits for the call to reach its outcome and returns its result
+	Result() (*Result, error)
+
+	// Cancel cancels the call, hanging up the dialed channel, if it has not
+	// yet been answered
+	Cancel()
+
+	// CancelWithReason cancels the call as Cancel does, hanging up the dialed
+	// channel with the given reason (ex. ari.HangupAnsweredElsewhere)
+	CancelWithReason(reason ari.HangupReason)
+}
+
+type dialSession struct {
+	c ari.Channel
+	h *ari.ChannelHandle
+
+	// cancel is the dial context's cancel function
+	cancel context.CancelFunc
+
+	progress chan Progress
+
+	// reported records the progress which has been reported, so that each is reported once
+	reported map[Progress]bool
+
+	// done is closed when the call reaches its outcome
+	done chan struct{}
+
+	started time.Time
+
+	mu     sync.Mutex
+	result *Result
+
+	// cancelReason is the reason with which the dialed channel is hung up when the call is cancelled
+	cancelReason ari.HangupReason
+}
+
+// Dial places an outbound call to the given target endpoint (tech/resource)
+// and returns a session which tracks it.  The dialed channel enters the ARI
+// application of the client when it is answered.
+//
+// Cancelling the context before the call is answered cancels the call and
+// hangs up the dialed channel.  Once the call is answered, the channel
+// belongs to the caller; the context no longer affects it.
+func Dial(ctx context.Context, client ari.Client, target string, opts ...OptionFunc) (Session, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	req, err := o.originate(target, client.ApplicationName())
+	if err != nil {
+		return nil, eris.Wrap(err, "invalid dial options")
+	}
+
+	c := client.Channel()
+
+	h, err := c.StageOriginate(o.originator, req)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to stage originate")
+	}
+
+	// Subscribe before the call is placed so that no events are missed
+	sub := h.Subscribe(
+		ari.Events.Dial,
+		ari.Events.ChannelStateChange,
+		ari.Events.StasisStart,
+		ari.Events.ChannelDestroyed,
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &dialSession{
+		c:            c,
+		h:            h,
+		cancel:       cancel,
+		cancelReason: ari.HangupNormal,
+		progress:     make(chan Progress, ProgressBufferSize),
+		reported:     make(map[Progress]bool),
+		done:         make(chan struct{}),
+		started:      time.Now(),
+	}
+
+	if err := h.Exec(); err != nil {
+		sub.Cancel()
+		cancel()
+
+		return nil, eris.Wrapf(err, "failed to dial %s", target)
+	}
+
+	go s.run(ctx, sub, o.ringTimeout)
+
+	return s, nil
+}
+
+func (s *dialSession) run(ctx context.Context, sub ari.Subscription, ringTimeout time.Duration) {
+	defer s.cancel()
+	defer sub.Cancel()
+
+	// Asterisk enforces the ring timeout, too, but the call is abandoned
+	// here in case the result of that is never received.
+	var timeout <-chan time.Time
+
+	if ringTimeout > 0 {
+		t := time.NewTimer(ringTimeout + time.Second)
+		defer t.Stop()
+
+		timeout = t.C
+	}
+
+	var status ari.DialStatus
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			reason := s.cancelReason
+			s.mu.Unlock()
+
+			s.abandon(reason, &Result{Outcome: Cancelled, Cause: reason.Cause(), DialStatus: status})
+
+			return
+		case <-timeout:
+			s.abandon(ari.HangupNoAnswer, &Result{Outcome: NoAnswer, Cause: ari.CauseNoAnswer, DialStatus: status})
+			return
+		case v, ok := <-sub.Events():
+			if !ok {
+				s.finish(&Result{Outcome: Failed, DialStatus: status, Error: eris.New("subscription closed")})
+				return
+			}
+
+			switch e := v.(type) {
+			case *ari.Dial:
+				if e.Peer.ID != s.h.ID() {
+					continue
+				}
+
+				status = e.Status()
+
+				switch status {
+				case ari.DialStatusRinging:
+					s.report(Ringing)
+				case ari.DialStatusProgress:
+					s.report(EarlyMedia)
+				case ari.DialStatusAnswer:
+					s.finish(&Result{Outcome: Answered, DialStatus: status})
+					return
+				}
+			case *ari.ChannelStateChange:
+				switch {
+				case e.Channel.IsRinging():
+					s.report(Ringing)
+				case e.Channel.IsAnswered():
+					s.finish(&Result{Outcome: Answered, DialStatus: status})
+					return
+				}
+			case *ari.StasisStart:
+				s.finish(&Result{Outcome: Answered, DialStatus: status})
+				return
+			case *ari.ChannelDestroyed:
+				cause := e.HangupCause()
+				s.finish(&Result{Outcome: outcome(status, cause), Cause: cause, DialStatus: status})
+
+				return
+			}
+		}
+	}
+}
+
+// outcome determines the outcome of an unanswered call from its final dial
+// status, if there is one, or else from its hangup cause
+func outcome(status ari.DialStatus, cause ari.Cause) Outcome {
+	switch status {
+	case ari.DialStatusBusy:
+		return Busy
+	case ari.DialStatusNoAnswer:
+		return NoAnswer
+	case ari.DialStatusCongestion:
+		return Congestion
+	case ari.DialStatusCancel:
+		return Cancelled
+	case ari.DialStatusChanUnavailable:
+		return Failed
+	}
+
+	switch {
+	case cause.IsBusy():
+		return Busy
+	case cause.IsNoAnswer():
+		return NoAnswer
+	case cause.IsCongestion():
+		return Congestion
+	}
+
+	return Failed
+}
+
+// report reports the given progress, if it has not already been reported
+func (s *dialSession) report(p Progress) {
+	if s.reported[p] {
+		return
+	}
+
+	s.reported[p] = true
+
+	select {
+	case s.progress <- p:
+	default:
+	}
+}
+
+// abandon hangs up the dialed channel with the given reason and finishes the
+// session with the given result
+func (s *dialSession) abandon(reason ari.HangupReason, res *Result) {
+	if err := s.c.Hangup(s.h.Key(), string(reason)); err != nil {
+		res.Error = eris.Wrap(err, "failed to hang up dialed channel")
+	}
+
+	s.finish(res)
+}
+
+// finish records the result of the call and ends the session
+func (s *dialSession) finish(res *Result) {
+	res.Duration = time.Since(s.started)
+
+	s.mu.Lock()
+	s.result = res
+	s.mu.Unlock()
+
+	close(s.progress)
+	close(s.done)
+}
+
+// Handle returns the handle of the dialed channel
+func (s *dialSession) Handle() *ari.ChannelHandle {
+	return s.h
+}
+
+// Progress returns a channel over which the progress of the call is reported
+func (s *dialSession) Progress() <-chan Progress {
+	return s.progress
+}
+
+// Done returns a channel which is closed when the call reaches its outcome
+func (s *dialSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Result waits for the call to reach its outcome and returns its result
+func (s *dialSession) Result() (*Result, error) {
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.result, s.result.Error
+}
+
+// Cancel cancels the call, if it has not yet been answered
+func (s *dialSession) Cancel() {
+	s.cancel()
+}
+
+// CancelWithReason cancels the call with the given hangup reason, if it has
+// not yet been answered.  An empty reason is taken to be ari.HangupNormal.
+func (s *dialSession) CancelWithReason(reason ari.HangupReason) {
+	if reason == "" {
+		reason = ari.HangupNormal
+	}
+
+	s.mu.Lock()
+	s.cancelReason = reason
+	s.mu.Unlock()
+
+	s.cancel()
+}
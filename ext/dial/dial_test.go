@@ -0,0 +1,266 @@
+package dial
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+type dialTest struct {
+	key     *ari.Key
+	events  chan ari.Event
+	client  *arimocks.Client
+	channel *arimocks.Channel
+	req     ari.OriginateRequest
+}
+
+func newDialTest(execErr error) *dialTest {
+	dt := &dialTest{
+		key:     ari.NewKey(ari.ChannelKey, "out1"),
+		events:  make(chan ari.Event),
+		client:  &arimocks.Client{},
+		channel: &arimocks.Channel{},
+	}
+
+	sub := &arimocks.Subscription{}
+	sub.On("Events").Return((<-chan ari.Event)(dt.events))
+	sub.On("Cancel").Return()
+
+	dt.client.On("ApplicationName").Return("myapp")
+	dt.client.On("Channel").Return(dt.channel)
+
+	dt.channel.On("StageOriginate", mock.Anything, mock.Anything).Return(
+		func(_ *ari.Key, req ari.OriginateRequest) (*ari.ChannelHandle, error) {
+			dt.req = req
+
+			return ari.NewChannelHandle(dt.key, dt.channel, func(_ *ari.ChannelHandle) error {
+				return execErr
+			}), nil
+		})
+	dt.channel.On("Subscribe", dt.key,
+		ari.Events.Dial, ari.Events.ChannelStateChange, ari.Events.StasisStart, ari.Events.ChannelDestroyed,
+	).Return(sub)
+	dt.channel.On("Hangup", dt.key, mock.Anything).Return(nil)
+
+	return dt
+}
+
+func (dt *dialTest) send(e ari.Event) {
+	dt.events <- e
+}
+
+func TestDialAnswered(t *testing.T) {
+	dt := newDialTest(nil)
+
+	s, err := Dial(context.Background(), dt.client, "PJSIP/george",
+		RingTimeout(20*time.Second),
+		CallerID("Jane", "100"),
+		Originator(ari.NewKey(ari.ChannelKey, "in1")),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	if dt.req.App != "myapp" || dt.req.Timeout != 20 || dt.req.Originator != "in1" || dt.req.CallerID != `"Jane" <100>` {
+		t.Errorf("unexpected originate request %+v", dt.req)
+	}
+
+	dt.send(&ari.Dial{Peer: ari.ChannelData{ID: "out1"}, Dialstatus: "RINGING"})
+	dt.send(&ari.ChannelStateChange{Channel: ari.ChannelData{ID: "out1", State: "Ringing"}})
+	dt.send(&ari.Dial{Peer: ari.ChannelData{ID: "out1"}, Dialstatus: "PROGRESS"})
+	dt.send(&ari.StasisStart{Channel: ari.ChannelData{ID: "out1", State: "Up"}})
+
+	res, err := s.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Outcome != Answered {
+		t.Errorf("expected answered, got %s", res.Outcome)
+	}
+
+	var progress []Progress
+	for p := range s.Progress() {
+		progress = append(progress, p)
+	}
+
+	if len(progress) != 2 || progress[0] != Ringing || progress[1] != EarlyMedia {
+		t.Errorf("unexpected progress %v", progress)
+	}
+
+	dt.channel.AssertNotCalled(t, "Hangup", dt.key, mock.Anything)
+}
+
+func TestDialFormatsAndOriginator(t *testing.T) {
+	dt := newDialTest(nil)
+
+	s, err := Dial(context.Background(), dt.client, "PJSIP/george",
+		Originator(ari.NewKey(ari.ChannelKey, "in1")),
+		Formats("ulaw", "alaw"),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	defer s.Cancel()
+
+	if dt.req.Formats != "ulaw,alaw" || dt.req.Originator != "" {
+		t.Errorf("expected the formats to replace the originator, got %+v", dt.req)
+	}
+}
+
+func TestDialOutcomes(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []ari.Event
+		outcome Outcome
+		cause   ari.Cause
+	}{
+		{
+			"busy",
+			[]ari.Event{&ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "out1"}, Cause: 17}},
+			Busy, ari.CauseUserBusy,
+		},
+		{
+			"noAnswerStatus",
+			[]ari.Event{
+				&ari.Dial{Peer: ari.ChannelData{ID: "out1"}, Dialstatus: "NOANSWER"},
+				&ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "out1"}, Cause: 16},
+			},
+			NoAnswer, ari.CauseNormalClearing,
+		},
+		{
+			"congestion",
+			[]ari.Event{&ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "out1"}, Cause: 34}},
+			Congestion, ari.CauseCongestion,
+		},
+		{
+			"failed",
+			[]ari.Event{&ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "out1"}, Cause: 1}},
+			Failed, ari.CauseUnallocated,
+		},
+		{
+			"otherPeer",
+			[]ari.Event{
+				&ari.Dial{Peer: ari.ChannelData{ID: "other"}, Dialstatus: "BUSY"},
+				&ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "out1"}, Cause: 21},
+			},
+			Failed, ari.CauseCallRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := newDialTest(nil)
+
+			s, err := Dial(context.Background(), dt.client, "PJSIP/george")
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+
+			for _, e := range tt.events {
+				dt.send(e)
+			}
+
+			res, _ := s.Result()
+			if res.Outcome != tt.outcome || res.Cause != tt.cause {
+				t.Errorf("expected %s (%d), got %s (%d)", tt.outcome, tt.cause, res.Outcome, res.Cause)
+			}
+		})
+	}
+}
+
+func TestDialCancel(t *testing.T) {
+	dt := newDialTest(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := Dial(ctx, dt.client, "PJSIP/george")
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	dt.send(&ari.ChannelStateChange{Channel: ari.ChannelData{ID: "out1", State: "Ringing"}})
+	cancel()
+
+	res, _ := s.Result()
+	if res.Outcome != Cancelled {
+		t.Errorf("expected cancelled, got %s", res.Outcome)
+	}
+
+	dt.channel.AssertCalled(t, "Hangup", dt.key, string(ari.HangupNormal))
+}
+
+func TestDialCancelWithReason(t *testing.T) {
+	dt := newDialTest(nil)
+
+	s, err := Dial(context.Background(), dt.client, "PJSIP/george")
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	dt.send(&ari.ChannelStateChange{Channel: ari.ChannelData{ID: "out1", State: "Ringing"}})
+	s.CancelWithReason(ari.HangupAnsweredElsewhere)
+
+	res, _ := s.Result()
+	if res.Outcome != Cancelled || res.Cause != ari.CauseAnsweredElsewhere {
+		t.Errorf("expected cancelled as answered elsewhere, got %s (cause %d)", res.Outcome, res.Cause)
+	}
+
+	dt.channel.AssertCalled(t, "Hangup", dt.key, string(ari.HangupAnsweredElsewhere))
+
+	dt = newDialTest(nil)
+
+	if s, err = Dial(context.Background(), dt.client, "PJSIP/george"); err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	s.CancelWithReason("")
+
+	if res, _ := s.Result(); res.Cause != ari.CauseNormal {
+		t.Errorf("expected an empty reason to hang up normally, got cause %d", res.Cause)
+	}
+}
+
+func TestDialRingTimeout(t *testing.T) {
+	dt := newDialTest(nil)
+
+	s, err := Dial(context.Background(), dt.client, "PJSIP/george", RingTimeout(time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	select {
+	case <-s.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for ring timeout")
+	}
+
+	res, _ := s.Result()
+	if res.Outcome != NoAnswer {
+		t.Errorf("expected no answer, got %s", res.Outcome)
+	}
+
+	dt.channel.AssertCalled(t, "Hangup", dt.key, string(ari.HangupNoAnswer))
+}
+
+func TestDialFailures(t *testing.T) {
+	dt := newDialTest(errors.New("boom"))
+
+	if _, err := Dial(context.Background(), dt.client, "PJSIP/george"); err == nil {
+		t.Error("expected error from failed originate")
+	}
+
+	dt = newDialTest(nil)
+
+	if _, err := Dial(context.Background(), dt.client, "george"); err == nil {
+		t.Error("expected error from invalid target")
+	}
+}
@@ -0,0 +1,131 @@
+package dial
+
+import (
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// DefaultRingTimeout is the default amount of time to wait for the dialed
+// party to answer before giving up.
+var DefaultRingTimeout = 30 * time.Second
+
+// Options describes the options for a Dial
+type Options struct {
+	ringTimeout time.Duration
+
+	originator *ari.Key
+
+	callerIDName   string
+	callerIDNumber string
+
+	args []string
+
+	channelID string
+
+	formats []string
+
+	variables map[string]string
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		ringTimeout: DefaultRingTimeout,
+	}
+}
+
+// Apply applies a set of options for the Dial
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// originate builds the originate request for a dial of the given target into the given ARI application
+func (o *Options) originate(target, app string) (ari.OriginateRequest, error) {
+	b := ari.NewOriginate().
+		ToEndpoint(target).
+		IntoApp(app, o.args...).
+		WithTimeout(o.ringTimeout).
+		WithVariables(o.variables).
+		WithChannelID(o.channelID)
+
+	if o.callerIDName != "" || o.callerIDNumber != "" {
+		b.WithCallerID(o.callerIDName, o.callerIDNumber)
+	}
+
+	if len(o.formats) > 0 {
+		b.WithFormats(o.formats...)
+	}
+
+	// Asterisk takes either the formats or the originator; the formats were
+	// asked for explicitly, so they win
+	if o.originator != nil && o.originator.Kind == ari.ChannelKey && len(o.formats) == 0 {
+		b.WithOriginator(o.originator.ID)
+	}
+
+	return b.Request()
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// RingTimeout sets the amount of time to wait for the dialed party to answer.
+// A negative value waits indefinitely.
+func RingTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.ringTimeout = timeout
+	}
+}
+
+// Originator sets the channel on whose behalf the call is placed.  Asterisk
+// uses it to select the codecs of the new channel, such that no transcoding
+// is needed when the two are bridged, and the new channel is placed on the
+// same Asterisk node.
+func Originator(key *ari.Key) OptionFunc {
+	return func(o *Options) {
+		o.originator = key
+	}
+}
+
+// CallerID sets the caller ID name and number presented to the dialed party
+func CallerID(name, number string) OptionFunc {
+	return func(o *Options) {
+		o.callerIDName = name
+		o.callerIDNumber = number
+	}
+}
+
+// Args sets the arguments with which the answered channel enters the ARI application
+func Args(args ...string) OptionFunc {
+	return func(o *Options) {
+		o.args = args
+	}
+}
+
+// ChannelID sets the unique ID of the new channel
+func ChannelID(id string) OptionFunc {
+	return func(o *Options) {
+		o.channelID = id
+	}
+}
+
+// Formats sets the codecs which are allowed for the new channel.  They take
+// the place of the codecs which Originator would select; the originator still
+// places the new channel on its Asterisk node.
+func Formats(formats ...string) OptionFunc {
+	return func(o *Options) {
+		o.formats = formats
+	}
+}
+
+// Variable sets a channel variable on the new channel
+func Variable(name, value string) OptionFunc {
+	return func(o *Options) {
+		if o.variables == nil {
+			o.variables = make(map[string]string)
+		}
+
+		o.variables[name] = value
+	}
+}
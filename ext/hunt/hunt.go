@@ -0,0 +1,329 @@
+// Package hunt provides hunt groups:  the ringing of a set of endpoints, all
+// at once or one after another, until one of them answers.
+package hunt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+	"github.com/CyCoreSystems/ari/v6/rid"
+)
+
+var (
+	// ErrNoAnswer indicates that no leg of the hunt answered
+	ErrNoAnswer = errors.New("no leg answered")
+
+	// ErrInboundHangup indicates that the hunt was cancelled because the inbound channel hung up
+	ErrInboundHangup = errors.New("inbound channel hung up")
+)
+
+// Leg describes an endpoint to be rung by a hunt
+type Leg struct {
+	// Target is the endpoint (tech/resource) to be dialed
+	Target string
+
+	// Timeout is the amount of time to ring the endpoint.  If zero,
+	// DefaultLegTimeout is used.
+	Timeout time.Duration
+}
+
+// LegResult describes the result of the dial of a leg
+type LegResult struct {
+	// Leg is the leg which was dialed
+	Leg Leg
+
+	// Handle is the handle of the dialed channel, if the dial was placed
+	Handle *ari.ChannelHandle
+
+	// Result is the result of the dial, if it was placed
+	Result *dial.Result
+
+	// Error is any error encountered in the dial of the leg
+	Error error
+}
+
+// Result describes the result of a hunt
+type Result struct {
+	// Winner is the channel of the leg which answered, if any
+	Winner *ari.ChannelHandle
+
+	// WinningLeg is the index of the leg which answered, or -1 if none answered
+	WinningLeg int
+
+	// Bridge is the bridge to which the winner and the inbound channel were
+	// added, if the winner was bridged
+	Bridge *ari.BridgeHandle
+
+	// Legs lists the results of each leg, in the order given.  Legs which were
+	// never dialed have neither a Result nor an Error.
+	Legs []LegResult
+}
+
+// Hunt rings the given legs, according to the strategy of the options, until
+// one of them answers.  The first leg to answer wins, and all other legs are
+// hung up.  If an inbound channel is given, it is bridged with the winner.
+//
+// If no leg answers, ErrNoAnswer is returned along with the result, which
+// describes the outcome of each leg.  If the context is cancelled or the
+// inbound channel hangs up, all legs are hung up.
+func Hunt(ctx context.Context, client ari.Client, legs []Leg, opts ...OptionFunc) (*Result, error) {
+	if len(legs) == 0 {
+		return nil, errors.New("no legs to hunt")
+	}
+
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	res := &Result{
+		WinningLeg: -1,
+		Legs:       make([]LegResult, len(legs)),
+	}
+
+	for i, l := range legs {
+		res.Legs[i].Leg = l
+	}
+
+	h := &hunt{
+		client: client,
+		o:      o,
+		res:    res,
+	}
+
+	if o.inbound != nil {
+		sub := o.inbound.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd)
+		defer sub.Cancel()
+
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-sub.Events():
+				cancel(ErrInboundHangup)
+			}
+		}()
+
+		if err := h.startEarlyMedia(); err != nil {
+			return nil, eris.Wrap(err, "failed to indicate ringing to inbound channel")
+		}
+
+		defer h.stopEarlyMedia()
+	}
+
+	if o.strategy == Sequential {
+		h.sequential(ctx)
+	} else {
+		h.simultaneous(ctx)
+	}
+
+	if res.WinningLeg < 0 {
+		if err := context.Cause(ctx); err != nil {
+			return res, err
+		}
+
+		return res, ErrNoAnswer
+	}
+
+	res.Winner = res.Legs[res.WinningLeg].Handle
+
+	if o.inbound == nil || !o.bridge {
+		return res, nil
+	}
+
+	h.stopEarlyMedia()
+
+	if err := h.bridge(); err != nil {
+		// The winner has nobody to talk to
+		_ = client.Channel().Hangup(res.Winner.Key(), string(ari.HangupNormal)) // nolint
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+// hunt tracks the state of a hunt
+type hunt struct {
+	client ari.Client
+	o      *Options
+	res    *Result
+
+	earlyMedia bool
+}
+
+// dial places the call for the given leg
+func (h *hunt) dial(ctx context.Context, i int) dial.Session {
+	l := h.res.Legs[i].Leg
+
+	timeout := l.Timeout
+	if timeout == 0 {
+		timeout = DefaultLegTimeout
+	}
+
+	// The options of the hunt itself come last, so that the dial options do
+	// not override them.  If the dial options set Formats, the dial leaves
+	// the originator out of the request.
+	opts := append(append([]dial.OptionFunc(nil), h.o.dialOptions...), dial.RingTimeout(timeout))
+	if h.o.inbound != nil {
+		opts = append(opts, dial.Originator(h.o.inbound.Key()))
+	}
+
+	s, err := dial.Dial(ctx, h.client, l.Target, opts...)
+	if err != nil {
+		h.res.Legs[i].Error = err
+		return nil
+	}
+
+	h.res.Legs[i].Handle = s.Handle()
+
+	return s
+}
+
+// legResult records the result of the dial of the given leg
+func (h *hunt) legResult(i int, r *dial.Result, err error) {
+	h.res.Legs[i].Result = r
+	h.res.Legs[i].Error = err
+}
+
+// sequential rings each leg in turn until one answers
+func (h *hunt) sequential(ctx context.Context) {
+	for i := range h.res.Legs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		s := h.dial(ctx, i)
+		if s == nil {
+			continue
+		}
+
+		r, err := s.Result()
+		h.legResult(i, r, err)
+
+		if r.Outcome == dial.Answered {
+			h.res.WinningLeg = i
+			return
+		}
+	}
+}
+
+// simultaneous rings all legs at once, until one answers
+func (h *hunt) simultaneous(ctx context.Context) {
+	type legDone struct {
+		i   int
+		r   *dial.Result
+		err error
+	}
+
+	sessions := make([]dial.Session, len(h.res.Legs))
+	done := make(chan legDone, len(h.res.Legs))
+
+	var active int
+
+	for i := range h.res.Legs {
+		s := h.dial(ctx, i)
+		if s == nil {
+			continue
+		}
+
+		sessions[i] = s
+		active++
+
+		go func(i int, s dial.Session) {
+			r, err := s.Result()
+			done <- legDone{i: i, r: r, err: err}
+		}(i, s)
+	}
+
+	for ; active > 0; active-- {
+		d := <-done
+		h.legResult(d.i, d.r, d.err)
+
+		if d.r.Outcome != dial.Answered {
+			continue
+		}
+
+		if h.res.WinningLeg >= 0 {
+			// This leg answered after the winner; it lost the race
+			_ = h.client.Channel().Hangup(sessions[d.i].Handle().Key(), string(ari.HangupAnsweredElsewhere)) // nolint
+
+			continue
+		}
+
+		h.res.WinningLeg = d.i
+
+		for j, s := range sessions {
+			if s != nil && j != d.i {
+				s.CancelWithReason(ari.HangupAnsweredElsewhere)
+			}
+		}
+	}
+}
+
+// startEarlyMedia indicates ringing, or plays music on hold, to the inbound channel
+func (h *hunt) startEarlyMedia() error {
+	var err error
+
+	switch {
+	case h.o.ringback:
+		err = h.o.inbound.Ring()
+	case h.o.moh:
+		err = h.o.inbound.MOH(h.o.mohClass)
+	default:
+		return nil
+	}
+
+	h.earlyMedia = err == nil
+
+	return err
+}
+
+// stopEarlyMedia stops any ringing or music on hold of the inbound channel
+func (h *hunt) stopEarlyMedia() {
+	if !h.earlyMedia {
+		return
+	}
+
+	h.earlyMedia = false
+
+	// The inbound channel may already be gone, so errors are ignored
+	if h.o.ringback {
+		_ = h.o.inbound.StopRing() // nolint
+	} else {
+		_ = h.o.inbound.StopMOH() // nolint
+	}
+}
+
+// bridge answers the inbound channel and bridges it with the winner
+func (h *hunt) bridge() error {
+	in := h.o.inbound
+
+	if err := in.Answer(); err != nil {
+		return eris.Wrap(err, "failed to answer inbound channel")
+	}
+
+	key := in.Key().New(ari.BridgeKey, rid.New(rid.Bridge))
+
+	br, err := h.client.Bridge().Create(key, "mixing", key.ID)
+	if err != nil {
+		return eris.Wrap(err, "failed to create bridge")
+	}
+
+	h.res.Bridge = br
+
+	if err := br.AddChannel(in.ID()); err != nil {
+		return eris.Wrap(err, "failed to add inbound channel to bridge")
+	}
+
+	if err := br.AddChannel(h.res.Winner.ID()); err != nil {
+		return eris.Wrap(err, "failed to add winning channel to bridge")
+	}
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package hunt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+)
+
+type huntTest struct {
+	client  *arimocks.Client
+	channel *arimocks.Channel
+	bridge  *arimocks.Bridge
+
+	inKey     *ari.Key
+	inbound   *ari.ChannelHandle
+	inEvents  chan ari.Event
+	bridgeKey *ari.Key
+
+	mu sync.Mutex
+
+	// legs maps the endpoint of each dialed leg to its event channel
+	legs map[string]chan ari.Event
+
+	// dialed is signalled with the endpoint of each leg as it is dialed
+	dialed chan string
+
+	// requests maps the endpoint of each dialed leg to its originate request
+	requests map[string]ari.OriginateRequest
+}
+
+func legKey(endpoint string) *ari.Key {
+	return ari.NewKey(ari.ChannelKey, "leg-"+endpoint[len("PJSIP/"):])
+}
+
+func newHuntTest() *huntTest {
+	ht := &huntTest{
+		client:   &arimocks.Client{},
+		channel:  &arimocks.Channel{},
+		bridge:   &arimocks.Bridge{},
+		inKey:    ari.NewKey(ari.ChannelKey, "in1"),
+		inEvents: make(chan ari.Event),
+		legs:     make(map[string]chan ari.Event),
+		dialed:   make(chan string, 10),
+		requests: make(map[string]ari.OriginateRequest),
+	}
+
+	ht.client.On("ApplicationName").Return("myapp")
+	ht.client.On("Channel").Return(ht.channel)
+	ht.client.On("Bridge").Return(ht.bridge)
+
+	ht.channel.On("StageOriginate", mock.Anything, mock.Anything).Return(
+		func(_ *ari.Key, req ari.OriginateRequest) (*ari.ChannelHandle, error) {
+			key := legKey(req.Endpoint)
+			events := make(chan ari.Event, 10)
+
+			sub := &arimocks.Subscription{}
+			sub.On("Events").Return((<-chan ari.Event)(events))
+			sub.On("Cancel").Return()
+
+			ht.mu.Lock()
+			ht.legs[req.Endpoint] = events
+			ht.requests[req.Endpoint] = req
+			ht.mu.Unlock()
+
+			ht.channel.On("Subscribe", key,
+				ari.Events.Dial, ari.Events.ChannelStateChange, ari.Events.StasisStart, ari.Events.ChannelDestroyed,
+			).Return(sub)
+
+			return ari.NewChannelHandle(key, ht.channel, func(_ *ari.ChannelHandle) error {
+				ht.dialed <- req.Endpoint
+				return nil
+			}), nil
+		})
+	ht.channel.On("Hangup", mock.Anything, mock.Anything).Return(nil)
+
+	inSub := &arimocks.Subscription{}
+	inSub.On("Events").Return((<-chan ari.Event)(ht.inEvents))
+	inSub.On("Cancel").Return()
+
+	ht.channel.On("Subscribe", ht.inKey, ari.Events.ChannelHangupRequest, ari.Events.StasisEnd).Return(inSub)
+	ht.channel.On("Ring", ht.inKey).Return(nil)
+	ht.channel.On("StopRing", ht.inKey).Return(nil)
+	ht.channel.On("Answer", ht.inKey).Return(nil)
+
+	ht.bridge.On("Create", mock.Anything, "mixing", mock.Anything).Return(
+		func(key *ari.Key, _, _ string) (*ari.BridgeHandle, error) {
+			ht.bridgeKey = key
+			return ari.NewBridgeHandle(key, ht.bridge, nil), nil
+		})
+	ht.bridge.On("AddChannel", mock.Anything, mock.Anything).Return(nil)
+
+	ht.inbound = ari.NewChannelHandle(ht.inKey, ht.channel, nil)
+
+	return ht
+}
+
+// waitDialed waits for the given leg to be dialed
+func (ht *huntTest) waitDialed(t *testing.T, endpoint string) {
+	t.Helper()
+
+	select {
+	case e := <-ht.dialed:
+		if e != endpoint {
+			t.Fatalf("expected %s to be dialed, got %s", endpoint, e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s to be dialed", endpoint)
+	}
+}
+
+// send sends an event to the given leg
+func (ht *huntTest) send(endpoint string, e ari.Event) {
+	ht.mu.Lock()
+	events := ht.legs[endpoint]
+	ht.mu.Unlock()
+
+	events <- e
+}
+
+func answered(endpoint string) ari.Event {
+	return &ari.StasisStart{Channel: ari.ChannelData{ID: legKey(endpoint).ID, State: "Up"}}
+}
+
+func destroyed(endpoint string, cause ari.Cause) ari.Event {
+	return &ari.ChannelDestroyed{Channel: ari.ChannelData{ID: legKey(endpoint).ID}, Cause: int(cause)}
+}
+
+func TestSimultaneous(t *testing.T) {
+	ht := newHuntTest()
+
+	type huntResult struct {
+		res *Result
+		err error
+	}
+
+	ret := make(chan huntResult)
+
+	go func() {
+		res, err := Hunt(context.Background(), ht.client,
+			[]Leg{{Target: "PJSIP/alice"}, {Target: "PJSIP/bob"}, {Target: "PJSIP/carol"}},
+			Inbound(ht.inbound),
+		)
+		ret <- huntResult{res, err}
+	}()
+
+	ht.waitDialed(t, "PJSIP/alice")
+	ht.waitDialed(t, "PJSIP/bob")
+	ht.waitDialed(t, "PJSIP/carol")
+
+	ht.send("PJSIP/alice", destroyed("PJSIP/alice", ari.CauseUserBusy))
+	ht.send("PJSIP/bob", answered("PJSIP/bob"))
+
+	r := <-ret
+	if r.err != nil {
+		t.Fatalf("unexpected error: %v", r.err)
+	}
+
+	if r.res.WinningLeg != 1 || r.res.Winner.ID() != "leg-bob" {
+		t.Errorf("expected bob to win, got %d", r.res.WinningLeg)
+	}
+
+	if r.res.Legs[0].Result.Outcome != dial.Busy {
+		t.Errorf("expected alice to be busy, got %s", r.res.Legs[0].Result.Outcome)
+	}
+
+	if r.res.Legs[2].Result.Outcome != dial.Cancelled {
+		t.Errorf("expected carol to be cancelled, got %s", r.res.Legs[2].Result.Outcome)
+	}
+
+	ht.channel.AssertCalled(t, "Hangup", legKey("PJSIP/carol"), string(ari.HangupAnsweredElsewhere))
+	ht.channel.AssertCalled(t, "Ring", ht.inKey)
+	ht.channel.AssertCalled(t, "StopRing", ht.inKey)
+	ht.channel.AssertCalled(t, "Answer", ht.inKey)
+	ht.bridge.AssertCalled(t, "AddChannel", ht.bridgeKey, "in1")
+	ht.bridge.AssertCalled(t, "AddChannel", ht.bridgeKey, "leg-bob")
+
+	if r.res.Bridge == nil {
+		t.Error("expected bridge in result")
+	}
+}
+
+func TestSequential(t *testing.T) {
+	ht := newHuntTest()
+
+	ret := make(chan *Result)
+
+	go func() {
+		res, err := Hunt(context.Background(), ht.client,
+			[]Leg{{Target: "PJSIP/alice"}, {Target: "PJSIP/bob"}, {Target: "PJSIP/carol"}},
+			WithStrategy(Sequential),
+		)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		ret <- res
+	}()
+
+	ht.waitDialed(t, "PJSIP/alice")
+	ht.send("PJSIP/alice", destroyed("PJSIP/alice", ari.CauseNoAnswer))
+
+	ht.waitDialed(t, "PJSIP/bob")
+	ht.send("PJSIP/bob", answered("PJSIP/bob"))
+
+	res := <-ret
+	if res == nil || res.WinningLeg != 1 {
+		t.Fatalf("expected bob to win")
+	}
+
+	if res.Legs[2].Result != nil || res.Legs[2].Handle != nil {
+		t.Errorf("expected carol never to be dialed")
+	}
+
+	if res.Bridge != nil {
+		t.Errorf("expected no bridge without an inbound channel")
+	}
+}
+
+func TestNoAnswer(t *testing.T) {
+	ht := newHuntTest()
+
+	ret := make(chan error)
+
+	go func() {
+		_, err := Hunt(context.Background(), ht.client, []Leg{{Target: "PJSIP/alice"}, {Target: "PJSIP/bob"}})
+		ret <- err
+	}()
+
+	ht.waitDialed(t, "PJSIP/alice")
+	ht.waitDialed(t, "PJSIP/bob")
+
+	ht.send("PJSIP/alice", destroyed("PJSIP/alice", ari.CauseUserBusy))
+	ht.send("PJSIP/bob", destroyed("PJSIP/bob", ari.CauseNoAnswer))
+
+	if err := <-ret; !errors.Is(err, ErrNoAnswer) {
+		t.Errorf("expected ErrNoAnswer, got %v", err)
+	}
+}
+
+func TestInboundHangup(t *testing.T) {
+	ht := newHuntTest()
+
+	ret := make(chan error)
+
+	go func() {
+		_, err := Hunt(context.Background(), ht.client, []Leg{{Target: "PJSIP/alice"}}, Inbound(ht.inbound))
+		ret <- err
+	}()
+
+	ht.waitDialed(t, "PJSIP/alice")
+
+	ht.inEvents <- &ari.ChannelHangupRequest{Channel: ari.ChannelData{ID: "in1"}}
+
+	if err := <-ret; !errors.Is(err, ErrInboundHangup) {
+		t.Errorf("expected ErrInboundHangup, got %v", err)
+	}
+
+	ht.channel.AssertCalled(t, "Hangup", legKey("PJSIP/alice"), string(ari.HangupNormal))
+	ht.channel.AssertNotCalled(t, "Answer", ht.inKey)
+}
+
+func TestDialOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []dial.OptionFunc
+		originator string
+		formats    string
+	}{
+		{"overridden", []dial.OptionFunc{dial.RingTimeout(5 * time.Second), dial.Originator(ari.NewKey(ari.ChannelKey, "other"))}, "in1", ""},
+		{"formats", []dial.OptionFunc{dial.Formats("ulaw")}, "", "ulaw"},
+	}
+
+	for _, tc := range tests {
+		ht := newHuntTest()
+
+		ret := make(chan error)
+
+		go func() {
+			_, err := Hunt(context.Background(), ht.client, []Leg{{Target: "PJSIP/alice", Timeout: 30 * time.Second}},
+				Inbound(ht.inbound), DialOptions(tc.opts...))
+			ret <- err
+		}()
+
+		ht.waitDialed(t, "PJSIP/alice")
+
+		ht.mu.Lock()
+		req := ht.requests["PJSIP/alice"]
+		ht.mu.Unlock()
+
+		ht.inEvents <- &ari.ChannelHangupRequest{Channel: ari.ChannelData{ID: "in1"}}
+		<-ret
+
+		if req.Timeout != 30 || req.Originator != tc.originator || req.Formats != tc.formats {
+			t.Errorf("%s: unexpected originate request %+v", tc.name, req)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package hunt
+
+import (
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+)
+
+// DefaultLegTimeout is the default amount of time to ring each leg before
+// giving up on it
+var DefaultLegTimeout = 20 * time.Second
+
+// Strategy describes the order in which the legs of a hunt are rung
+type Strategy int
+
+const (
+	// Simultaneous rings all legs at once
+	Simultaneous Strategy = iota
+
+	// Sequential rings the legs one after another, in order
+	Sequential
+)
+
+// Options describes the options for a hunt
+type Options struct {
+	strategy Strategy
+
+	inbound *ari.ChannelHandle
+
+	bridge bool
+
+	ringback bool
+
+	moh      bool
+	mohClass string
+
+	dialOptions []dial.OptionFunc
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		strategy: Simultaneous,
+		bridge:   true,
+		ringback: true,
+	}
+}
+
+// Apply applies a set of options for the hunt
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// WithStrategy sets the order in which the legs are rung.  The default is Simultaneous.
+func WithStrategy(s Strategy) OptionFunc {
+	return func(o *Options) {
+		o.strategy = s
+	}
+}
+
+// Inbound sets the inbound channel on whose behalf the hunt is made.  The
+// legs are originated with the inbound channel as their originator, so
+// that they share its codecs.  Unless NoBridge is given, the inbound channel
+// is answered and bridged with the winning leg.  If the inbound channel hangs
+// up, the hunt is cancelled.
+func Inbound(h *ari.ChannelHandle) OptionFunc {
+	return func(o *Options) {
+		o.inbound = h
+	}
+}
+
+// NoBridge leaves the winning leg unbridged, even if there is an inbound channel
+func NoBridge() OptionFunc {
+	return func(o *Options) {
+		o.bridge = false
+	}
+}
+
+// NoRingback disables the indication of ringing to the inbound channel while the legs are rung
+func NoRingback() OptionFunc {
+	return func(o *Options) {
+		o.ringback = false
+	}
+}
+
+// MusicOnHold plays the given music on hold class (or the default class, if
+// empty) to the inbound channel, as early media, while the legs are rung.  It
+// replaces the indication of ringing.
+func MusicOnHold(class string) OptionFunc {
+	return func(o *Options) {
+		o.moh = true
+		o.mohClass = class
+		o.ringback = false
+	}
+}
+
+// DialOptions sets the options which are applied to the dial of each leg
+// (ex. dial.CallerID).  The timeout of the leg and the inbound channel, as
+// the originator, take precedence over them.
+func DialOptions(opts ...dial.OptionFunc) OptionFunc {
+	return func(o *Options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
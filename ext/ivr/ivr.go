@@ -0,0 +1,471 @@
+// Package ivr runs declarative IVR menus:  graphs of nodes, defined in Go or
+// loaded from JSON or YAML, which are played to a caller with ext/play.
+package ivr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+// ErrMaxSteps indicates that the run of a menu visited the maximum number of nodes
+var ErrMaxSteps = errors.New("maximum number of steps exceeded")
+
+// Status describes how the run of a menu ended
+type Status int
+
+const (
+	// Exited indicates that the menu exited, by an Exit or Return action or
+	// by a node without a next action
+	Exited Status = iota
+
+	// HungUp indicates that the menu hung up the channel, by a Hangup action
+	HungUp
+
+	// CallerHungUp indicates that the caller hung up
+	CallerHungUp
+
+	// Exhausted indicates that the retries of a node without a handler were exhausted
+	Exhausted
+
+	// Cancelled indicates that the run was cancelled by its context
+	Cancelled
+
+	// Failed indicates that the run failed.  The error describes why.
+	Failed
+)
+
+// String implements fmt.Stringer
+func (s Status) String() string {
+	switch s {
+	case Exited:
+		return "exited"
+	case HungUp:
+		return "hung up"
+	case CallerHungUp:
+		return "caller hung up"
+	case Exhausted:
+		return "exhausted"
+	case Cancelled:
+		return "cancelled"
+	case Failed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+// Event describes what happened at a visit to a node
+type Event string
+
+const (
+	// Played indicates that the prompts of a node without input were played
+	Played Event = "played"
+
+	// Matched indicates that the caller's digits selected an action
+	Matched Event = "matched"
+
+	// Captured indicates that the caller's digits were captured into a variable
+	Captured Event = "captured"
+
+	// TimedOut indicates that the caller entered nothing
+	TimedOut Event = "timeout"
+
+	// InvalidInput indicates that the caller's digits were invalid
+	InvalidInput Event = "invalid"
+)
+
+// Step describes a visit to a node, for the analysis of the caller's path through the menu
+type Step struct {
+	// Node is the name of the node
+	Node string
+
+	// Event describes what happened at the visit
+	Event Event
+
+	// Input is the caller's digits, if any
+	Input string
+
+	// Action is the type of the action which was taken, if any.  It is empty
+	// if the node was retried.
+	Action ActionType
+
+	// Time is the time at which the visit ended
+	Time time.Time
+}
+
+// Result describes the result of the run of a menu
+type Result struct {
+	// Status describes how the run ended
+	Status Status
+
+	// Value is the value of the Exit action which ended the run, if any
+	Value string
+
+	// Node is the name of the last node visited
+	Node string
+
+	// Vars holds the variables of the run
+	Vars map[string]string
+
+	// Path lists the caller's visits to the nodes of the menu, in order
+	Path []Step
+}
+
+// Session is the state of the run of a menu.  It is passed to hooks.
+type Session struct {
+	menu    *Menu
+	o       *Options
+	player  ari.Player
+	channel *ari.ChannelHandle
+
+	node  string
+	vars  map[string]string
+	stack []string
+	path  []Step
+}
+
+// Node returns the name of the current node
+func (s *Session) Node() string {
+	return s.node
+}
+
+// Var returns the value of the given variable
+func (s *Session) Var(name string) string {
+	return s.vars[name]
+}
+
+// SetVar sets the value of the given variable
+func (s *Session) SetVar(name, value string) {
+	s.vars[name] = value
+}
+
+// Channel returns the channel of the caller
+func (s *Session) Channel() *ari.ChannelHandle {
+	return s.channel
+}
+
+// Player returns the player to which prompts are played
+func (s *Session) Player() ari.Player {
+	return s.player
+}
+
+// Path returns the caller's visits to the nodes of the menu so far
+func (s *Session) Path() []Step {
+	return append([]Step(nil), s.path...)
+}
+
+// playback plays the given media URIs to the player with ext/play, waiting
+// for digits afterward if prompt is set.  It is the default PlaybackFunc.
+func playback(ctx context.Context, p ari.Player, uris []string, prompt bool, opts ...play.OptionFunc) (*play.Result, error) {
+	opts = append([]play.OptionFunc{play.URI(uris...)}, opts...)
+
+	if prompt {
+		return play.Prompt(ctx, p, opts...).Result()
+	}
+
+	return play.Play(ctx, p, opts...).Result()
+}
+
+// Run runs the menu, playing its prompts to the given player (usually the
+// channel itself) and collecting the caller's digits from it, until the menu
+// ends.  The channel is hung up by Hangup actions and is passed to hooks.
+func Run(ctx context.Context, m *Menu, p ari.Player, h *ari.ChannelHandle, opts ...OptionFunc) (*Result, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := checkHooks(m, o); err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		menu:    m,
+		o:       o,
+		player:  p,
+		channel: h,
+		node:    m.Start,
+		vars:    make(map[string]string),
+	}
+
+	for k, v := range o.vars {
+		s.vars[k] = v
+	}
+
+	res := new(Result)
+
+	var err error
+
+	res.Status, res.Value, err = s.run(ctx)
+
+	res.Node = s.node
+	res.Vars = s.vars
+	res.Path = s.path
+
+	return res, err
+}
+
+// checkHooks checks that each hook which the menu names is registered
+func checkHooks(m *Menu, o *Options) error {
+	for name, n := range m.Nodes {
+		actions := []*Action{n.Next, n.OnTimeout, n.OnInvalid}
+		for _, a := range n.Actions {
+			actions = append(actions, a)
+		}
+
+		for _, a := range actions {
+			if a == nil || a.Type != Hook {
+				continue
+			}
+
+			if _, ok := o.hooks[a.Target]; !ok {
+				return eris.Errorf("node %q: hook %q is not registered", name, a.Target)
+			}
+		}
+	}
+
+	return nil
+}
+
+// run visits the nodes of the menu until it ends
+func (s *Session) run(ctx context.Context) (Status, string, error) {
+	for steps := 0; ; steps++ {
+		if steps >= s.o.maxSteps {
+			return Failed, "", ErrMaxSteps
+		}
+
+		n, ok := s.menu.Nodes[s.node]
+		if !ok {
+			return Failed, "", eris.Errorf("node %q does not exist", s.node)
+		}
+
+		a, status, err := s.visit(ctx, n)
+		if a == nil {
+			return status, "", err
+		}
+
+		for a.Type == Hook {
+			s.setVars(a)
+
+			name := a.Target
+
+			f, ok := s.o.hooks[name]
+			if !ok {
+				return Failed, "", eris.Errorf("hook %q is not registered", name)
+			}
+
+			a, err = f(ctx, s)
+			if err != nil {
+				return Failed, "", eris.Wrapf(err, "hook %q failed", name)
+			}
+
+			if a == nil {
+				return Exited, "", nil
+			}
+		}
+
+		s.setVars(a)
+
+		switch a.Type {
+		case Goto:
+			s.node = a.Target
+		case Call:
+			s.stack = append(s.stack, s.node)
+			s.node = a.Target
+		case Return:
+			if len(s.stack) == 0 {
+				return Exited, "", nil
+			}
+
+			s.node = s.stack[len(s.stack)-1]
+			s.stack = s.stack[:len(s.stack)-1]
+		case Repeat:
+		case Hangup:
+			if s.channel != nil {
+				if err := s.channel.Hangup(); err != nil {
+					return Failed, "", eris.Wrap(err, "failed to hang up channel")
+				}
+			}
+
+			return HungUp, "", nil
+		case Exit:
+			return Exited, s.expandOne(a.Value), nil
+		default:
+			return Failed, "", eris.Errorf("unknown action type %q", a.Type)
+		}
+	}
+}
+
+// visit plays the node to the caller, retrying it as necessary, and returns
+// the action to be taken.  If the action is nil, the run ends with the
+// returned status.
+func (s *Session) visit(ctx context.Context, n *Node) (*Action, Status, error) {
+	prompt := n.Capture != nil || len(n.Actions) > 0
+
+	if !prompt && len(n.Prompts) == 0 {
+		return s.next(n.Next, Played, "")
+	}
+
+	var invalid bool
+
+	for attempt := 0; ; attempt++ {
+		uris := s.expand(n.Prompts)
+		if invalid {
+			uris = append(s.expand(n.InvalidPrompts), uris...)
+		}
+
+		r, err := s.o.playback(ctx, s.player, uris, prompt, s.playOptions(n)...)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil, Cancelled, ctx.Err()
+		case r != nil && r.Status == play.Hangup:
+			return nil, CallerHungUp, nil
+		case err != nil:
+			return nil, Failed, eris.Wrap(err, "failed to play prompts")
+		}
+
+		if !prompt {
+			return s.next(n.Next, Played, "")
+		}
+
+		event, a := s.evaluate(n, r)
+		if event == Matched || event == Captured {
+			return s.next(a, event, r.DTMF)
+		}
+
+		invalid = event == InvalidInput
+
+		if attempt < n.Retries {
+			s.record(event, r.DTMF, "")
+			continue
+		}
+
+		handler := n.OnTimeout
+		if invalid {
+			handler = n.OnInvalid
+		}
+
+		if handler == nil {
+			s.record(event, r.DTMF, "")
+			return nil, Exhausted, nil
+		}
+
+		return s.next(handler, event, r.DTMF)
+	}
+}
+
+// evaluate determines the outcome of a prompt from its result
+func (s *Session) evaluate(n *Node, r *play.Result) (Event, *Action) {
+	if r.MatchResult != play.Complete {
+		if r.DTMF == "" {
+			return TimedOut, nil
+		}
+
+		return InvalidInput, nil
+	}
+
+	if n.Capture == nil {
+		a, ok := n.Actions[r.DTMF]
+		if !ok {
+			return InvalidInput, nil
+		}
+
+		return Matched, a
+	}
+
+	if n.Capture.Pattern != "" {
+		if ok, _ := regexp.MatchString(n.Capture.Pattern, r.DTMF); !ok {
+			return InvalidInput, nil
+		}
+	}
+
+	s.vars[n.Capture.Variable] = r.DTMF
+
+	return Captured, n.Next
+}
+
+// next records the visit to the current node and returns the given action.
+// A nil action exits the menu.
+func (s *Session) next(a *Action, event Event, input string) (*Action, Status, error) {
+	if a == nil {
+		s.record(event, input, Exit)
+		return nil, Exited, nil
+	}
+
+	s.record(event, input, a.Type)
+
+	return a, Exited, nil
+}
+
+// record records a visit to the current node
+func (s *Session) record(event Event, input string, action ActionType) {
+	s.path = append(s.path, Step{
+		Node:   s.node,
+		Event:  event,
+		Input:  input,
+		Action: action,
+		Time:   time.Now(),
+	})
+}
+
+// setVars sets the variables of the action
+func (s *Session) setVars(a *Action) {
+	for k, v := range a.Set {
+		s.vars[k] = s.expandOne(v)
+	}
+}
+
+// expand expands references to variables in the given media URIs
+func (s *Session) expand(uris []string) []string {
+	ret := make([]string, len(uris))
+	for i, u := range uris {
+		ret[i] = s.expandOne(u)
+	}
+
+	return ret
+}
+
+func (s *Session) expandOne(v string) string {
+	return os.Expand(v, func(name string) string {
+		return s.vars[name]
+	})
+}
+
+// playOptions returns the play options for the prompts of the node
+func (s *Session) playOptions(n *Node) []play.OptionFunc {
+	opts := append([]play.OptionFunc(nil), s.o.playOptions...)
+
+	if n.Timeout > 0 {
+		opts = append(opts, play.DigitTimeouts(time.Duration(n.Timeout), play.DefaultInterDigitTimeout, play.DefaultOverallDigitTimeout))
+	}
+
+	switch c := n.Capture; {
+	case c == nil && len(n.Actions) > 0:
+		keys := make([]string, 0, len(n.Actions))
+		for k := range n.Actions {
+			keys = append(keys, k)
+		}
+
+		opts = append(opts, play.MatchDiscrete(keys))
+	case c == nil:
+	case c.MaxDigits > 0 && c.Terminator != "":
+		opts = append(opts, play.MatchLenOrTerminator(c.MaxDigits, c.Terminator))
+	case c.MaxDigits > 0:
+		opts = append(opts, play.MatchLen(c.MaxDigits))
+	default:
+		opts = append(opts, play.MatchTerminator(c.Terminator))
+	}
+
+	return opts
+}
@@ -0,0 +1,334 @@
+package ivr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+// response is the caller's response to a prompt
+type response struct {
+	dtmf   string
+	match  play.MatchResult
+	status play.Status
+}
+
+// caller answers the playback of prompts with the given responses, and
+// records the URIs which are played
+type caller struct {
+	responses []response
+	played    [][]string
+}
+
+// playback returns the option which replaces the playback of prompts with the caller
+func (c *caller) playback() OptionFunc {
+	return Playback(func(_ context.Context, _ ari.Player, uris []string, prompt bool, _ ...play.OptionFunc) (*play.Result, error) {
+		c.played = append(c.played, uris)
+
+		if !prompt {
+			return &play.Result{Status: play.Finished}, nil
+		}
+
+		if len(c.responses) == 0 {
+			return nil, errors.New("no response")
+		}
+
+		r := c.responses[0]
+		c.responses = c.responses[1:]
+
+		if r.status == play.InProgress {
+			r.status = play.Finished
+		}
+
+		return &play.Result{DTMF: r.dtmf, MatchResult: r.match, Status: r.status}, nil
+	})
+}
+
+func testMenu() *Menu {
+	return &Menu{
+		Start: "main",
+		Nodes: map[string]*Node{
+			"main": {
+				Prompts:        []string{"sound:main-menu"},
+				InvalidPrompts: []string{"sound:option-is-invalid"},
+				Actions: map[string]*Action{
+					"1": {Type: Goto, Target: "account", Set: map[string]string{"dept": "billing"}},
+					"2": {Type: Call, Target: "hours"},
+					"9": {Type: Hangup},
+				},
+				Retries:   1,
+				OnInvalid: &Action{Type: Goto, Target: "goodbye"},
+			},
+			"hours": {
+				Prompts: []string{"sound:our-hours"},
+				Next:    &Action{Type: Return},
+			},
+			"account": {
+				Prompts: []string{"sound:enter-account"},
+				Capture: &Capture{Variable: "account", MaxDigits: 4, Terminator: "#", Pattern: `^\d+$`},
+				Next:    &Action{Type: Hook, Target: "lookup"},
+			},
+			"confirm": {
+				Prompts: []string{"sound:you-entered", "digits:${account}"},
+				Next:    &Action{Type: Exit, Value: "${dept}"},
+			},
+			"goodbye": {
+				Prompts: []string{"sound:goodbye"},
+			},
+		},
+	}
+}
+
+func lookup(_ context.Context, s *Session) (*Action, error) {
+	if s.Var("account") == "1234" {
+		return &Action{Type: Goto, Target: "confirm", Set: map[string]string{"found": "yes"}}, nil
+	}
+
+	return &Action{Type: Goto, Target: "goodbye"}, nil
+}
+
+func events(path []Step) (ret []string) {
+	for _, s := range path {
+		ret = append(ret, s.Node+":"+string(s.Event)+":"+string(s.Action))
+	}
+
+	return
+}
+
+func TestRun(t *testing.T) {
+	c := &caller{
+		responses: []response{
+			{dtmf: "2", match: play.Complete},
+			{dtmf: "", match: play.Incomplete},
+			{dtmf: "1", match: play.Complete},
+			{dtmf: "1234", match: play.Complete},
+		},
+	}
+
+	res, err := Run(context.Background(), testMenu(), nil, nil, c.playback(), WithHook("lookup", lookup))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != Exited || res.Node != "confirm" {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	if res.Vars["account"] != "1234" || res.Vars["found"] != "yes" || res.Vars["dept"] != "billing" {
+		t.Errorf("unexpected vars %v", res.Vars)
+	}
+
+	expected := []string{
+		"main:matched:call",
+		"hours:played:return",
+		"main:timeout:",
+		"main:matched:goto",
+		"account:captured:hook",
+		"confirm:played:exit",
+	}
+	if got := events(res.Path); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected path %v, got %v", expected, got)
+	}
+
+	if got := c.played[len(c.played)-1]; !reflect.DeepEqual(got, []string{"sound:you-entered", "digits:1234"}) {
+		t.Errorf("unexpected final prompts %v", got)
+	}
+}
+
+func TestRunExitValue(t *testing.T) {
+	m := testMenu()
+	m.Start = "confirm"
+
+	c := new(caller)
+
+	res, err := Run(context.Background(), m, nil, nil, c.playback(), WithHook("lookup", lookup), Var("dept", "sales"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != Exited || res.Value != "sales" {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	c := &caller{
+		responses: []response{
+			{dtmf: "5", match: play.Invalid},
+			{dtmf: "7", match: play.Invalid},
+		},
+	}
+
+	res, err := Run(context.Background(), testMenu(), nil, nil, c.playback(), WithHook("lookup", lookup))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != Exited || res.Node != "goodbye" {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	// The retry is preceded by the invalid prompts
+	expected := [][]string{
+		{"sound:main-menu"},
+		{"sound:option-is-invalid", "sound:main-menu"},
+		{"sound:goodbye"},
+	}
+	if !reflect.DeepEqual(c.played, expected) {
+		t.Errorf("expected prompts %v, got %v", expected, c.played)
+	}
+}
+
+func TestRunExhausted(t *testing.T) {
+	c := &caller{
+		responses: []response{
+			{match: play.Incomplete},
+			{match: play.Incomplete},
+		},
+	}
+
+	res, err := Run(context.Background(), testMenu(), nil, nil, c.playback(), WithHook("lookup", lookup))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != Exhausted || len(res.Path) != 2 {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestRunCallerHangup(t *testing.T) {
+	c := &caller{
+		responses: []response{
+			{status: play.Hangup},
+		},
+	}
+
+	res, err := Run(context.Background(), testMenu(), nil, nil, c.playback(), WithHook("lookup", lookup))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != CallerHungUp {
+		t.Errorf("unexpected status %s", res.Status)
+	}
+}
+
+func TestRunHangup(t *testing.T) {
+	c := &caller{
+		responses: []response{
+			{dtmf: "9", match: play.Complete},
+		},
+	}
+
+	key := ari.NewKey(ari.ChannelKey, "ch1")
+
+	channel := &arimocks.Channel{}
+	channel.On("Hangup", key, string(ari.HangupNormal)).Return(nil)
+
+	h := ari.NewChannelHandle(key, channel, nil)
+
+	res, err := Run(context.Background(), testMenu(), h, h, c.playback(), WithHook("lookup", lookup))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != HungUp {
+		t.Errorf("unexpected status %s", res.Status)
+	}
+
+	channel.AssertExpectations(t)
+}
+
+func TestRunMaxSteps(t *testing.T) {
+	m := &Menu{
+		Start: "loop",
+		Nodes: map[string]*Node{
+			"loop": {Next: &Action{Type: Repeat}},
+		},
+	}
+
+	res, err := Run(context.Background(), m, nil, nil, MaxSteps(10))
+	if !errors.Is(err, ErrMaxSteps) {
+		t.Errorf("expected ErrMaxSteps, got %v", err)
+	}
+
+	if res.Status != Failed || len(res.Path) != 10 {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestRunUnregisteredHook(t *testing.T) {
+	if _, err := Run(context.Background(), testMenu(), nil, nil); err == nil {
+		t.Error("expected error for unregistered hook")
+	}
+}
+
+const yamlMenu = `
+start: main
+nodes:
+  main:
+    prompts: ["sound:main-menu"]
+    timeout: 5s
+    retries: 2
+    actions:
+      "1": {type: goto, target: sales}
+      "0": {type: exit, value: operator}
+    on_timeout: {type: goto, target: sales}
+  sales:
+    prompts: ["sound:transferring"]
+    next: {type: exit, value: sales}
+`
+
+func TestParseYAML(t *testing.T) {
+	m, err := ParseYAML([]byte(yamlMenu))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	main := m.Nodes["main"]
+	if main == nil || time.Duration(main.Timeout) != 5*time.Second || main.Retries != 2 {
+		t.Fatalf("unexpected main node %+v", main)
+	}
+
+	if a := main.Actions["0"]; a == nil || a.Type != Exit || a.Value != "operator" {
+		t.Errorf("unexpected action %+v", a)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	m, err := ParseJSON([]byte(`{"start": "a", "nodes": {"a": {"prompts": ["sound:a"], "timeout": "1m"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if time.Duration(m.Nodes["a"].Timeout) != time.Minute {
+		t.Errorf("unexpected timeout %v", m.Nodes["a"].Timeout)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		menu string
+	}{
+		{"no start", `{"start": "x", "nodes": {"a": {}}}`},
+		{"bad target", `{"start": "a", "nodes": {"a": {"next": {"type": "goto", "target": "x"}}}}`},
+		{"bad type", `{"start": "a", "nodes": {"a": {"next": {"type": "jump"}}}}`},
+		{"no prompts", `{"start": "a", "nodes": {"a": {"actions": {"1": {"type": "exit"}}}}}`},
+		{"bad capture", `{"start": "a", "nodes": {"a": {"prompts": ["sound:a"], "capture": {"variable": "v"}}}}`},
+		{"capture and actions", `{"start": "a", "nodes": {"a": {"prompts": ["sound:a"], "capture": {"variable": "v", "max_digits": 4}, "actions": {"1": {"type": "exit"}}}}}`},
+	}
+
+	for _, tc := range tests {
+		if _, err := ParseJSON([]byte(tc.menu)); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package ivr
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"gopkg.in/yaml.v3"
+)
+
+// ActionType describes what is done when an action is taken
+type ActionType string
+
+const (
+	// Goto moves to the target node
+	Goto ActionType = "goto"
+
+	// Call moves to the target node, pushing the current node onto the return
+	// stack, so that a later Return comes back to it
+	Call ActionType = "call"
+
+	// Return moves to the node on the top of the return stack.  If the stack
+	// is empty, the menu exits.
+	Return ActionType = "return"
+
+	// Repeat visits the current node again
+	Repeat ActionType = "repeat"
+
+	// Hook calls the custom action hook named by the target.  The hook returns
+	// the next action to be taken.
+	Hook ActionType = "hook"
+
+	// Hangup hangs up the channel and ends the menu
+	Hangup ActionType = "hangup"
+
+	// Exit ends the menu.  Its value is reported in the result.
+	Exit ActionType = "exit"
+)
+
+// Action describes an action to be taken in response to the caller
+type Action struct {
+	// Type is the type of the action
+	Type ActionType `json:"type" yaml:"type"`
+
+	// Target is the node to which a Goto or Call moves, or the name of the
+	// hook which a Hook calls
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Value is the value with which an Exit ends the menu.  References to
+	// variables are expanded.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Set lists variables which are set when the action is taken.  References
+	// to variables in their values are expanded.
+	Set map[string]string `json:"set,omitempty" yaml:"set,omitempty"`
+}
+
+// Capture describes the capture of digits into a variable
+type Capture struct {
+	// Variable is the name of the variable into which the digits are stored
+	Variable string `json:"variable" yaml:"variable"`
+
+	// MaxDigits is the number of digits after which the capture is complete
+	MaxDigits int `json:"max_digits,omitempty" yaml:"max_digits,omitempty"`
+
+	// Terminator is the digit which completes the capture.  It is not stored.
+	Terminator string `json:"terminator,omitempty" yaml:"terminator,omitempty"`
+
+	// Pattern is an optional regular expression which the captured digits
+	// must match.  Digits which do not match are treated as invalid input.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
+
+// Node is a node of a menu.  A node with Actions is a menu prompt:  the
+// caller's digits select the action to be taken.  A node with a Capture
+// stores the caller's digits in a variable and then takes its Next action; it
+// may not also have Actions.  Any other node plays its prompts and then takes its Next action.
+type Node struct {
+	// Prompts lists the media URIs which are played when the node is
+	// visited.  References to variables (ex. "digits:${account}") are
+	// expanded.
+	Prompts []string `json:"prompts,omitempty" yaml:"prompts,omitempty"`
+
+	// InvalidPrompts lists the media URIs which are played before the
+	// prompts when the node is retried after invalid input
+	InvalidPrompts []string `json:"invalid_prompts,omitempty" yaml:"invalid_prompts,omitempty"`
+
+	// Actions maps the caller's digits to the action which is taken
+	Actions map[string]*Action `json:"actions,omitempty" yaml:"actions,omitempty"`
+
+	// Capture describes the capture of the caller's digits into a variable
+	Capture *Capture `json:"capture,omitempty" yaml:"capture,omitempty"`
+
+	// Next is the action which is taken after the prompts of a node without
+	// Actions are played, or after a capture.  If it is nil, the menu exits.
+	Next *Action `json:"next,omitempty" yaml:"next,omitempty"`
+
+	// Timeout is the amount of time to wait for the first digit after the
+	// prompts are played.  If zero, the default of ext/play is used.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Retries is the number of times the node is retried after a timeout or
+	// invalid input, before its handler is taken
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+
+	// OnTimeout is the action which is taken when the caller enters nothing
+	// and the retries are exhausted.  If it is nil, the menu ends with the
+	// Exhausted status.
+	OnTimeout *Action `json:"on_timeout,omitempty" yaml:"on_timeout,omitempty"`
+
+	// OnInvalid is the action which is taken when the caller's input is
+	// invalid and the retries are exhausted.  If it is nil, the menu ends
+	// with the Exhausted status.
+	OnInvalid *Action `json:"on_invalid,omitempty" yaml:"on_invalid,omitempty"`
+}
+
+// Menu is a graph of nodes
+type Menu struct {
+	// Start is the name of the node at which the menu starts
+	Start string `json:"start" yaml:"start"`
+
+	// Nodes maps the name of each node to the node
+	Nodes map[string]*Node `json:"nodes" yaml:"nodes"`
+}
+
+// Duration is a time.Duration which is represented in JSON and YAML as a
+// string (ex. "5s")
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// ParseJSON parses a menu from its JSON representation
+func ParseJSON(data []byte) (*Menu, error) {
+	m := new(Menu)
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, eris.Wrap(err, "failed to parse menu")
+	}
+
+	return m, m.Validate()
+}
+
+// ParseYAML parses a menu from its YAML representation
+func ParseYAML(data []byte) (*Menu, error) {
+	m := new(Menu)
+
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, eris.Wrap(err, "failed to parse menu")
+	}
+
+	return m, m.Validate()
+}
+
+// LoadFile loads a menu from the given file.  Files with the extension
+// ".json" are parsed as JSON; all others are parsed as YAML.
+func LoadFile(name string) (*Menu, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read menu")
+	}
+
+	if filepath.Ext(name) == ".json" {
+		return ParseJSON(data)
+	}
+
+	return ParseYAML(data)
+}
+
+// Validate checks that the menu is well-formed:  that its start node and
+// the targets of its actions exist, and that its captures are complete.
+// The existence of hooks is checked when the menu is run.
+func (m *Menu) Validate() error {
+	if _, ok := m.Nodes[m.Start]; !ok {
+		return eris.Errorf("start node %q does not exist", m.Start)
+	}
+
+	for name, n := range m.Nodes {
+		if n == nil {
+			return eris.Errorf("node %q is empty", name)
+		}
+
+		if (n.Capture != nil || len(n.Actions) > 0) && len(n.Prompts) == 0 {
+			return eris.Errorf("node %q takes input but has no prompts", name)
+		}
+
+		if n.Capture != nil && len(n.Actions) > 0 {
+			return eris.Errorf("node %q has both a capture and actions", name)
+		}
+
+		if n.Capture != nil {
+			if n.Capture.Variable == "" {
+				return eris.Errorf("node %q: capture has no variable", name)
+			}
+
+			if n.Capture.MaxDigits <= 0 && n.Capture.Terminator == "" {
+				return eris.Errorf("node %q: capture has neither a length nor a terminator", name)
+			}
+
+			if _, err := regexp.Compile(n.Capture.Pattern); err != nil {
+				return eris.Wrapf(err, "node %q: invalid capture pattern", name)
+			}
+		}
+
+		actions := []*Action{n.Next, n.OnTimeout, n.OnInvalid}
+		for _, a := range n.Actions {
+			actions = append(actions, a)
+		}
+
+		for _, a := range actions {
+			if err := m.validateAction(a); err != nil {
+				return eris.Wrapf(err, "node %q", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Menu) validateAction(a *Action) error {
+	if a == nil {
+		return nil
+	}
+
+	switch a.Type {
+	case Goto, Call:
+		if _, ok := m.Nodes[a.Target]; !ok {
+			return eris.Errorf("%s target %q does not exist", a.Type, a.Target)
+		}
+	case Hook:
+		if a.Target == "" {
+			return eris.New("hook has no name")
+		}
+	case Return, Repeat, Hangup, Exit:
+	default:
+		return eris.Errorf("unknown action type %q", a.Type)
+	}
+
+	return nil
+}
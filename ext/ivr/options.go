@@ -0,0 +1,89 @@
+package ivr
+
+import (
+	"context"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+// DefaultMaxSteps is the default maximum number of nodes which a run of a
+// menu may visit, to guard against loops in the menu
+var DefaultMaxSteps = 100
+
+// HookFunc is a custom action hook.  It returns the next action to be
+// taken; if it returns nil, the menu exits.
+type HookFunc func(ctx context.Context, s *Session) (*Action, error)
+
+// PlaybackFunc plays the prompts of a node, given as media URIs, to the
+// player.  If prompt is set, it waits for the caller's digits afterward, as
+// play.Prompt does.
+type PlaybackFunc func(ctx context.Context, p ari.Player, uris []string, prompt bool, opts ...play.OptionFunc) (*play.Result, error)
+
+// Options describes the options for the run of a menu
+type Options struct {
+	hooks map[string]HookFunc
+
+	vars map[string]string
+
+	maxSteps int
+
+	playOptions []play.OptionFunc
+
+	playback PlaybackFunc
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		hooks:    make(map[string]HookFunc),
+		vars:     make(map[string]string),
+		maxSteps: DefaultMaxSteps,
+		playback: playback,
+	}
+}
+
+// Apply applies a set of options for the run of a menu
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// WithHook registers the custom action hook of the given name
+func WithHook(name string, f HookFunc) OptionFunc {
+	return func(o *Options) {
+		o.hooks[name] = f
+	}
+}
+
+// Var sets the initial value of a variable
+func Var(name, value string) OptionFunc {
+	return func(o *Options) {
+		o.vars[name] = value
+	}
+}
+
+// MaxSteps sets the maximum number of nodes which the run may visit
+func MaxSteps(n int) OptionFunc {
+	return func(o *Options) {
+		o.maxSteps = n
+	}
+}
+
+// PlayOptions sets the options which are applied to the playback of the prompts of each node
+func PlayOptions(opts ...play.OptionFunc) OptionFunc {
+	return func(o *Options) {
+		o.playOptions = append(o.playOptions, opts...)
+	}
+}
+
+// Playback replaces the playback of the prompts of each node, which is done
+// with ext/play by default
+func Playback(f PlaybackFunc) OptionFunc {
+	return func(o *Options) {
+		o.playback = f
+	}
+}
@@ -1,16 +1,25 @@
+// Package keyfilter provides declarative filtering of ARI keys, both of the
+// lists of keys returned by `List` operations and of the events received by
+// subscriptions.  Filters are composed from `ari.Matcher`s, such as those
+// returned by `ari.And`, `ari.Or`, `ari.Not`, `ari.IDPrefix`, `ari.IDRegexp`
+// and `ari.KindIn`.
 package keyfilter
 
-import "github.com/CyCoreSystems/ari/v6"
+import (
+	"sync"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// Filter returns the keys from the given list which are matched by the
+// given matcher, preserving their order.
+func Filter(m ari.Matcher, in []*ari.Key) (out []*ari.Key) {
+	return ari.Keys(in).Filter(m)
+}
 
 // Kind filters a list of keys by a particular Kind
 func Kind(kind string, in []*ari.Key) (out []*ari.Key) {
-	for _, k := range in {
-		if k.Kind == kind {
-			out = append(out, k)
-		}
-	}
-
-	return
+	return Filter(ari.KindIn(kind), in)
 }
 
 // Applications returns the Application keys from the given list of keys
@@ -77,3 +86,69 @@ func StoredRecordings(in []*ari.Key) (out []*ari.Key) {
 func Variables(in []*ari.Key) (out []*ari.Key) {
 	return Kind(ari.VariableKey, in)
 }
+
+// Subscribe subscribes to the given event types on the given subscriber,
+// returning a subscription which only receives those events which relate to
+// at least one key matched by the given matcher.
+func Subscribe(sub ari.Subscriber, m ari.Matcher, n ...string) ari.Subscription {
+	s := &subscription{
+		sub:    sub.Subscribe(ari.NewKey("", ""), n...),
+		m:      m,
+		events: make(chan ari.Event, 100),
+		closed: make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// subscription is a subscription which filters the events of an underlying
+// subscription by a matcher
+type subscription struct {
+	sub ari.Subscription
+	m   ari.Matcher
+
+	events chan ari.Event
+	closed chan struct{}
+
+	once sync.Once
+}
+
+func (s *subscription) run() {
+	defer close(s.events)
+
+	for {
+		select {
+		case <-s.closed:
+			return
+		case e, ok := <-s.sub.Events():
+			if !ok {
+				return
+			}
+
+			if len(e.Keys().Filter(s.m)) == 0 {
+				continue
+			}
+
+			select {
+			case s.events <- e:
+			case <-s.closed:
+				return
+			}
+		}
+	}
+}
+
+// Events returns the channel on which matched events are sent
+func (s *subscription) Events() <-chan ari.Event {
+	return s.events
+}
+
+// Cancel terminates the subscription
+func (s *subscription) Cancel() {
+	s.once.Do(func() {
+		close(s.closed)
+		s.sub.Cancel()
+	})
+}
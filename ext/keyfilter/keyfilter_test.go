@@ -0,0 +1,51 @@
+package keyfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/stdbus"
+)
+
+func TestSubscribe(t *testing.T) {
+	bus := stdbus.New()
+	defer bus.Close()
+
+	sub := Subscribe(bus, ari.And(ari.KindIn(ari.ChannelKey), ari.IDPrefix("agent-")), ari.Events.ChannelStateChange)
+	defer sub.Cancel()
+
+	for _, id := range []string{"caller-1", "agent-1"} {
+		bus.Send(&ari.ChannelStateChange{
+			EventData: ari.EventData{Type: ari.Events.ChannelStateChange},
+			Channel:   ari.ChannelData{ID: id},
+		})
+	}
+
+	select {
+	case e := <-sub.Events():
+		if id := e.(*ari.ChannelStateChange).Channel.ID; id != "agent-1" {
+			t.Errorf("Expected event for '%s', got '%s'", "agent-1", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+
+	sub.Cancel()
+
+	if _, ok := <-sub.Events(); ok {
+		t.Errorf("Expected events channel to be closed after Cancel")
+	}
+}
+
+func TestKind(t *testing.T) {
+	in := []*ari.Key{
+		ari.NewKey(ari.ChannelKey, "ch1"),
+		ari.NewKey(ari.BridgeKey, "br1"),
+	}
+
+	out := Bridges(in)
+	if len(out) != 1 || out[0].ID != "br1" {
+		t.Errorf("Expected only bridge key, got %v", out)
+	}
+}
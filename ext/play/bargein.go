@@ -0,0 +1,164 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/chanfunc"
+)
+
+var (
+	// DefaultTalkSilence is the default duration of silence after which the
+	// caller is considered to have stopped talking.  It is Asterisk's default.
+	DefaultTalkSilence = 2500 * time.Millisecond
+
+	// DefaultTalkThreshold is the default average magnitude per sample of the
+	// audio above which the caller is considered to be talking.  It is
+	// Asterisk's default.
+	DefaultTalkThreshold = 256
+)
+
+// ErrNoTalkDetect indicates that speech barge-in was requested for a player
+// which does not support talk detection.  Talk detection requires a channel.
+var ErrNoTalkDetect = errors.New("player does not support talk detection")
+
+// bargeIn describes the speech barge-in parameters of a playback
+type bargeIn struct {
+	// silence is the duration of silence after which talking is considered to have stopped
+	silence time.Duration
+
+	// threshold is the energy level above which talking is detected
+	threshold int
+
+	// minimum is the time which must be played before speech barges in
+	minimum time.Duration
+}
+
+// talkDetector is a player on which talk detection may be enabled
+type talkDetector interface {
+	chanfunc.Variabler
+}
+
+// SpeechBargeIn enables speech barge-in:  when the caller starts talking,
+// the audio is stopped and the session ends with the SpeechDetected status.
+// Talk detection is enabled on the channel (through the TALK_DETECT
+// function) for the duration of the session, so the player must be a
+// channel.  Passing a negative value to any of these indicates that the
+// default value (shown in parentheses below) should be used.
+//
+//   - Silence (2.5 sec):  The duration of silence after which the caller is considered to have stopped talking
+//
+//   - Threshold (256):  The average magnitude per sample of the audio above which the caller is considered to be talking
+//
+//   - Minimum (0):  The time, from the start of the session, during which talking does not barge in, so that the beginning of a prompt is always played.  If the caller is still talking when it ends, the audio is stopped then.
+func SpeechBargeIn(silence time.Duration, threshold int, minimum time.Duration) OptionFunc {
+	return func(o *Options) error {
+		b := &bargeIn{
+			silence:   DefaultTalkSilence,
+			threshold: DefaultTalkThreshold,
+		}
+
+		if silence >= 0 {
+			b.silence = silence
+		}
+
+		if threshold >= 0 {
+			b.threshold = threshold
+		}
+
+		if minimum >= 0 {
+			b.minimum = minimum
+		}
+
+		o.bargeIn = b
+
+		return nil
+	}
+}
+
+// enableTalkDetect enables talk detection on the player, returning a
+// function which restores the talk detection which was in place before
+func (b *bargeIn) enableTalkDetect(d talkDetector) (func(), error) {
+	prev, enabled, err := chanfunc.TalkDetect(d)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to get talk detection")
+	}
+
+	if err := chanfunc.SetTalkDetect(d, fmt.Sprintf("%d,%d", b.silence.Milliseconds(), b.threshold)); err != nil {
+		return nil, eris.Wrap(err, "failed to enable talk detection")
+	}
+
+	return func() {
+		if enabled {
+			_ = chanfunc.SetTalkDetect(d, prev) // nolint
+			return
+		}
+
+		_ = chanfunc.RemoveTalkDetect(d) // nolint
+	}, nil
+}
+
+// listenTalk stops the session when the caller starts talking, once the
+// minimum playback time has passed.  Talking which starts before then
+// stops the session when the minimum time passes, if it has not finished.
+func (s *playSession) listenTalk(ctx context.Context, sub ari.Subscription) {
+	defer sub.Cancel()
+
+	var (
+		talking bool
+
+		// window is closed when the minimum playback time has passed; it is
+		// nil once it has
+		window <-chan time.Time
+	)
+
+	if s.o.bargeIn.minimum > 0 {
+		t := time.NewTimer(s.o.bargeIn.minimum)
+		defer t.Stop()
+
+		window = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-window:
+			window = nil
+
+			if talking {
+				s.speechBargeIn()
+				return
+			}
+		case e := <-sub.Events():
+			switch e.(type) {
+			case nil:
+				return
+			case *ari.ChannelTalkingStarted:
+				talking = true
+			case *ari.ChannelTalkingFinished:
+				talking = false
+			}
+
+			if talking && window == nil {
+				s.speechBargeIn()
+				return
+			}
+		}
+	}
+}
+
+// speechBargeIn ends the session because the caller is talking
+func (s *playSession) speechBargeIn() {
+	s.mu.Lock()
+	s.speechDetected = true
+	s.mu.Unlock()
+
+	// End the session; it is stopped (and its status set) by its own goroutine
+	s.cancel()
+}
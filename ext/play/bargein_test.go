@@ -0,0 +1,222 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+	"github.com/CyCoreSystems/ari/v6/ext/chanfunc"
+)
+
+// talkPlayer is a mock player which supports talk detection
+type talkPlayer struct {
+	*arimocks.Player
+
+	mu   sync.Mutex
+	vars map[string]string
+}
+
+func (p *talkPlayer) ID() string {
+	return "ch1"
+}
+
+func (p *talkPlayer) GetVariable(name string) (string, error) {
+	v, _ := p.variable(name)
+	return v, nil
+}
+
+func (p *talkPlayer) SetVariable(name, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.vars == nil {
+		p.vars = make(map[string]string)
+	}
+
+	p.vars[name] = value
+
+	return nil
+}
+
+func (p *talkPlayer) variable(name string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	v, ok := p.vars[name]
+
+	return v, ok
+}
+
+// waitFor waits up to a second for the condition, which the session may
+// satisfy after its result is available
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+	}
+}
+
+func newTalkTest() (*controlsTest, *talkPlayer, chan ari.Event) {
+	ct := newControlsTest()
+	talk := make(chan ari.Event, 2)
+
+	ct.player.On("Subscribe", ari.Events.ChannelTalkingStarted, ari.Events.ChannelTalkingFinished).Return(subscription(talk))
+
+	return ct, &talkPlayer{Player: ct.player}, talk
+}
+
+func TestSpeechBargeIn(t *testing.T) {
+	ct, p, talk := newTalkTest()
+
+	s := Prompt(context.Background(), p, URI("sound:one", "sound:two"), SpeechBargeIn(-1, -1, -1))
+
+	ct.waitStarted(t, "sound:one")
+
+	if v, ok := p.variable("TALK_DETECT(set)"); !ok || v != "2500,256" {
+		t.Errorf("expected talk detection to be enabled with defaults, got %q", v)
+	}
+
+	talk <- &ari.ChannelTalkingStarted{}
+
+	res, err := s.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != SpeechDetected {
+		t.Errorf("expected status SpeechDetected, got %v", res.Status)
+	}
+
+	select {
+	case u := <-ct.started:
+		t.Errorf("expected no further audio, but %s started", u)
+	default:
+	}
+}
+
+func TestSpeechBargeInRestoresTalkDetect(t *testing.T) {
+	ct, p, talk := newTalkTest()
+
+	// Talk detection was already enabled by someone else, such as a conference
+	if err := chanfunc.SetTalkDetect(p, "1200,300"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := Prompt(context.Background(), p, URI("sound:one"), SpeechBargeIn(-1, -1, -1))
+
+	ct.waitStarted(t, "sound:one")
+
+	talk <- &ari.ChannelTalkingStarted{}
+
+	if _, err := s.Result(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	waitFor(t, "talk detection to be restored", func() bool {
+		v, _ := p.variable("TALK_DETECT(set)")
+		return v == "1200,300"
+	})
+
+	if _, removed := p.variable("TALK_DETECT(remove)"); removed {
+		t.Errorf("expected talk detection to be left enabled")
+	}
+
+	ct, p, talk = newTalkTest()
+	s = Prompt(context.Background(), p, URI("sound:one"), SpeechBargeIn(-1, -1, -1))
+
+	ct.waitStarted(t, "sound:one")
+
+	talk <- &ari.ChannelTalkingStarted{}
+
+	if _, err := s.Result(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	waitFor(t, "talk detection to be removed", func() bool {
+		_, removed := p.variable("TALK_DETECT(remove)")
+		return removed
+	})
+}
+
+func TestSpeechBargeInMinimum(t *testing.T) {
+	ct, p, talk := newTalkTest()
+
+	s := Play(context.Background(), p, URI("sound:one"), SpeechBargeIn(100, 1000, DefaultMaxPlaybackTime))
+
+	ct.waitStarted(t, "sound:one")
+
+	if v, _ := p.variable("TALK_DETECT(set)"); v != "0,1000" {
+		t.Errorf("expected talk detection thresholds 0,1000, got %q", v)
+	}
+
+	talk <- &ari.ChannelTalkingStarted{}
+
+	s.Stop()
+
+	res, _ := s.Result()
+	if res.Status != Cancelled {
+		t.Errorf("expected talking to be ignored before the minimum time, got status %v", res.Status)
+	}
+}
+
+func TestSpeechBargeInAfterMinimum(t *testing.T) {
+	ct, p, talk := newTalkTest()
+
+	s := Play(context.Background(), p, URI("sound:one"), SpeechBargeIn(-1, -1, 50*time.Millisecond))
+
+	ct.waitStarted(t, "sound:one")
+
+	// The caller starts talking within the minimum time and is still talking when it passes
+	talk <- &ari.ChannelTalkingStarted{}
+
+	res, err := s.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != SpeechDetected {
+		t.Errorf("expected status SpeechDetected, got %v", res.Status)
+	}
+}
+
+func TestSpeechBargeInFinishedBeforeMinimum(t *testing.T) {
+	ct, p, talk := newTalkTest()
+
+	s := Play(context.Background(), p, URI("sound:one"), SpeechBargeIn(-1, -1, 50*time.Millisecond))
+
+	ct.waitStarted(t, "sound:one")
+
+	talk <- &ari.ChannelTalkingStarted{}
+	talk <- &ari.ChannelTalkingFinished{}
+
+	time.Sleep(100 * time.Millisecond)
+
+	s.Stop()
+
+	res, _ := s.Result()
+	if res.Status != Cancelled {
+		t.Errorf("expected talking which finished within the minimum time to be ignored, got status %v", res.Status)
+	}
+}
+
+func TestSpeechBargeInUnsupported(t *testing.T) {
+	ct := newControlsTest()
+
+	s := Play(context.Background(), ct.player, URI("sound:one"), SpeechBargeIn(-1, -1, -1))
+
+	res, err := s.Result()
+	if !errors.Is(err, ErrNoTalkDetect) {
+		t.Errorf("expected ErrNoTalkDetect, got %v", err)
+	}
+
+	if res.Status != Failed {
+		t.Errorf("expected status Failed, got %v", res.Status)
+	}
+}
@@ -0,0 +1,234 @@
+package play
+
+import (
+	"errors"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// ErrNotPlaying indicates that a transport control was used while no audio was playing
+var ErrNotPlaying = errors.New("no audio is playing")
+
+// SeekStep is the amount by which Asterisk moves a playback for each
+// reverse or forward operation.  It is Asterisk's default.
+var SeekStep = 3 * time.Second
+
+// Control is a transport control, which may be bound to a DTMF digit (see Controls)
+type Control int
+
+const (
+	// ControlPause pauses the current audio, or resumes it if it is paused
+	ControlPause Control = iota
+
+	// ControlSkip skips to the next audio of the sequence
+	ControlSkip
+
+	// ControlRestart restarts the current audio from its beginning
+	ControlRestart
+
+	// ControlRewind moves the current audio back by one SeekStep
+	ControlRewind
+
+	// ControlForward moves the current audio forward by one SeekStep
+	ControlForward
+)
+
+// Controller is implemented by the sessions of this package.  It provides
+// transport controls for the current audio of a session:
+//
+//	if c, ok := s.(play.Controller); ok {
+//		err = c.Pause()
+//	}
+type Controller interface {
+	// Pause pauses the current audio
+	Pause() error
+
+	// Resume resumes the current audio, if it is paused
+	Resume() error
+
+	// Skip stops the current audio, so that the next audio of the sequence is played
+	Skip() error
+
+	// Restart restarts the current audio from its beginning
+	Restart() error
+
+	// Seek moves the current audio forward (or, if negative, back) by the
+	// given duration, rounded to a multiple of SeekStep
+	Seek(d time.Duration) error
+
+	// Index returns the index, in the URI list, of the current audio, or -1
+	// if none of the URI list is playing
+	Index() int
+}
+
+// Controls binds DTMF digits to transport controls (ex. "*" to ControlRewind
+// and "#" to ControlSkip).  Digits which are bound to a control operate the
+// control; they are not collected and do not stop the audio.
+func Controls(bindings map[string]Control) OptionFunc {
+	return func(o *Options) error {
+		o.controls = bindings
+		return nil
+	}
+}
+
+// current returns the current sequence, or nil if there is none
+func (s *playSession) current() *sequence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.currentSequence
+}
+
+// control performs the given operation on the current audio
+func (s *playSession) control(op string) error {
+	seq := s.current()
+	if seq == nil {
+		return ErrNotPlaying
+	}
+
+	h, _ := seq.Current()
+	if h == nil {
+		return ErrNotPlaying
+	}
+
+	return h.Control(op)
+}
+
+// Pause pauses the current audio
+func (s *playSession) Pause() error {
+	if err := s.control("pause"); err != nil {
+		return err
+	}
+
+	s.current().setPaused(true)
+
+	return nil
+}
+
+// Resume resumes the current audio, if it is paused
+func (s *playSession) Resume() error {
+	if err := s.control("unpause"); err != nil {
+		return err
+	}
+
+	s.current().setPaused(false)
+
+	return nil
+}
+
+// Skip stops the current audio, so that the next audio of the sequence is played
+func (s *playSession) Skip() error {
+	seq := s.current()
+	if seq == nil {
+		return ErrNotPlaying
+	}
+
+	h, _ := seq.Current()
+	if h == nil {
+		return ErrNotPlaying
+	}
+
+	return h.Stop()
+}
+
+// Restart restarts the current audio from its beginning
+func (s *playSession) Restart() error {
+	return s.control("restart")
+}
+
+// Seek moves the current audio forward (or, if negative, back) by the given
+// duration, rounded to a multiple of SeekStep
+func (s *playSession) Seek(d time.Duration) error {
+	op := "forward"
+	if d < 0 {
+		op = "reverse"
+		d = -d
+	}
+
+	n := int((d + SeekStep/2) / SeekStep)
+	if n == 0 {
+		n = 1
+	}
+
+	for i := 0; i < n; i++ {
+		if err := s.control(op); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Index returns the index, in the URI list, of the current audio, or -1 if
+// none of the URI list is playing
+func (s *playSession) Index() int {
+	seq := s.current()
+	if seq == nil {
+		return -1
+	}
+
+	_, i := seq.Current()
+
+	return i
+}
+
+// applyControl operates the given transport control
+func (s *playSession) applyControl(c Control) error {
+	switch c {
+	case ControlPause:
+		if seq := s.current(); seq != nil && seq.paused() {
+			return s.Resume()
+		}
+
+		return s.Pause()
+	case ControlSkip:
+		return s.Skip()
+	case ControlRestart:
+		return s.Restart()
+	case ControlRewind:
+		return s.Seek(-SeekStep)
+	case ControlForward:
+		return s.Seek(SeekStep)
+	}
+
+	return nil
+}
+
+// Current returns the current playback of the sequence and its index in the
+// URI list.  The playback is nil if no audio is playing; the index is -1 if
+// none of the URI list is playing.
+func (s *sequence) Current() (*ari.PlaybackHandle, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.handle, s.index
+}
+
+// setCurrent records the current playback of the sequence
+func (s *sequence) setCurrent(h *ari.PlaybackHandle, index int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handle = h
+	s.index = index
+	s.isPaused = false
+}
+
+func (s *sequence) paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isPaused
+}
+
+func (s *sequence) setPaused(paused bool) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isPaused = paused
+}
@@ -0,0 +1,161 @@
+package play
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+// controlsTest plays a sequence of URIs to a mock player, whose playbacks
+// start when they are executed and finish when they are stopped
+type controlsTest struct {
+	player   *arimocks.Player
+	playback *arimocks.Playback
+
+	dtmf chan ari.Event
+
+	// started receives the URI of each playback as it starts
+	started chan string
+}
+
+func newControlsTest() *controlsTest {
+	ct := &controlsTest{
+		player:   &arimocks.Player{},
+		playback: &arimocks.Playback{},
+		dtmf:     make(chan ari.Event, 10),
+		started:  make(chan string, 10),
+	}
+
+	ct.player.On("Subscribe", ari.Events.ChannelDtmfReceived).Return(subscription(ct.dtmf))
+	ct.player.On("StagePlay", mock.Anything, mock.Anything).Return(
+		func(id string, uris ...string) (*ari.PlaybackHandle, error) {
+			key := ari.NewKey(ari.PlaybackKey, id)
+			started := make(chan ari.Event, 1)
+			finished := make(chan ari.Event, 1)
+
+			ct.playback.On("Subscribe", key, ari.Events.PlaybackStarted).Return(subscription(started))
+			ct.playback.On("Subscribe", key, ari.Events.PlaybackFinished).Return(subscription(finished))
+			ct.playback.On("Control", key, mock.Anything).Return(nil)
+			ct.playback.On("Stop", key).Return(func(_ *ari.Key) error {
+				select {
+				case finished <- &ari.PlaybackFinished{}:
+				default:
+				}
+
+				return nil
+			})
+
+			return ari.NewPlaybackHandle(key, ct.playback, func(_ *ari.PlaybackHandle) error {
+				started <- &ari.PlaybackStarted{}
+				ct.started <- uris[0]
+
+				return nil
+			}), nil
+		})
+
+	return ct
+}
+
+func subscription(events chan ari.Event) *arimocks.Subscription {
+	sub := &arimocks.Subscription{}
+	sub.On("Events").Return((<-chan ari.Event)(events))
+	sub.On("Cancel").Return()
+
+	return sub
+}
+
+func (ct *controlsTest) waitStarted(t *testing.T, uri string) {
+	t.Helper()
+
+	select {
+	case u := <-ct.started:
+		if u != uri {
+			t.Fatalf("expected %s to start, got %s", uri, u)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s to start", uri)
+	}
+}
+
+func TestSessionControls(t *testing.T) {
+	ct := newControlsTest()
+
+	sess := Play(context.Background(), ct.player, URI("sound:one", "sound:two"))
+
+	s, ok := sess.(Controller)
+	if !ok {
+		t.Fatal("expected the session to be a Controller")
+	}
+
+	ct.waitStarted(t, "sound:one")
+
+	if i := s.Index(); i != 0 {
+		t.Errorf("expected index 0, got %d", i)
+	}
+
+	if err := s.Pause(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Resume(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Seek(-7 * time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ct.playback.AssertCalled(t, "Control", mock.Anything, "pause")
+	ct.playback.AssertCalled(t, "Control", mock.Anything, "unpause")
+	ct.playback.AssertNumberOfCalls(t, "Control", 4)
+
+	if err := s.Skip(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ct.waitStarted(t, "sound:two")
+
+	if i := s.Index(); i != 1 {
+		t.Errorf("expected index 1, got %d", i)
+	}
+
+	sess.Stop()
+
+	if i := s.Index(); i != -1 {
+		t.Errorf("expected index -1 after stop, got %d", i)
+	}
+}
+
+func TestDTMFControls(t *testing.T) {
+	ct := newControlsTest()
+
+	s := Play(context.Background(), ct.player,
+		URI("sound:one", "sound:two"),
+		Controls(map[string]Control{"*": ControlRewind, "#": ControlSkip, "5": ControlPause}),
+	)
+
+	ct.waitStarted(t, "sound:one")
+
+	ct.dtmf <- &ari.ChannelDtmfReceived{Digit: "5"}
+	ct.dtmf <- &ari.ChannelDtmfReceived{Digit: "5"}
+	ct.dtmf <- &ari.ChannelDtmfReceived{Digit: "*"}
+	ct.dtmf <- &ari.ChannelDtmfReceived{Digit: "#"}
+
+	ct.waitStarted(t, "sound:two")
+
+	ct.playback.AssertCalled(t, "Control", mock.Anything, "pause")
+	ct.playback.AssertCalled(t, "Control", mock.Anything, "unpause")
+	ct.playback.AssertCalled(t, "Control", mock.Anything, "reverse")
+
+	s.Stop()
+
+	res, _ := s.Result()
+	if res.DTMF != "" {
+		t.Errorf("expected control digits not to be collected, got %q", res.DTMF)
+	}
+}
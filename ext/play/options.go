@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
 )
 
 var (
@@ -82,6 +84,10 @@ const (
 
 	// Timeout indicates that audio playback timed out.  It is not known whether this was due to a failure in the playback, a network loss, or some other problem.
 	Timeout
+
+	// SpeechDetected indicates that the audio playback was interrupted because
+	// the caller started talking (see SpeechBargeIn).
+	SpeechDetected
 )
 
 // MatchResult indicates the status of a match for the received DTMF of a playback
@@ -98,6 +104,18 @@ const (
 	Invalid
 )
 
+// entry is an entry of a URI list:  a media URI, or text which is rendered
+// by the text-to-speech provider when it is played (see Text)
+type entry struct {
+	uri  string
+	text string
+}
+
+// empty indicates that the entry is the zero entry, which marks the end of a list
+func (e entry) empty() bool {
+	return e == entry{}
+}
+
 type uriList struct {
 	list    *list.List
 	current *list.Element
@@ -113,6 +131,15 @@ func (u *uriList) Empty() bool {
 }
 
 func (u *uriList) Add(uri string) {
+	u.push(entry{uri: uri})
+}
+
+// AddText adds text, which is rendered by the text-to-speech provider when it is played
+func (u *uriList) AddText(text string) {
+	u.push(entry{text: text})
+}
+
+func (u *uriList) push(e entry) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
@@ -120,16 +147,16 @@ func (u *uriList) Add(uri string) {
 		u.list = list.New()
 	}
 
-	u.list.PushBack(uri)
+	u.list.PushBack(e)
 
 	if u.current == nil {
 		u.current = u.list.Front()
 	}
 }
 
-func (u *uriList) First() string {
+func (u *uriList) First() entry {
 	if u.list == nil {
-		return ""
+		return entry{}
 	}
 
 	u.mu.Lock()
@@ -140,16 +167,16 @@ func (u *uriList) First() string {
 	return u.val()
 }
 
-func (u *uriList) Next() string {
+func (u *uriList) Next() entry {
 	if u.list == nil {
-		return ""
+		return entry{}
 	}
 
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
 	if u.current == nil {
-		return ""
+		return entry{}
 	}
 
 	u.current = u.current.Next()
@@ -157,14 +184,32 @@ func (u *uriList) Next() string {
 	return u.val()
 }
 
-func (u *uriList) val() string {
+// Items returns a copy of the media URIs of the list.  Texts are left out.
+func (u *uriList) Items() (ret []string) {
+	if u == nil || u.list == nil {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for e := u.list.Front(); e != nil; e = e.Next() {
+		if v, ok := e.Value.(entry); ok && v.uri != "" {
+			ret = append(ret, v.uri)
+		}
+	}
+
+	return ret
+}
+
+func (u *uriList) val() entry {
 	if u.current == nil {
-		return ""
+		return entry{}
 	}
 
-	ret, ok := u.current.Value.(string)
+	ret, ok := u.current.Value.(entry)
 	if !ok {
-		return ""
+		return entry{}
 	}
 
 	return ret
@@ -235,6 +280,25 @@ type Options struct {
 	// replayed if there is no response.  By default, the audio sequence is
 	// played only once.
 	maxReplays int
+
+	// tts is the text-to-speech provider which renders text.  If not
+	// specified, DefaultTTS is used.
+	tts TTS
+
+	// voice is the voice with which text is spoken
+	voice Voice
+
+	// controls binds DTMF digits to transport controls
+	controls map[string]Control
+
+	// bargeIn, if set, enables speech barge-in
+	bargeIn *bargeIn
+
+	// sounds, if set, resolves sound URIs to the language of the caller
+	sounds SoundResolver
+
+	// languages is the language preference of the caller
+	languages []string
 }
 
 // NewDefaultOptions returns a set of options which represent reasonable defaults for most simple playbacks.
@@ -304,6 +368,19 @@ func URI(uri ...string) OptionFunc {
 	}
 }
 
+// Media adds a set of typed media URIs to a playback.  The URIs are
+// validated when the option is applied.
+func Media(uris ...ari.MediaURI) OptionFunc {
+	return func(o *Options) error {
+		list, err := ari.MediaURIs(uris...)
+		if err != nil {
+			return err
+		}
+
+		return URI(list...)(o)
+	}
+}
+
 // InvalidPrependURI sets a prepend URI set to be played when Replay count > 0, such as when
 // a user has entered and invalid DMTF sequence.
 func InvalidPrependURI(uri ...string) OptionFunc {
@@ -330,3 +330,19 @@ func testPlayDtmf(t *testing.T) {
 		t.Errorf("Expected DTMF %s, got DTMF %s", "1", res.DTMF)
 	}
 }
+
+func TestMedia(t *testing.T) {
+	o := NewDefaultOptions()
+
+	if err := o.ApplyOptions(Media(ari.SoundURI("hello-world"), ari.NumberURI(3))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if items := o.uriList.Items(); len(items) != 2 || items[0] != "sound:hello-world" || items[1] != "number:3" {
+		t.Errorf("unexpected URI list %v", items)
+	}
+
+	if err := o.ApplyOptions(Media(ari.DigitsURI("12x"))); err == nil {
+		t.Error("expected error for invalid media URI")
+	}
+}
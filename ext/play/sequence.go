@@ -2,6 +2,7 @@ package play
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/rotisserie/eris"
@@ -16,6 +17,17 @@ type sequence struct {
 	s      *playSession
 
 	done chan struct{}
+
+	mu sync.Mutex
+
+	// handle is the current playback, if there is one
+	handle *ari.PlaybackHandle
+
+	// index is the index of the current playback in the URI list, or -1
+	index int
+
+	// isPaused indicates that the current playback is paused
+	isPaused bool
 }
 
 func (s *sequence) Done() <-chan struct{} {
@@ -30,8 +42,9 @@ func (s *sequence) Stop() {
 
 func newSequence(s *playSession) *sequence {
 	return &sequence{
-		s:    s,
-		done: make(chan struct{}),
+		s:     s,
+		done:  make(chan struct{}),
+		index: -1,
 	}
 }
 
@@ -43,8 +56,16 @@ func (s *sequence) Play(ctx context.Context, p ari.Player, playbackCounter int)
 	defer close(s.done)
 
 	if playbackCounter > 0 && !s.s.o.invalidPrependUriList.Empty() {
-		for u := s.s.o.invalidPrependUriList.First(); u != ""; u = s.s.o.invalidPrependUriList.Next() {
-			pb, err := p.StagePlay(rid.New(rid.Playback), u)
+		for u := s.s.o.invalidPrependUriList.First(); !u.empty(); u = s.s.o.invalidPrependUriList.Next() {
+			uri, err := s.s.o.resolve(ctx, u)
+			if err != nil {
+				s.s.result.Status = Failed
+				s.s.result.Error = err
+
+				return
+			}
+
+			pb, err := p.StagePlay(rid.New(rid.Playback), uri)
 			if err != nil {
 				s.s.result.Status = Failed
 				s.s.result.Error = eris.Wrap(err, "failed to stage playback")
@@ -52,17 +73,35 @@ func (s *sequence) Play(ctx context.Context, p ari.Player, playbackCounter int)
 				return
 			}
 
+			s.setCurrent(pb, -1)
+
 			s.s.result.Status, err = playStaged(ctx, pb, s.s.o.playbackStartTimeout)
 			if err != nil {
 				s.s.result.Error = eris.Wrap(err, "failure in playback")
 
 				return
 			}
+
+			if ctx.Err() != nil {
+				return
+			}
 		}
 	}
 
-	for u := s.s.o.uriList.First(); u != ""; u = s.s.o.uriList.Next() {
-		pb, err := p.StagePlay(rid.New(rid.Playback), u)
+	defer s.setCurrent(nil, -1)
+
+	index := 0
+
+	for u := s.s.o.uriList.First(); !u.empty(); u, index = s.s.o.uriList.Next(), index+1 {
+		uri, err := s.s.o.resolve(ctx, u)
+		if err != nil {
+			s.s.result.Status = Failed
+			s.s.result.Error = err
+
+			return
+		}
+
+		pb, err := p.StagePlay(rid.New(rid.Playback), uri)
 		if err != nil {
 			s.s.result.Status = Failed
 			s.s.result.Error = eris.Wrap(err, "failed to stage playback")
@@ -70,12 +109,18 @@ func (s *sequence) Play(ctx context.Context, p ari.Player, playbackCounter int)
 			return
 		}
 
+		s.setCurrent(pb, index)
+
 		s.s.result.Status, err = playStaged(ctx, pb, s.s.o.playbackStartTimeout)
 		if err != nil {
 			s.s.result.Error = eris.Wrap(err, "failure in playback")
 
 			return
 		}
+
+		if ctx.Err() != nil {
+			return
+		}
 	}
 }
 
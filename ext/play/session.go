@@ -57,6 +57,9 @@ type playSession struct {
 
 	// result is the final result of the playback
 	result *Result
+
+	// speechDetected indicates that the session was stopped by speech barge-in
+	speechDetected bool
 }
 
 type nilSession struct {
@@ -87,6 +90,30 @@ func (n *nilSession) Result() (*Result, error) {
 func (n *nilSession) Stop() {
 }
 
+func (n *nilSession) Pause() error {
+	return ErrNotPlaying
+}
+
+func (n *nilSession) Resume() error {
+	return ErrNotPlaying
+}
+
+func (n *nilSession) Skip() error {
+	return ErrNotPlaying
+}
+
+func (n *nilSession) Restart() error {
+	return ErrNotPlaying
+}
+
+func (n *nilSession) Seek(_ time.Duration) error {
+	return ErrNotPlaying
+}
+
+func (n *nilSession) Index() int {
+	return -1
+}
+
 func errorSession(err error) *nilSession {
 	s := &nilSession{
 		res: new(Result),
@@ -122,12 +149,47 @@ func (s *playSession) play(ctx context.Context, p ari.Player) {
 		return
 	}
 
+	// Check that every sound exists in the language of the caller
+	if err := s.o.checkSounds(ctx, p); err != nil {
+		s.result.Status = Failed
+		s.result.Error = err
+
+		return
+	}
+
 	// cancel if we go over the maximum time
 	go s.watchMaxTime(ctx)
 
 	// Listen for DTMF
 	go s.listenDTMF(ctx, p)
 
+	// Listen for speech
+	if s.o.bargeIn != nil {
+		d, ok := p.(talkDetector)
+		if !ok {
+			s.result.Status = Failed
+			s.result.Error = ErrNoTalkDetect
+
+			return
+		}
+
+		sub := p.Subscribe(ari.Events.ChannelTalkingStarted, ari.Events.ChannelTalkingFinished)
+
+		disable, err := s.o.bargeIn.enableTalkDetect(d)
+		if err != nil {
+			sub.Cancel()
+
+			s.result.Status = Failed
+			s.result.Error = err
+
+			return
+		}
+
+		defer disable()
+
+		go s.listenTalk(ctx, sub)
+	}
+
 	for i := 0; i < s.o.maxReplays+1; i++ {
 		if ctx.Err() != nil {
 			break
@@ -231,8 +293,15 @@ func (s *playSession) Stop() {
 		<-s.currentSequence.Done()
 	}
 
-	// If we have no other status set, set it to Cancelled
-	if s.result.Status == InProgress {
+	s.mu.Lock()
+	speech := s.speechDetected
+	s.mu.Unlock()
+
+	// If we were stopped by speech, the status is SpeechDetected; otherwise,
+	// if we have no other status set, set it to Cancelled
+	if speech && s.result.Error == nil {
+		s.result.Status = SpeechDetected
+	} else if s.result.Status == InProgress {
 		s.result.Status = Cancelled
 	}
 
@@ -278,6 +347,13 @@ func (s *playSession) listenDTMF(ctx context.Context, p ari.Player) {
 				continue
 			}
 
+			if c, ok := s.o.controls[v.Digit]; ok {
+				// Errors are ignored; there may be no audio to control
+				_ = s.applyControl(c) // nolint
+
+				continue
+			}
+
 			s.result.mu.Lock()
 			s.result.DTMF += v.Digit
 			s.result.mu.Unlock()
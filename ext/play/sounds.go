@@ -0,0 +1,201 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// ErrSoundNotFound indicates that a sound does not exist in any of the
+// languages in which it was sought
+var ErrSoundNotFound = errors.New("sound not found")
+
+// SoundResolver resolves "sound:" URIs to the language of the caller
+type SoundResolver interface {
+	// ResolveSound returns the media URI by which the given sound (the ID of
+	// a "sound:" URI) is played in the first of the given languages in which
+	// it exists.  If it exists in none of them, ErrSoundNotFound is returned.
+	ResolveSound(ctx context.Context, id string, languages []string) (string, error)
+}
+
+// WithSoundResolver sets the resolver by which each "sound:" URI of the
+// playback is checked and resolved to the language of the caller (see
+// Language).  All sounds are checked when the session starts, so a missing
+// sound fails the session immediately, rather than by a playback start
+// timeout.
+func WithSoundResolver(r SoundResolver) OptionFunc {
+	return func(o *Options) error {
+		o.sounds = r
+		return nil
+	}
+}
+
+// Language sets the language preference of the caller (ex. "fr_CA", "fr"),
+// in order, to which sounds are resolved (see WithSoundResolver).  If not
+// given, the language of the channel is used.
+func Language(languages ...string) OptionFunc {
+	return func(o *Options) error {
+		o.languages = languages
+		return nil
+	}
+}
+
+// channelData is a player which describes a channel
+type channelData interface {
+	Data() (*ari.ChannelData, error)
+}
+
+// checkSounds resolves the language preference of the caller and checks
+// that every sound of the playback exists in it
+func (o *Options) checkSounds(ctx context.Context, p ari.Player) error {
+	if o.sounds == nil {
+		return nil
+	}
+
+	if len(o.languages) == 0 {
+		if c, ok := p.(channelData); ok {
+			data, err := c.Data()
+			if err != nil {
+				return eris.Wrap(err, "failed to get channel language")
+			}
+
+			if data.Language != "" {
+				o.languages = []string{data.Language}
+			}
+		}
+	}
+
+	for _, u := range append(o.invalidPrependUriList.Items(), o.uriList.Items()...) {
+		if _, err := o.resolveSound(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveSound returns the media URI by which the given URI is played in
+// the language of the caller.  URIs other than sounds are returned
+// unchanged.
+func (o *Options) resolveSound(ctx context.Context, u string) (string, error) {
+	id, ok := strings.CutPrefix(u, "sound:")
+	if !ok || o.sounds == nil {
+		return u, nil
+	}
+
+	return o.sounds.ResolveSound(ctx, id, o.languages)
+}
+
+// SoundCatalog is a SoundResolver which checks sounds against the catalogue
+// of the Asterisk server.  The sounds of each language are listed when they
+// are first needed and cached until the catalogue is refreshed.
+type SoundCatalog struct {
+	sound    ari.Sound
+	fallback []string
+
+	mu sync.Mutex
+
+	// languages holds the set of sound IDs for each listed language
+	languages map[string]map[string]struct{}
+}
+
+// NewSoundCatalog returns a catalogue of the sounds of the Asterisk server.
+// Sounds which do not exist in the language of the caller are sought in the
+// fallback languages, in order (ex. "fr", "en").
+func NewSoundCatalog(sound ari.Sound, fallback ...string) *SoundCatalog {
+	return &SoundCatalog{
+		sound:     sound,
+		fallback:  fallback,
+		languages: make(map[string]map[string]struct{}),
+	}
+}
+
+// Refresh discards the cached catalogue, so that it is listed again
+func (c *SoundCatalog) Refresh() {
+	c.mu.Lock()
+	c.languages = make(map[string]map[string]struct{})
+	c.mu.Unlock()
+}
+
+// Exists indicates whether the given sound exists in the given language
+func (c *SoundCatalog) Exists(id, language string) (bool, error) {
+	ids, err := c.list(language)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := ids[id]
+
+	return ok, nil
+}
+
+// ResolveSound implements SoundResolver.  The languages of the caller are
+// tried first, followed by the fallback languages of the catalogue.  A sound
+// which exists in the first language of the caller is played as it is,
+// leaving the choice of file to Asterisk; otherwise, it is played from the
+// directory of the language in which it was found (ex. "sound:en/hello-world").
+func (c *SoundCatalog) ResolveSound(ctx context.Context, id string, languages []string) (string, error) {
+	var chain []string
+
+	for _, list := range [][]string{languages, c.fallback} {
+		for _, l := range list {
+			if l != "" && !slices.Contains(chain, l) {
+				chain = append(chain, l)
+			}
+		}
+	}
+
+	for _, l := range chain {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		ok, err := c.Exists(id, l)
+		if err != nil {
+			return "", err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if len(languages) > 0 && l == languages[0] {
+			return "sound:" + id, nil
+		}
+
+		return "sound:" + l + "/" + id, nil
+	}
+
+	return "", eris.Wrapf(ErrSoundNotFound, "sound %s not found in languages %v", id, chain)
+}
+
+// list returns the set of sound IDs of the given language, listing them if
+// they are not cached
+func (c *SoundCatalog) list(language string) (map[string]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ids, ok := c.languages[language]; ok {
+		return ids, nil
+	}
+
+	keys, err := c.sound.List(map[string]string{"lang": language}, nil)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to list sounds for language %s", language)
+	}
+
+	ids := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		ids[k.ID] = struct{}{}
+	}
+
+	c.languages[language] = ids
+
+	return ids, nil
+}
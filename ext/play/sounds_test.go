@@ -0,0 +1,113 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+func soundCatalog() (*SoundCatalog, *arimocks.Sound) {
+	keys := func(ids ...string) (ret []*ari.Key) {
+		for _, id := range ids {
+			ret = append(ret, ari.NewKey(ari.SoundKey, id))
+		}
+
+		return ret
+	}
+
+	sound := &arimocks.Sound{}
+	sound.On("List", map[string]string{"lang": "en"}, (*ari.Key)(nil)).Return(keys("hello-world", "goodbye", "digits/1"), nil)
+	sound.On("List", map[string]string{"lang": "fr"}, (*ari.Key)(nil)).Return(keys("hello-world"), nil)
+	sound.On("List", map[string]string{"lang": "fr_CA"}, (*ari.Key)(nil)).Return(nil, nil)
+
+	return NewSoundCatalog(sound, "fr", "en"), sound
+}
+
+func TestSoundCatalog(t *testing.T) {
+	c, sound := soundCatalog()
+
+	tests := []struct {
+		id        string
+		languages []string
+		expected  string
+	}{
+		{"hello-world", []string{"fr"}, "sound:hello-world"},
+		{"hello-world", []string{"fr_CA"}, "sound:fr/hello-world"},
+		{"goodbye", []string{"fr_CA", "fr"}, "sound:en/goodbye"},
+		{"digits/1", nil, "sound:en/digits/1"},
+		{"hello-world", nil, "sound:fr/hello-world"},
+	}
+
+	for _, tc := range tests {
+		uri, err := c.ResolveSound(context.Background(), tc.id, tc.languages)
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %v", tc.id, tc.languages, err)
+			continue
+		}
+
+		if uri != tc.expected {
+			t.Errorf("%s %v: expected %s, got %s", tc.id, tc.languages, tc.expected, uri)
+		}
+	}
+
+	if _, err := c.ResolveSound(context.Background(), "missing", []string{"fr"}); !errors.Is(err, ErrSoundNotFound) {
+		t.Errorf("expected ErrSoundNotFound, got %v", err)
+	}
+
+	// Each language is listed only once
+	sound.AssertNumberOfCalls(t, "List", 3)
+
+	c.Refresh()
+
+	if _, err := c.ResolveSound(context.Background(), "hello-world", []string{"fr"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	sound.AssertNumberOfCalls(t, "List", 4)
+}
+
+func TestPlaySoundResolver(t *testing.T) {
+	c, _ := soundCatalog()
+	ct := newControlsTest()
+
+	s := Play(context.Background(), ct.player,
+		URI("sound:hello-world", "sound:goodbye"),
+		WithSoundResolver(c),
+		Language("fr"),
+	)
+
+	ct.waitStarted(t, "sound:hello-world")
+
+	if err := s.(Controller).Skip(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ct.waitStarted(t, "sound:en/goodbye")
+
+	s.Stop()
+}
+
+func TestPlaySoundResolverMissing(t *testing.T) {
+	c, _ := soundCatalog()
+	ct := newControlsTest()
+
+	s := Play(context.Background(), ct.player,
+		URI("sound:hello-world", "sound:missing"),
+		WithSoundResolver(c),
+		Language("fr"),
+	)
+
+	res, err := s.Result()
+	if !errors.Is(err, ErrSoundNotFound) {
+		t.Errorf("expected ErrSoundNotFound, got %v", err)
+	}
+
+	if res.Status != Failed {
+		t.Errorf("expected status Failed, got %v", res.Status)
+	}
+
+	ct.player.AssertNumberOfCalls(t, "StagePlay", 0)
+}
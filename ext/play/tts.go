@@ -0,0 +1,90 @@
+package play
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rotisserie/eris"
+)
+
+// DefaultTTS is the text-to-speech provider which renders text (see Text)
+// when no provider is given to the playback (see WithTTS)
+var DefaultTTS TTS
+
+// Voice describes the voice with which text is spoken
+type Voice struct {
+	// Name is the name of the voice, as known to the provider.  If empty, the
+	// provider chooses the voice.
+	Name string
+
+	// Language is the language (ex. "en-US") of the text
+	Language string
+}
+
+// TTS is a text-to-speech provider, which renders text as a playable media URI
+type TTS interface {
+	// URI renders the text, spoken by the given voice, and returns the media
+	// URI (ex. "sound:/var/lib/tts/8f3c...") by which it may be played
+	URI(ctx context.Context, text string, voice Voice) (string, error)
+}
+
+// WithTTS sets the text-to-speech provider which renders text (see Text).
+// By default, DefaultTTS is used.
+func WithTTS(p TTS) OptionFunc {
+	return func(o *Options) error {
+		o.tts = p
+		return nil
+	}
+}
+
+// WithVoice sets the voice with which text (see Text) is spoken
+func WithVoice(v Voice) OptionFunc {
+	return func(o *Options) error {
+		o.voice = v
+		return nil
+	}
+}
+
+// Text adds a set of texts to a playback.  Each is rendered by the
+// text-to-speech provider (see WithTTS) when it is played, so texts may be
+// freely mixed with the URIs of the playback.
+func Text(text ...string) OptionFunc {
+	return func(o *Options) error {
+		if o.uriList == nil {
+			o.uriList = new(uriList)
+		}
+
+		for _, t := range text {
+			if t != "" {
+				o.uriList.AddText(t)
+			}
+		}
+
+		return nil
+	}
+}
+
+// resolve returns the media URI for an entry of a URI list, rendering it
+// with the text-to-speech provider if it is text, or resolving it to the
+// language of the caller if it is a sound
+func (o *Options) resolve(ctx context.Context, e entry) (string, error) {
+	if e.text == "" {
+		return o.resolveSound(ctx, e.uri)
+	}
+
+	p := o.tts
+	if p == nil {
+		p = DefaultTTS
+	}
+
+	if p == nil {
+		return "", errors.New("no text-to-speech provider")
+	}
+
+	uri, err := p.URI(ctx, e.text, o.voice)
+	if err != nil {
+		return "", eris.Wrap(err, "failed to render text")
+	}
+
+	return uri, nil
+}
@@ -0,0 +1,47 @@
+package play
+
+import (
+	"context"
+	"testing"
+)
+
+type testTTS struct{}
+
+func (testTTS) URI(_ context.Context, text string, voice Voice) (string, error) {
+	return "sound:tts/" + voice.Language + "/" + text, nil
+}
+
+func TestText(t *testing.T) {
+	o := NewDefaultOptions()
+
+	if err := o.ApplyOptions(URI("sound:hello"), Text("your balance is"), WithTTS(testTTS{}), WithVoice(Voice{Language: "en"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+
+	for u := o.uriList.First(); !u.empty(); u = o.uriList.Next() {
+		uri, err := o.resolve(context.Background(), u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got = append(got, uri)
+	}
+
+	if len(got) != 2 || got[0] != "sound:hello" || got[1] != "sound:tts/en/your balance is" {
+		t.Errorf("unexpected URIs %v", got)
+	}
+}
+
+func TestTextNoProvider(t *testing.T) {
+	o := NewDefaultOptions()
+
+	if err := o.ApplyOptions(Text("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := o.resolve(context.Background(), o.uriList.First()); err == nil {
+		t.Error("expected error without a provider")
+	}
+}
@@ -0,0 +1,288 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+)
+
+// AgentState describes the availability of an agent
+type AgentState int
+
+const (
+	// AgentAvailable indicates that the agent may be offered a caller
+	AgentAvailable AgentState = iota
+
+	// AgentRinging indicates that the agent is being offered a caller
+	AgentRinging
+
+	// AgentOnCall indicates that the agent is talking with a caller of the queue
+	AgentOnCall
+
+	// AgentWrapUp indicates that the agent has recently finished a queue call
+	// and is not yet offered another
+	AgentWrapUp
+
+	// AgentBusy indicates that the agent's device is in use, outside of the queue
+	AgentBusy
+
+	// AgentOffline indicates that the agent's endpoint is offline or its device is unavailable
+	AgentOffline
+)
+
+// String implements fmt.Stringer
+func (s AgentState) String() string {
+	switch s {
+	case AgentAvailable:
+		return "available"
+	case AgentRinging:
+		return "ringing"
+	case AgentOnCall:
+		return "on call"
+	case AgentWrapUp:
+		return "wrap-up"
+	case AgentBusy:
+		return "busy"
+	case AgentOffline:
+		return "offline"
+	}
+
+	return "unknown"
+}
+
+// Agent describes an agent of the queue
+type Agent struct {
+	// Endpoint is the endpoint (tech/resource) of the agent
+	Endpoint string
+
+	// State is the availability of the agent
+	State AgentState
+
+	// Calls is the number of queue calls which the agent has taken
+	Calls int
+
+	// LastCall is the time at which the agent's last queue call ended
+	LastCall time.Time
+}
+
+// agent tracks the state of an agent
+type agent struct {
+	endpoint string
+
+	// endpointState is the state of the endpoint, from EndpointStateChange
+	endpointState string
+
+	// deviceState is the state of the device, from DeviceStateChanged
+	deviceState string
+
+	// state is the queue's own view of the agent:  one of AgentAvailable,
+	// AgentRinging, AgentOnCall, or AgentWrapUp
+	state AgentState
+
+	calls    int
+	lastCall time.Time
+
+	// retryAt is the time before which the agent is passed over, since it did not answer
+	retryAt time.Time
+}
+
+// State returns the availability of the agent
+func (a *agent) State() AgentState {
+	if a.state != AgentAvailable {
+		return a.state
+	}
+
+	if a.endpointState == "offline" {
+		return AgentOffline
+	}
+
+	switch a.deviceState {
+	case "", "NOT_INUSE", "UNKNOWN":
+		return AgentAvailable
+	case "UNAVAILABLE", "INVALID":
+		return AgentOffline
+	}
+
+	return AgentBusy
+}
+
+// available indicates whether the agent may be offered a caller
+func (a *agent) available(now time.Time) bool {
+	return a.State() == AgentAvailable && !now.Before(a.retryAt)
+}
+
+func (a *agent) snapshot() Agent {
+	return Agent{
+		Endpoint: a.endpoint,
+		State:    a.State(),
+		Calls:    a.calls,
+		LastCall: a.lastCall,
+	}
+}
+
+// AddAgent adds the given endpoint (tech/resource) as an agent of the queue.
+// The application is subscribed to the endpoint and its device, so that the
+// availability of the agent may be tracked.
+func (q *Queue) AddAgent(endpoint string) error {
+	tech, resource, ok := strings.Cut(endpoint, "/")
+	if !ok {
+		return errors.New("endpoint is not in tech/resource format")
+	}
+
+	data, err := q.client.Endpoint().Data(ari.NewEndpointKey(tech, resource))
+	if err != nil {
+		return eris.Wrapf(err, "failed to get endpoint %s", endpoint)
+	}
+
+	app := q.client.Application()
+	appKey := ari.NewKey(ari.ApplicationKey, q.client.ApplicationName())
+
+	if err := app.Subscribe(appKey, "endpoint:"+endpoint); err != nil {
+		return eris.Wrapf(err, "failed to subscribe to endpoint %s", endpoint)
+	}
+
+	// Not every device has a device state which may be subscribed; the
+	// state of the endpoint suffices for those which do not
+	_ = app.Subscribe(appKey, "deviceState:"+endpoint) // nolint
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.findAgent(endpoint) != nil {
+		return nil
+	}
+
+	q.agents = append(q.agents, &agent{
+		endpoint:      endpoint,
+		endpointState: data.State,
+	})
+
+	q.dispatch()
+
+	return nil
+}
+
+// RemoveAgent removes the given endpoint from the agents of the queue.  A
+// call which the agent is taking is not affected.
+func (q *Queue) RemoveAgent(endpoint string) {
+	q.mu.Lock()
+
+	for i, a := range q.agents {
+		if a.endpoint == endpoint {
+			q.agents = append(q.agents[:i], q.agents[i+1:]...)
+			break
+		}
+	}
+
+	q.mu.Unlock()
+
+	app := q.client.Application()
+	appKey := ari.NewKey(ari.ApplicationKey, q.client.ApplicationName())
+
+	_ = app.Unsubscribe(appKey, "endpoint:"+endpoint)    // nolint
+	_ = app.Unsubscribe(appKey, "deviceState:"+endpoint) // nolint
+}
+
+// Agents returns the agents of the queue, in the order in which they were added
+func (q *Queue) Agents() []Agent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ret := make([]Agent, len(q.agents))
+	for i, a := range q.agents {
+		ret[i] = a.snapshot()
+	}
+
+	return ret
+}
+
+// findAgent returns the agent with the given endpoint.  It must be called with the lock held.
+func (q *Queue) findAgent(endpoint string) *agent {
+	for _, a := range q.agents {
+		if a.endpoint == endpoint {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// monitorAgents tracks the state of the endpoints and devices of the agents
+func (q *Queue) monitorAgents() {
+	for v := range q.agentSub.Events() {
+		q.mu.Lock()
+
+		switch e := v.(type) {
+		case *ari.DeviceStateChanged:
+			if a := q.findAgent(e.DeviceState.Name); a != nil {
+				a.deviceState = e.DeviceState.State
+			}
+		case *ari.EndpointStateChange:
+			if a := q.findAgent(e.Endpoint.Technology + "/" + e.Endpoint.Resource); a != nil {
+				a.endpointState = e.Endpoint.State
+			}
+		}
+
+		q.dispatch()
+
+		q.mu.Unlock()
+	}
+}
+
+// pick chooses the agents to be offered a caller, according to the strategy,
+// and marks them as ringing.  It must be called with the lock held.
+func (q *Queue) pick() []*agent {
+	now := time.Now()
+
+	var ret []*agent
+
+	switch q.o.strategy {
+	case RingAll:
+		for _, a := range q.agents {
+			if a.available(now) {
+				ret = append(ret, a)
+			}
+		}
+	case RoundRobin:
+		for i := range q.agents {
+			n := (q.next + i) % len(q.agents)
+			if q.agents[n].available(now) {
+				ret = append(ret, q.agents[n])
+				q.next = n + 1
+
+				break
+			}
+		}
+	case LeastRecent, FewestCalls:
+		var best *agent
+
+		for _, a := range q.agents {
+			if a.available(now) && (best == nil || q.better(a, best)) {
+				best = a
+			}
+		}
+
+		if best != nil {
+			ret = append(ret, best)
+		}
+	}
+
+	for _, a := range ret {
+		a.state = AgentRinging
+	}
+
+	return ret
+}
+
+// better indicates whether agent a is preferred to agent b by the strategy
+func (q *Queue) better(a, b *agent) bool {
+	if q.o.strategy == FewestCalls {
+		return a.calls < b.calls
+	}
+
+	return a.lastCall.Before(b.lastCall)
+}
@@ -0,0 +1,163 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+)
+
+// DefaultRingTimeout is the default amount of time to ring an agent before
+// offering the caller to another
+var DefaultRingTimeout = 15 * time.Second
+
+// DefaultRetryDelay is the default amount of time for which an agent which
+// did not answer is passed over
+var DefaultRetryDelay = 5 * time.Second
+
+// Strategy describes how waiting callers are distributed to agents
+type Strategy int
+
+const (
+	// RingAll rings all available agents at once
+	RingAll Strategy = iota
+
+	// RoundRobin rings one agent at a time, taking turns in the order in which
+	// the agents were added
+	RoundRobin
+
+	// LeastRecent rings the agent whose last queue call ended the longest time ago
+	LeastRecent
+
+	// FewestCalls rings the agent which has taken the fewest queue calls
+	FewestCalls
+)
+
+// String implements fmt.Stringer
+func (s Strategy) String() string {
+	switch s {
+	case RingAll:
+		return "ringall"
+	case RoundRobin:
+		return "roundrobin"
+	case LeastRecent:
+		return "leastrecent"
+	case FewestCalls:
+		return "fewestcalls"
+	}
+
+	return "unknown"
+}
+
+// Options describes the options for a Queue
+type Options struct {
+	strategy Strategy
+
+	mohClass string
+
+	ringTimeout time.Duration
+	retryDelay  time.Duration
+	wrapUp      time.Duration
+
+	maxWait  time.Duration
+	overflow string
+
+	announceInterval time.Duration
+	announceHoldTime bool
+
+	dialOptions []dial.OptionFunc
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		strategy:    RingAll,
+		ringTimeout: DefaultRingTimeout,
+		retryDelay:  DefaultRetryDelay,
+	}
+}
+
+// Apply applies a set of options for the Queue
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// WithStrategy sets the strategy by which callers are distributed to agents.  The default is RingAll.
+func WithStrategy(s Strategy) OptionFunc {
+	return func(o *Options) {
+		o.strategy = s
+	}
+}
+
+// MusicOnHold sets the music on hold class which is played to waiting
+// callers.  By default, the default class is played.
+func MusicOnHold(class string) OptionFunc {
+	return func(o *Options) {
+		o.mohClass = class
+	}
+}
+
+// RingTimeout sets the amount of time to ring an agent before offering the caller to another
+func RingTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.ringTimeout = d
+	}
+}
+
+// RetryDelay sets the amount of time for which an agent which did not
+// answer is passed over
+func RetryDelay(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.retryDelay = d
+	}
+}
+
+// WrapUp sets the amount of time after the end of a queue call during which
+// the agent is not offered another
+func WrapUp(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.wrapUp = d
+	}
+}
+
+// MaxWait sets the maximum amount of time for which a caller waits in the
+// queue.  When it elapses, the caller is sent to the overflow destination,
+// if there is one (see Overflow).  Zero, the default, means no limit.
+func MaxWait(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.maxWait = d
+	}
+}
+
+// Overflow sets the endpoint (tech/resource) which is dialed for a caller
+// which has waited for the maximum amount of time (see MaxWait)
+func Overflow(target string) OptionFunc {
+	return func(o *Options) {
+		o.overflow = target
+	}
+}
+
+// Announcements enables the periodic announcement of their position in the
+// queue to waiting callers, at the given interval
+func Announcements(interval time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.announceInterval = interval
+	}
+}
+
+// AnnounceHoldTime adds the estimated wait to the periodic announcements (see Announcements)
+func AnnounceHoldTime() OptionFunc {
+	return func(o *Options) {
+		o.announceHoldTime = true
+	}
+}
+
+// DialOptions sets the options which are applied to the dial of each agent (ex. dial.CallerID)
+func DialOptions(opts ...dial.OptionFunc) OptionFunc {
+	return func(o *Options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
@@ -0,0 +1,769 @@
+// Package queue provides call queues:  callers wait in a holding bridge
+// until they may be distributed to one of a set of agents.
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/audiouri"
+	"github.com/CyCoreSystems/ari/v6/ext/hunt"
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+	"github.com/CyCoreSystems/ari/v6/rid"
+)
+
+// ErrClosed indicates that the queue has been closed
+var ErrClosed = errors.New("queue is closed")
+
+// Outcome describes the result of a caller's stay in the queue
+type Outcome int
+
+const (
+	// Connected indicates that the caller was connected with an agent
+	Connected Outcome = iota
+
+	// Abandoned indicates that the caller hung up while waiting
+	Abandoned
+
+	// Overflowed indicates that the caller waited for the maximum amount of
+	// time and was connected with the overflow destination
+	Overflowed
+
+	// TimedOut indicates that the caller waited for the maximum amount of
+	// time and was not connected with an overflow destination
+	TimedOut
+
+	// Cancelled indicates that the wait was cancelled by the context, or by the closure of the queue
+	Cancelled
+
+	// Failed indicates that the caller could not be queued or connected.  The error describes why.
+	Failed
+)
+
+// String implements fmt.Stringer
+func (o Outcome) String() string {
+	switch o {
+	case Connected:
+		return "connected"
+	case Abandoned:
+		return "abandoned"
+	case Overflowed:
+		return "overflowed"
+	case TimedOut:
+		return "timed out"
+	case Cancelled:
+		return "cancelled"
+	case Failed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+// Result describes the result of a caller's stay in the queue
+type Result struct {
+	// Outcome is the outcome of the caller's stay
+	Outcome Outcome
+
+	// Position is the position (from 1) at which the caller entered the queue
+	Position int
+
+	// Wait is the amount of time for which the caller waited
+	Wait time.Duration
+
+	// Agent is the endpoint of the agent with which the caller was connected, if any
+	Agent string
+
+	// AgentChannel is the channel of the agent (or of the overflow
+	// destination) with which the caller was connected, if any
+	AgentChannel *ari.ChannelHandle
+
+	// Bridge is the bridge in which the caller and the agent (or the overflow
+	// destination) were joined, if any
+	Bridge *ari.BridgeHandle
+}
+
+// Stats describes the statistics of the queue
+type Stats struct {
+	// Waiting is the number of callers currently waiting
+	Waiting int
+
+	// LongestWait is the amount of time for which the longest-waiting caller has waited
+	LongestWait time.Duration
+
+	// Entered is the number of callers which have entered the queue
+	Entered int
+
+	// Connected is the number of callers which were connected with an agent
+	Connected int
+
+	// Abandoned is the number of callers which hung up while waiting
+	Abandoned int
+
+	// Overflowed is the number of callers which were sent to the overflow destination
+	Overflowed int
+
+	// TimedOut is the number of callers which waited for the maximum amount
+	// of time and could not be sent to an overflow destination
+	TimedOut int
+
+	// AverageWait is the average amount of time for which connected callers waited
+	AverageWait time.Duration
+
+	// AverageTalk is the average duration of completed queue calls
+	AverageTalk time.Duration
+
+	// AgentsAvailable is the number of agents which may currently be offered a caller
+	AgentsAvailable int
+}
+
+// caller tracks a caller in the queue
+type caller struct {
+	h       *ari.ChannelHandle
+	ctx     context.Context
+	entered time.Time
+
+	// offering indicates that agents are being rung for the caller
+	offering    bool
+	cancelOffer context.CancelFunc
+
+	// gone indicates that the caller has left the queue
+	gone bool
+
+	// connected receives the result of the connection of the caller with an agent
+	connected chan connection
+}
+
+// connection describes the connection of a caller with an agent
+type connection struct {
+	agent   string
+	channel *ari.ChannelHandle
+	bridge  *ari.BridgeHandle
+	err     error
+}
+
+// Queue is a call queue
+type Queue struct {
+	client ari.Client
+	o      *Options
+
+	bridge   *ari.BridgeHandle
+	agentSub ari.Subscription
+
+	closed chan struct{}
+
+	mu      sync.Mutex
+	callers []*caller
+	agents  []*agent
+
+	// next is the index of the next agent to be rung by the RoundRobin strategy
+	next int
+
+	stats     Stats
+	totalWait time.Duration
+	talks     int
+	totalTalk time.Duration
+}
+
+// New creates a new queue, backed by a holding bridge with the given key.
+// If the key is nil, a new bridge ID is generated.
+func New(client ari.Client, key *ari.Key, opts ...OptionFunc) (*Queue, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	if key == nil {
+		key = ari.NewKey(ari.BridgeKey, rid.New(rid.Bridge))
+	}
+
+	br, err := client.Bridge().Create(key, "holding", key.ID)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create holding bridge")
+	}
+
+	if err := br.MOH(o.mohClass); err != nil {
+		_ = br.Delete() // nolint
+
+		return nil, eris.Wrap(err, "failed to start music on hold")
+	}
+
+	q := &Queue{
+		client:   client,
+		o:        o,
+		bridge:   br,
+		agentSub: client.Bus().Subscribe(nil, ari.Events.DeviceStateChanged, ari.Events.EndpointStateChange),
+		closed:   make(chan struct{}),
+	}
+
+	go q.monitorAgents()
+
+	return q, nil
+}
+
+// Bridge returns the holding bridge of the queue
+func (q *Queue) Bridge() *ari.BridgeHandle {
+	return q.bridge
+}
+
+// Enqueue answers the given channel and places it in the queue, where it
+// waits until it is connected with an agent, it hangs up, it waits for the
+// maximum amount of time, or the context is cancelled.
+//
+// When the caller is connected, it is joined with the agent in a new mixing
+// bridge, which is described by the Result.  If the caller hangs up, the
+// agent is hung up; if the agent hangs up, the bridge is deleted and the
+// caller is left to the application.
+func (q *Queue) Enqueue(ctx context.Context, ch *ari.ChannelHandle) (*Result, error) {
+	res := new(Result)
+
+	select {
+	case <-q.closed:
+		res.Outcome = Failed
+		return res, ErrClosed
+	default:
+	}
+
+	sub := ch.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd)
+	defer sub.Cancel()
+
+	if err := ch.Answer(); err != nil {
+		res.Outcome = Failed
+		return res, eris.Wrap(err, "failed to answer caller")
+	}
+
+	if err := q.bridge.AddChannel(ch.ID()); err != nil {
+		res.Outcome = Failed
+		return res, eris.Wrap(err, "failed to add caller to holding bridge")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := &caller{
+		h:         ch,
+		ctx:       ctx,
+		entered:   time.Now(),
+		connected: make(chan connection, 1),
+	}
+
+	q.mu.Lock()
+	q.callers = append(q.callers, c)
+	q.stats.Entered++
+	res.Position = len(q.callers)
+	q.dispatch()
+	q.mu.Unlock()
+
+	var maxWait <-chan time.Time
+
+	if q.o.maxWait > 0 {
+		t := time.NewTimer(q.o.maxWait)
+		defer t.Stop()
+
+		maxWait = t.C
+	}
+
+	var announce <-chan time.Time
+
+	if q.o.announceInterval > 0 {
+		t := time.NewTicker(q.o.announceInterval)
+		defer t.Stop()
+
+		announce = t.C
+	}
+
+	hangup := sub.Events()
+	done := ctx.Done()
+	closed := q.closed
+
+	var announcing chan struct{}
+
+	for {
+		select {
+		case conn := <-c.connected:
+			res.Wait = time.Since(c.entered)
+
+			if conn.err != nil {
+				res.Outcome = Failed
+				return res, conn.err
+			}
+
+			res.Outcome = Connected
+			res.Agent = conn.agent
+			res.AgentChannel = conn.channel
+			res.Bridge = conn.bridge
+
+			return res, nil
+		case <-hangup:
+			// Stop watching, in case the caller was connected in the meantime
+			hangup = nil
+
+			if q.leave(c, Abandoned) {
+				res.Outcome = Abandoned
+				res.Wait = time.Since(c.entered)
+
+				return res, nil
+			}
+		case <-done:
+			// Stop watching, in case the caller was connected in the meantime
+			done = nil
+
+			if q.leave(c, Cancelled) {
+				q.release(ch)
+
+				res.Outcome = Cancelled
+				res.Wait = time.Since(c.entered)
+
+				return res, ctx.Err()
+			}
+		case <-closed:
+			// Stop watching, in case the caller was connected in the meantime
+			closed = nil
+
+			if q.leave(c, Cancelled) {
+				res.Outcome = Cancelled
+				res.Wait = time.Since(c.entered)
+
+				return res, ErrClosed
+			}
+		case <-maxWait:
+			if q.o.overflow == "" {
+				if q.leave(c, TimedOut) {
+					q.release(ch)
+
+					res.Outcome = TimedOut
+					res.Wait = time.Since(c.entered)
+
+					return res, nil
+				}
+
+				continue
+			}
+
+			if q.leave(c, Overflowed) {
+				res.Wait = time.Since(c.entered)
+				return res, q.overflow(ctx, ch, res)
+			}
+		case <-announce:
+			if announcing != nil {
+				select {
+				case <-announcing:
+				default:
+					// The previous announcement is still playing
+					continue
+				}
+			}
+
+			announcing = make(chan struct{})
+
+			go func(done chan struct{}) {
+				q.announce(ctx, c)
+				close(done)
+			}(announcing)
+		}
+	}
+}
+
+// leave removes the caller from the queue, recording the given outcome.  It
+// returns false if the caller has already been connected with an agent, in
+// which case the connection is (or will be) available from the caller.
+func (q *Queue) leave(c *caller, outcome Outcome) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if c.gone {
+		return false
+	}
+
+	q.remove(c)
+
+	switch outcome {
+	case Abandoned:
+		q.stats.Abandoned++
+	case Overflowed:
+		q.stats.Overflowed++
+	case TimedOut:
+		q.stats.TimedOut++
+	}
+
+	return true
+}
+
+// remove removes the caller from the list of waiting callers and cancels any
+// offer of it to the agents.  It must be called with the lock held.
+func (q *Queue) remove(c *caller) {
+	c.gone = true
+
+	if c.cancelOffer != nil {
+		c.cancelOffer()
+	}
+
+	for i, w := range q.callers {
+		if w == c {
+			q.callers = append(q.callers[:i], q.callers[i+1:]...)
+			break
+		}
+	}
+}
+
+// release removes the channel from the holding bridge.  The channel may
+// already be gone, so errors are ignored.
+func (q *Queue) release(ch *ari.ChannelHandle) {
+	_ = q.bridge.RemoveChannel(ch.ID()) // nolint
+}
+
+// dispatch offers waiting callers, in order, to available agents.  It must
+// be called with the lock held.
+func (q *Queue) dispatch() {
+	for _, c := range q.callers {
+		if c.offering {
+			continue
+		}
+
+		agents := q.pick()
+		if len(agents) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(c.ctx)
+
+		c.offering = true
+		c.cancelOffer = cancel
+
+		go q.offer(ctx, c, agents)
+	}
+}
+
+// offer rings the given agents for the caller, and connects the caller with
+// the first to answer
+func (q *Queue) offer(ctx context.Context, c *caller, agents []*agent) {
+	legs := make([]hunt.Leg, len(agents))
+	for i, a := range agents {
+		legs[i] = hunt.Leg{Target: a.endpoint, Timeout: q.o.ringTimeout}
+	}
+
+	res, err := hunt.Hunt(ctx, q.client, legs,
+		hunt.Inbound(c.h),
+		hunt.NoBridge(),
+		hunt.NoRingback(),
+		hunt.DialOptions(q.o.dialOptions...),
+	)
+
+	q.mu.Lock()
+
+	c.offering = false
+	c.cancelOffer()
+	c.cancelOffer = nil
+
+	for _, a := range agents {
+		if a.state == AgentRinging {
+			a.state = AgentAvailable
+		}
+	}
+
+	if res == nil || res.Winner == nil {
+		if errors.Is(err, hunt.ErrNoAnswer) {
+			// Pass over the agents which did not answer for a while
+			retryAt := time.Now().Add(q.o.retryDelay)
+			for _, a := range agents {
+				a.retryAt = retryAt
+			}
+
+			time.AfterFunc(q.o.retryDelay, q.redispatch)
+		}
+
+		q.dispatch()
+		q.mu.Unlock()
+
+		return
+	}
+
+	winner := agents[res.WinningLeg]
+
+	if c.gone {
+		// The caller left while the agent was answering
+		q.dispatch()
+		q.mu.Unlock()
+
+		_ = q.client.Channel().Hangup(res.Winner.Key(), string(ari.HangupNormal)) // nolint
+
+		return
+	}
+
+	// Take the caller off the queue and reserve the agent while they are
+	// connected, which is done without the lock
+	q.remove(c)
+
+	winner.state = AgentOnCall
+
+	q.dispatch()
+	q.mu.Unlock()
+
+	br, err := q.connect(c.h, res.Winner)
+	if err != nil {
+		_ = q.client.Channel().Hangup(res.Winner.Key(), string(ari.HangupNormal)) // nolint
+
+		q.mu.Lock()
+
+		if winner.state == AgentOnCall {
+			winner.state = AgentAvailable
+		}
+
+		q.dispatch()
+		q.mu.Unlock()
+
+		c.connected <- connection{err: err}
+
+		return
+	}
+
+	q.mu.Lock()
+	winner.calls++
+	q.stats.Connected++
+	q.totalWait += time.Since(c.entered)
+	q.mu.Unlock()
+
+	c.connected <- connection{
+		agent:   winner.endpoint,
+		channel: res.Winner,
+		bridge:  br,
+	}
+
+	go q.watchCall(winner, c.h, res.Winner, br)
+}
+
+// redispatch offers waiting callers to available agents
+func (q *Queue) redispatch() {
+	q.mu.Lock()
+	q.dispatch()
+	q.mu.Unlock()
+}
+
+// connect moves the caller from the holding bridge to a new mixing bridge,
+// where it is joined with the given channel
+func (q *Queue) connect(ch, peer *ari.ChannelHandle) (*ari.BridgeHandle, error) {
+	if err := q.bridge.RemoveChannel(ch.ID()); err != nil {
+		return nil, eris.Wrap(err, "failed to remove caller from holding bridge")
+	}
+
+	key := ch.Key().New(ari.BridgeKey, rid.New(rid.Bridge))
+
+	br, err := q.client.Bridge().Create(key, "mixing", key.ID)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create bridge")
+	}
+
+	for _, id := range []string{ch.ID(), peer.ID()} {
+		if err := br.AddChannel(id); err != nil {
+			_ = br.Delete() // nolint
+
+			return nil, eris.Wrapf(err, "failed to add channel %s to bridge", id)
+		}
+	}
+
+	return br, nil
+}
+
+// watchCall waits for either party of a queue call to hang up, then ends
+// the call and begins the agent's wrap-up
+func (q *Queue) watchCall(a *agent, ch, peer *ari.ChannelHandle, br *ari.BridgeHandle) {
+	started := time.Now()
+
+	callerSub := ch.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd)
+	defer callerSub.Cancel()
+
+	agentSub := peer.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd)
+	defer agentSub.Cancel()
+
+	select {
+	case <-callerSub.Events():
+	case <-agentSub.Events():
+	}
+
+	// Either party may already be gone, so errors are ignored
+	_ = br.Delete()                                                     // nolint
+	_ = q.client.Channel().Hangup(peer.Key(), string(ari.HangupNormal)) // nolint
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	a.lastCall = time.Now()
+	q.talks++
+	q.totalTalk += a.lastCall.Sub(started)
+
+	if q.o.wrapUp <= 0 {
+		a.state = AgentAvailable
+		q.dispatch()
+
+		return
+	}
+
+	a.state = AgentWrapUp
+
+	time.AfterFunc(q.o.wrapUp, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		if a.state == AgentWrapUp {
+			a.state = AgentAvailable
+		}
+
+		q.dispatch()
+	})
+}
+
+// overflow sends the caller, which has left the queue, to the overflow destination
+func (q *Queue) overflow(ctx context.Context, ch *ari.ChannelHandle, res *Result) error {
+	q.release(ch)
+
+	h, err := hunt.Hunt(ctx, q.client, []hunt.Leg{{Target: q.o.overflow}},
+		hunt.Inbound(ch),
+		hunt.NoBridge(),
+		hunt.DialOptions(q.o.dialOptions...),
+	)
+	if err != nil {
+		res.Outcome = TimedOut
+		return eris.Wrap(err, "failed to dial overflow destination")
+	}
+
+	res.AgentChannel = h.Winner
+
+	res.Bridge, err = q.connect(ch, h.Winner)
+	if err != nil {
+		_ = q.client.Channel().Hangup(h.Winner.Key(), string(ari.HangupNormal)) // nolint
+
+		res.Outcome = TimedOut
+
+		return err
+	}
+
+	res.Outcome = Overflowed
+
+	return nil
+}
+
+// announce plays the caller's position in the queue, and optionally the
+// estimated wait, to the caller
+func (q *Queue) announce(ctx context.Context, c *caller) {
+	q.mu.Lock()
+
+	if c.gone {
+		q.mu.Unlock()
+		return
+	}
+
+	var pos int
+
+	for i, w := range q.callers {
+		if w == c {
+			pos = i + 1
+			break
+		}
+	}
+
+	wait := q.averageWait()
+
+	q.mu.Unlock()
+
+	_, _ = play.Play(ctx, c.h, play.URI(announcement(pos, wait, q.o.announceHoldTime)...)).Result() // nolint
+}
+
+// announcement returns the media URIs which announce the given position in
+// the queue and, if requested and known, the estimated wait
+func announcement(pos int, wait time.Duration, holdTime bool) []string {
+	var ret []string
+
+	if pos == 1 {
+		ret = append(ret, "sound:queue-youarenext")
+	} else {
+		ret = append(ret, "sound:queue-thereare", audiouri.NumberURI(pos), "sound:queue-callswaiting")
+	}
+
+	if holdTime && wait > 0 {
+		// Hold times are announced in whole minutes
+		if wait < time.Minute {
+			wait = time.Minute
+		}
+
+		ret = append(ret, "sound:queue-holdtime")
+		ret = append(ret, audiouri.DurationURI(wait.Round(time.Minute))...)
+	}
+
+	return ret
+}
+
+// averageWait returns the average amount of time for which connected
+// callers have waited.  It must be called with the lock held.
+func (q *Queue) averageWait() time.Duration {
+	if q.stats.Connected == 0 {
+		return 0
+	}
+
+	return q.totalWait / time.Duration(q.stats.Connected)
+}
+
+// EstimatedWait returns the estimated amount of time for which a new caller
+// will wait, based upon the callers which have been connected.  It is zero
+// if no caller has yet been connected.
+func (q *Queue) EstimatedWait() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.averageWait()
+}
+
+// Stats returns the statistics of the queue
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+
+	ret := q.stats
+	ret.Waiting = len(q.callers)
+	ret.AverageWait = q.averageWait()
+
+	if len(q.callers) > 0 {
+		ret.LongestWait = now.Sub(q.callers[0].entered)
+	}
+
+	if q.talks > 0 {
+		ret.AverageTalk = q.totalTalk / time.Duration(q.talks)
+	}
+
+	for _, a := range q.agents {
+		if a.available(now) {
+			ret.AgentsAvailable++
+		}
+	}
+
+	return ret
+}
+
+// Close closes the queue, deleting its holding bridge.  Waiting callers
+// leave the queue with the Cancelled outcome; calls in progress are not affected.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+
+	select {
+	case <-q.closed:
+		q.mu.Unlock()
+		return nil
+	default:
+	}
+
+	close(q.closed)
+
+	q.mu.Unlock()
+
+	q.agentSub.Cancel()
+
+	if err := q.bridge.Delete(); err != nil {
+		return eris.Wrap(err, "failed to delete holding bridge")
+	}
+
+	return nil
+}
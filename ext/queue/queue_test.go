@@ -0,0 +1,424 @@
+package queue
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+type queueTest struct {
+	client   *arimocks.Client
+	channel  *arimocks.Channel
+	bridge   *arimocks.Bridge
+	endpoint *arimocks.Endpoint
+	app      *arimocks.Application
+	bus      *arimocks.Bus
+
+	busEvents chan ari.Event
+
+	mu sync.Mutex
+
+	// legs maps the endpoint of each dialed leg to its event channel
+	legs map[string]chan ari.Event
+
+	// hangups maps each channel ID to the event channels of its hangup subscriptions
+	hangups map[string][]chan ari.Event
+
+	// dialed is signalled with the endpoint of each leg as it is dialed
+	dialed chan string
+}
+
+func legKey(endpoint string) *ari.Key {
+	return ari.NewKey(ari.ChannelKey, "leg-"+strings.TrimPrefix(endpoint, "PJSIP/"))
+}
+
+func subscription(events chan ari.Event) *arimocks.Subscription {
+	sub := &arimocks.Subscription{}
+	sub.On("Events").Return((<-chan ari.Event)(events))
+	sub.On("Cancel").Return()
+
+	return sub
+}
+
+func newQueueTest() *queueTest {
+	qt := &queueTest{
+		client:    &arimocks.Client{},
+		channel:   &arimocks.Channel{},
+		bridge:    &arimocks.Bridge{},
+		endpoint:  &arimocks.Endpoint{},
+		app:       &arimocks.Application{},
+		bus:       &arimocks.Bus{},
+		busEvents: make(chan ari.Event, 10),
+		legs:      make(map[string]chan ari.Event),
+		hangups:   make(map[string][]chan ari.Event),
+		dialed:    make(chan string, 10),
+	}
+
+	qt.client.On("ApplicationName").Return("myapp")
+	qt.client.On("Channel").Return(qt.channel)
+	qt.client.On("Bridge").Return(qt.bridge)
+	qt.client.On("Endpoint").Return(qt.endpoint)
+	qt.client.On("Application").Return(qt.app)
+	qt.client.On("Bus").Return(qt.bus)
+
+	qt.bus.On("Subscribe", mock.Anything, ari.Events.DeviceStateChanged, ari.Events.EndpointStateChange).
+		Return(subscription(qt.busEvents))
+	qt.endpoint.On("Data", mock.Anything).Return(&ari.EndpointData{State: "online"}, nil)
+	qt.app.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+	qt.app.On("Unsubscribe", mock.Anything, mock.Anything).Return(nil)
+
+	qt.channel.On("StageOriginate", mock.Anything, mock.Anything).Return(
+		func(_ *ari.Key, req ari.OriginateRequest) (*ari.ChannelHandle, error) {
+			key := legKey(req.Endpoint)
+			events := make(chan ari.Event, 10)
+
+			qt.mu.Lock()
+			qt.legs[req.Endpoint] = events
+			qt.mu.Unlock()
+
+			qt.channel.On("Subscribe", key,
+				ari.Events.Dial, ari.Events.ChannelStateChange, ari.Events.StasisStart, ari.Events.ChannelDestroyed,
+			).Return(subscription(events))
+
+			return ari.NewChannelHandle(key, qt.channel, func(_ *ari.ChannelHandle) error {
+				qt.dialed <- req.Endpoint
+				return nil
+			}), nil
+		})
+	qt.channel.On("Subscribe", mock.Anything, ari.Events.ChannelHangupRequest, ari.Events.StasisEnd).Return(
+		func(key *ari.Key, _ ...string) ari.Subscription {
+			events := make(chan ari.Event, 2)
+
+			qt.mu.Lock()
+			qt.hangups[key.ID] = append(qt.hangups[key.ID], events)
+			qt.mu.Unlock()
+
+			return subscription(events)
+		})
+	qt.channel.On("Answer", mock.Anything).Return(nil)
+	qt.channel.On("Hangup", mock.Anything, mock.Anything).Return(nil)
+
+	for _, t := range []string{"holding", "mixing"} {
+		qt.bridge.On("Create", mock.Anything, t, mock.Anything).Return(
+			func(key *ari.Key, _, _ string) (*ari.BridgeHandle, error) {
+				return ari.NewBridgeHandle(key, qt.bridge, nil), nil
+			})
+	}
+
+	qt.bridge.On("MOH", mock.Anything, mock.Anything).Return(nil)
+	qt.bridge.On("AddChannel", mock.Anything, mock.Anything).Return(nil)
+	qt.bridge.On("RemoveChannel", mock.Anything, mock.Anything).Return(nil)
+	qt.bridge.On("Delete", mock.Anything).Return(nil)
+
+	return qt
+}
+
+func (qt *queueTest) newQueue(t *testing.T, opts ...OptionFunc) *Queue {
+	t.Helper()
+
+	q, err := New(qt.client, ari.NewKey(ari.BridgeKey, "queue"), opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return q
+}
+
+func (qt *queueTest) handle(id string) *ari.ChannelHandle {
+	return ari.NewChannelHandle(ari.NewKey(ari.ChannelKey, id), qt.channel, nil)
+}
+
+// waitDialed waits for the given legs to be dialed, in any order
+func (qt *queueTest) waitDialed(t *testing.T, endpoints ...string) {
+	t.Helper()
+
+	want := make(map[string]bool)
+	for _, e := range endpoints {
+		want[e] = true
+	}
+
+	for len(want) > 0 {
+		select {
+		case e := <-qt.dialed:
+			if !want[e] {
+				t.Fatalf("unexpected dial of %s", e)
+			}
+
+			delete(want, e)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %v to be dialed", want)
+		}
+	}
+}
+
+// answer answers the leg of the given endpoint
+func (qt *queueTest) answer(endpoint string) {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+
+	qt.legs[endpoint] <- &ari.StasisStart{Channel: ari.ChannelData{ID: legKey(endpoint).ID, State: "Up"}}
+}
+
+// hangup signals the hangup of the given channel to its subscribers
+func (qt *queueTest) hangup(id string) {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+
+	for _, events := range qt.hangups[id] {
+		events <- &ari.ChannelHangupRequest{Channel: ari.ChannelData{ID: id}}
+	}
+}
+
+// waitSubscribed waits for the given channel to have the given number of hangup subscriptions
+func (qt *queueTest) waitSubscribed(t *testing.T, id string, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		qt.mu.Lock()
+		count := len(qt.hangups[id])
+		qt.mu.Unlock()
+
+		if count >= n {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d hangup subscriptions of %s", n, id)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitAgent waits for the agent with the given endpoint to reach the given state
+func waitAgent(t *testing.T, q *Queue, endpoint string, state AgentState) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		for _, a := range q.Agents() {
+			if a.Endpoint == endpoint && a.State == state {
+				return
+			}
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s to be %s; agents are %+v", endpoint, state, q.Agents())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type enqueueResult struct {
+	res *Result
+	err error
+}
+
+func enqueue(q *Queue, ch *ari.ChannelHandle) chan enqueueResult {
+	ret := make(chan enqueueResult, 1)
+
+	go func() {
+		res, err := q.Enqueue(context.Background(), ch)
+		ret <- enqueueResult{res, err}
+	}()
+
+	return ret
+}
+
+func waitResult(t *testing.T, ch chan enqueueResult) *Result {
+	t.Helper()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("unexpected error: %v", r.err)
+		}
+
+		return r.res
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for caller to leave queue")
+	}
+
+	return nil
+}
+
+func TestRingAll(t *testing.T) {
+	qt := newQueueTest()
+	q := qt.newQueue(t, WrapUp(time.Hour))
+
+	for _, a := range []string{"PJSIP/alice", "PJSIP/bob"} {
+		if err := q.AddAgent(a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	done := enqueue(q, qt.handle("caller1"))
+
+	qt.waitDialed(t, "PJSIP/alice", "PJSIP/bob")
+	qt.answer("PJSIP/bob")
+
+	res := waitResult(t, done)
+	if res.Outcome != Connected || res.Agent != "PJSIP/bob" || res.Position != 1 || res.Bridge == nil {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	qt.bridge.AssertCalled(t, "RemoveChannel", ari.NewKey(ari.BridgeKey, "queue"), "caller1")
+	qt.bridge.AssertCalled(t, "AddChannel", res.Bridge.Key(), "caller1")
+	qt.bridge.AssertCalled(t, "AddChannel", res.Bridge.Key(), "leg-bob")
+
+	waitAgent(t, q, "PJSIP/bob", AgentOnCall)
+	waitAgent(t, q, "PJSIP/alice", AgentAvailable)
+
+	// The caller hangs up, so the agent is hung up and begins its wrap-up.
+	// The call is watched by the third subscription, after those of the
+	// queue and of the hunt.
+	qt.waitSubscribed(t, "caller1", 3)
+	qt.hangup("caller1")
+
+	waitAgent(t, q, "PJSIP/bob", AgentWrapUp)
+
+	qt.channel.AssertCalled(t, "Hangup", legKey("PJSIP/bob"), string(ari.HangupNormal))
+
+	if s := q.Stats(); s.Entered != 1 || s.Connected != 1 || s.Waiting != 0 || s.AgentsAvailable != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestAbandon(t *testing.T) {
+	qt := newQueueTest()
+	q := qt.newQueue(t)
+
+	done := enqueue(q, qt.handle("caller1"))
+
+	qt.waitSubscribed(t, "caller1", 1)
+	qt.hangup("caller1")
+
+	if res := waitResult(t, done); res.Outcome != Abandoned {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	if s := q.Stats(); s.Abandoned != 1 || s.Waiting != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestMaxWait(t *testing.T) {
+	qt := newQueueTest()
+	q := qt.newQueue(t, MaxWait(10*time.Millisecond))
+
+	res := waitResult(t, enqueue(q, qt.handle("caller1")))
+	if res.Outcome != TimedOut || res.Wait < 10*time.Millisecond {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	qt.bridge.AssertCalled(t, "RemoveChannel", ari.NewKey(ari.BridgeKey, "queue"), "caller1")
+
+	if s := q.Stats(); s.TimedOut != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestAgentState(t *testing.T) {
+	qt := newQueueTest()
+	q := qt.newQueue(t)
+
+	if err := q.AddAgent("PJSIP/alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	qt.busEvents <- &ari.DeviceStateChanged{DeviceState: ari.DeviceStateData{Name: "PJSIP/alice", State: "INUSE"}}
+
+	waitAgent(t, q, "PJSIP/alice", AgentBusy)
+
+	qt.busEvents <- &ari.EndpointStateChange{Endpoint: ari.EndpointData{Technology: "PJSIP", Resource: "alice", State: "offline"}}
+	qt.busEvents <- &ari.DeviceStateChanged{DeviceState: ari.DeviceStateData{Name: "PJSIP/alice", State: "NOT_INUSE"}}
+
+	waitAgent(t, q, "PJSIP/alice", AgentOffline)
+
+	qt.busEvents <- &ari.EndpointStateChange{Endpoint: ari.EndpointData{Technology: "PJSIP", Resource: "alice", State: "online"}}
+
+	waitAgent(t, q, "PJSIP/alice", AgentAvailable)
+
+	q.RemoveAgent("PJSIP/alice")
+
+	if n := len(q.Agents()); n != 0 {
+		t.Errorf("expected no agents, got %d", n)
+	}
+
+	qt.app.AssertCalled(t, "Unsubscribe", ari.NewKey(ari.ApplicationKey, "myapp"), "endpoint:PJSIP/alice")
+}
+
+func TestPick(t *testing.T) {
+	now := time.Now()
+
+	newAgents := func() []*agent {
+		return []*agent{
+			{endpoint: "a", calls: 3, lastCall: now.Add(-time.Minute)},
+			{endpoint: "b", calls: 1, lastCall: now},
+			{endpoint: "c", calls: 2, lastCall: now.Add(-time.Hour)},
+			{endpoint: "d", calls: 0, deviceState: "INUSE"},
+		}
+	}
+
+	tests := []struct {
+		strategy Strategy
+		picks    [][]string
+	}{
+		{RingAll, [][]string{{"a", "b", "c"}, nil}},
+		{RoundRobin, [][]string{{"a"}, {"b"}, {"c"}, nil}},
+		{LeastRecent, [][]string{{"c"}, {"a"}, {"b"}, nil}},
+		{FewestCalls, [][]string{{"b"}, {"c"}, {"a"}, nil}},
+	}
+
+	for _, tc := range tests {
+		q := &Queue{
+			o:      &Options{strategy: tc.strategy},
+			agents: newAgents(),
+		}
+
+		for i, want := range tc.picks {
+			var got []string
+			for _, a := range q.pick() {
+				got = append(got, a.endpoint)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s pick %d: expected %v, got %v", tc.strategy, i, want, got)
+			}
+		}
+	}
+}
+
+func TestAnnouncement(t *testing.T) {
+	tests := []struct {
+		pos      int
+		wait     time.Duration
+		holdTime bool
+		uris     []string
+	}{
+		{1, 0, true, []string{"sound:queue-youarenext"}},
+		{3, time.Minute, false, []string{"sound:queue-thereare", "number:3", "sound:queue-callswaiting"}},
+		{2, 20 * time.Second, true, []string{
+			"sound:queue-thereare", "number:2", "sound:queue-callswaiting",
+			"sound:queue-holdtime", "number:1", "sound:time/minute",
+		}},
+	}
+
+	for _, tc := range tests {
+		if got := announcement(tc.pos, tc.wait, tc.holdTime); !reflect.DeepEqual(got, tc.uris) {
+			t.Errorf("announcement(%d, %s, %v): expected %v, got %v", tc.pos, tc.wait, tc.holdTime, tc.uris, got)
+		}
+	}
+}
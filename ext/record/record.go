@@ -52,6 +52,9 @@ type Options struct {
 	beep bool
 
 	terminateOn string
+
+	// review describes the review of the recording by RecordWithReview
+	review reviewOptions
 }
 
 func defaultOptions() *Options {
@@ -64,6 +67,7 @@ func defaultOptions() *Options {
 		name:        rid.New(rid.Recording),
 		logger:      slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError})),
 		terminateOn: "none",
+		review:      defaultReviewOptions(),
 	}
 }
 
@@ -231,20 +235,28 @@ func (r *Result) Key() *ari.Key {
 
 // Save stores the recording to the given name
 func (r *Result) Save(name string) error {
+	_, err := r.save(name)
+
+	return err
+}
+
+// save stores the recording to the given name, returning the handle of the
+// stored recording
+func (r *Result) save(name string) (*ari.StoredRecordingHandle, error) {
 	if name == "" {
 		// no name indicates the default, which is where it already is
-		return nil
+		return r.h, nil
 	}
 
 	if r.h == nil {
-		return eris.New("no stored recording handle available")
+		return nil, eris.New("no stored recording handle available")
 	}
 
 	// Copy the recording to the desired name
 	destH, err := r.h.Copy(name)
 	if err != nil {
 		if !strings.Contains(err.Error(), "409 Conflict") || !r.overwrite {
-			return eris.Wrapf(err, "failed to copy recording (%s)", r.h.ID())
+			return nil, eris.Wrapf(err, "failed to copy recording (%s)", r.h.ID())
 		}
 
 		// we are set to overwrite, so delete the previous recording
@@ -252,22 +264,22 @@ func (r *Result) Save(name string) error {
 
 		err = destH.Delete()
 		if err != nil {
-			return eris.Wrap(err, "failed to remove previous destination recording")
+			return nil, eris.Wrap(err, "failed to remove previous destination recording")
 		}
 
-		_, err = r.h.Copy(name)
+		destH, err = r.h.Copy(name)
 		if err != nil {
-			return eris.Wrap(err, "failed to copy recording")
+			return nil, eris.Wrap(err, "failed to copy recording")
 		}
 	}
 
 	// Delete the original
 	err = r.h.Delete()
 	if err != nil {
-		return eris.Wrap(err, "failed to remove temporary recording after copy")
+		return nil, eris.Wrap(err, "failed to remove temporary recording after copy")
 	}
 
-	return nil
+	return destH, nil
 }
 
 // URI returns the AudioURI to play the recording
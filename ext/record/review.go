@@ -0,0 +1,237 @@
+package record
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+	"github.com/CyCoreSystems/ari/v6/rid"
+)
+
+var (
+	// DefaultMaxAttempts is the default number of recordings which may be
+	// made by RecordWithReview before it gives up
+	DefaultMaxAttempts = 3
+
+	// DefaultReviewReplays is the default number of times the review prompt
+	// is replayed when the caller does not respond to it
+	DefaultReviewReplays = 2
+)
+
+// ErrNotAccepted indicates that the caller did not accept any recording
+var ErrNotAccepted = errors.New("no recording was accepted")
+
+// ErrHangup indicates that the channel hung up during the recording or its review
+var ErrHangup = errors.New("channel hung up")
+
+// reviewOptions describes the review of a recording
+type reviewOptions struct {
+	// prompt is the set of audio URIs which offer the choices.  If empty, it
+	// is composed from the keys.
+	prompt []string
+
+	accept   string
+	rerecord string
+	listen   string
+
+	maxAttempts int
+
+	replays int
+
+	// attempt makes the recording of an attempt
+	attempt func(ctx context.Context, r ari.Recorder, opts ...OptionFunc) (*Result, error)
+
+	// choose plays the review prompt and collects the caller's choice
+	choose func(ctx context.Context, p ari.Player, opts ...play.OptionFunc) (*play.Result, error)
+}
+
+func defaultReviewOptions() reviewOptions {
+	return reviewOptions{
+		accept:      "1",
+		rerecord:    "2",
+		listen:      "3",
+		maxAttempts: DefaultMaxAttempts,
+		replays:     DefaultReviewReplays,
+		attempt:     recordAttempt,
+		choose:      collectChoice,
+	}
+}
+
+// promptURIs returns the audio URIs of the review prompt
+func (r *reviewOptions) promptURIs() []string {
+	if len(r.prompt) > 0 {
+		return r.prompt
+	}
+
+	return []string{
+		"sound:press", "digits:" + r.accept, "sound:to-accept-recording",
+		"sound:press", "digits:" + r.rerecord, "sound:to-rerecord-it",
+		"sound:press", "digits:" + r.listen, "sound:to-listen-to-it",
+	}
+}
+
+// ReviewPrompt sets the audio URIs which offer the caller the choice to
+// accept, re-record, or listen again to a recording (see RecordWithReview).
+// By default, the prompt is composed from the review keys.
+func ReviewPrompt(uris ...string) OptionFunc {
+	return func(o *Options) {
+		o.review.prompt = uris
+	}
+}
+
+// ReviewKeys sets the DTMF digits with which the caller accepts,
+// re-records, or listens again to a recording (see RecordWithReview).  The
+// defaults are "1", "2", and "3".
+func ReviewKeys(accept, rerecord, listen string) OptionFunc {
+	return func(o *Options) {
+		o.review.accept = accept
+		o.review.rerecord = rerecord
+		o.review.listen = listen
+	}
+}
+
+// MaxAttempts sets the number of recordings which may be made by
+// RecordWithReview before it gives up
+func MaxAttempts(n int) OptionFunc {
+	return func(o *Options) {
+		o.review.maxAttempts = n
+	}
+}
+
+// ReviewReplays sets the number of times the review prompt is replayed when
+// the caller does not respond to it
+func ReviewReplays(n int) OptionFunc {
+	return func(o *Options) {
+		o.review.replays = n
+	}
+}
+
+// recordAttempt makes the recording of an attempt
+func recordAttempt(ctx context.Context, r ari.Recorder, opts ...OptionFunc) (*Result, error) {
+	return Record(ctx, r, opts...).Result()
+}
+
+// collectChoice plays the review prompt and collects the caller's choice
+func collectChoice(ctx context.Context, p ari.Player, opts ...play.OptionFunc) (*play.Result, error) {
+	return play.Prompt(ctx, p, opts...).Result()
+}
+
+// RecordWithReview records the channel and lets the caller review the
+// recording:  it is played back, and the caller may accept it, re-record
+// it, or listen to it again.  The recording options (ex. Beep, MaxSilence,
+// TerminateOn) apply to each attempt.
+//
+// Each attempt is recorded under a temporary name, and is deleted unless it
+// is accepted, even if the channel hangs up or the context is cancelled.
+// The accepted recording is saved under the name given by Name (replacing
+// any existing recording only if IfExists is "overwrite") and returned.
+// If no recording is accepted within the maximum number of attempts (see
+// MaxAttempts), ErrNotAccepted is returned.
+func RecordWithReview(ctx context.Context, h *ari.ChannelHandle, opts ...OptionFunc) (*Result, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	// Each attempt adds its own temporary name to a copy of the options,
+	// leaving the caller's slice untouched
+	attemptOpts := make([]OptionFunc, len(opts), len(opts)+1)
+	copy(attemptOpts, opts)
+
+	for attempt := 1; ; attempt++ {
+		res, err := o.review.attempt(ctx, h, append(attemptOpts, Name(rid.New(rid.Recording)))...)
+
+		switch {
+		case res != nil && res.Hangup:
+			discard(res)
+			return nil, ErrHangup
+		case ctx.Err() != nil:
+			discard(res)
+			return nil, ctx.Err()
+		case err != nil:
+			discard(res)
+			return nil, eris.Wrap(err, "failed to record")
+		}
+
+		accepted, err := review(ctx, h, res, &o.review)
+		if err != nil {
+			discard(res)
+			return nil, err
+		}
+
+		if accepted {
+			saved, err := res.save(o.name)
+			if err != nil {
+				discard(res)
+				return nil, eris.Wrap(err, "failed to save recording")
+			}
+
+			// The result describes the saved recording, not the temporary one
+			res.h = saved
+
+			return res, nil
+		}
+
+		discard(res)
+
+		if attempt >= o.review.maxAttempts {
+			return nil, ErrNotAccepted
+		}
+	}
+}
+
+// review plays the recording back and prompts the caller to accept it,
+// returning false if the caller chose to re-record it
+func review(ctx context.Context, p ari.Player, res *Result, r *reviewOptions) (bool, error) {
+	listen := true
+
+	for tries := 0; tries <= r.replays; {
+		uris := r.promptURIs()
+		if listen {
+			uris = append([]string{res.URI()}, uris...)
+		}
+
+		pr, err := r.choose(ctx, p,
+			play.URI(uris...),
+			play.MatchDiscrete([]string{r.accept, r.rerecord, r.listen}),
+		)
+
+		switch {
+		case ctx.Err() != nil:
+			return false, ctx.Err()
+		case pr != nil && pr.Status == play.Hangup:
+			return false, ErrHangup
+		case err != nil:
+			return false, eris.Wrap(err, "failed to play review prompt")
+		}
+
+		listen = false
+
+		if pr.MatchResult == play.Complete {
+			switch pr.DTMF {
+			case r.accept:
+				return true, nil
+			case r.rerecord:
+				return false, nil
+			case r.listen:
+				// Listening again does not count against the replays
+				listen = true
+				continue
+			}
+		}
+
+		tries++
+	}
+
+	return false, eris.Wrap(ErrNotAccepted, "no response to review")
+}
+
+// discard deletes the recording of an attempt, if there is one
+func discard(res *Result) {
+	if res.Key() == nil {
+		return
+	}
+
+	_ = res.Delete() // nolint  the recording may not exist
+}
@@ -0,0 +1,185 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+// reviewTest stands in for the recording and prompting of RecordWithReview
+type reviewTest struct {
+	recordings *arimocks.StoredRecording
+
+	// names holds the names of the recordings made, in order
+	names []string
+
+	// responses holds the results of the review prompts, in order
+	responses []*play.Result
+
+	prompts int
+
+	attempt func(ctx context.Context, r ari.Recorder, opts ...OptionFunc) (*Result, error)
+	choose  func(ctx context.Context, p ari.Player, opts ...play.OptionFunc) (*play.Result, error)
+}
+
+func newReviewTest(t *testing.T, responses ...*play.Result) *reviewTest {
+	rt := &reviewTest{
+		recordings: &arimocks.StoredRecording{},
+		responses:  responses,
+	}
+
+	rt.attempt = func(ctx context.Context, r ari.Recorder, opts ...OptionFunc) (*Result, error) {
+		o := defaultOptions()
+		o.Apply(opts...)
+
+		rt.names = append(rt.names, o.name)
+
+		key := ari.NewKey(ari.StoredRecordingKey, o.name)
+
+		return &Result{
+			h:      ari.NewStoredRecordingHandle(key, rt.recordings, nil),
+			logger: o.logger,
+		}, nil
+	}
+
+	rt.choose = func(ctx context.Context, p ari.Player, opts ...play.OptionFunc) (*play.Result, error) {
+		if rt.prompts >= len(rt.responses) {
+			t.Fatalf("unexpected prompt %d", rt.prompts+1)
+		}
+
+		rt.prompts++
+
+		return rt.responses[rt.prompts-1], nil
+	}
+
+	rt.recordings.On("Delete", mock.Anything).Return(nil)
+
+	return rt
+}
+
+// stubs is the option which replaces the recording and prompting of RecordWithReview
+func (rt *reviewTest) stubs(o *Options) {
+	o.review.attempt = rt.attempt
+	o.review.choose = rt.choose
+}
+
+func pressed(digit string) *play.Result {
+	return &play.Result{Status: play.Finished, DTMF: digit, MatchResult: play.Complete}
+}
+
+var noResponse = &play.Result{Status: play.Finished, MatchResult: play.Incomplete}
+
+func (rt *reviewTest) assertDeleted(t *testing.T, count int) {
+	t.Helper()
+
+	rt.recordings.AssertNumberOfCalls(t, "Delete", count)
+
+	for _, n := range rt.names[:count] {
+		rt.recordings.AssertCalled(t, "Delete", ari.NewKey(ari.StoredRecordingKey, n))
+	}
+}
+
+func TestRecordWithReviewAccept(t *testing.T) {
+	rt := newReviewTest(t, pressed("3"), pressed("1"))
+
+	saved := ari.NewStoredRecordingHandle(ari.NewKey(ari.StoredRecordingKey, "greeting"), rt.recordings, nil)
+	rt.recordings.On("Copy", mock.Anything, "greeting").Return(saved, nil)
+
+	res, err := RecordWithReview(context.Background(), nil, rt.stubs, Name("greeting"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rt.names) != 1 || rt.names[0] == "greeting" {
+		t.Errorf("expected one recording with a temporary name, got %v", rt.names)
+	}
+
+	if rt.prompts != 2 {
+		t.Errorf("expected 2 prompts, got %d", rt.prompts)
+	}
+
+	if res.Key().ID != "greeting" || res.URI() != "recording:greeting" {
+		t.Errorf("expected the result to describe the saved recording, got %s", res.URI())
+	}
+
+	rt.assertDeleted(t, 1)
+}
+
+func TestRecordWithReviewRerecord(t *testing.T) {
+	rt := newReviewTest(t, pressed("2"), noResponse, pressed("9"), pressed("1"))
+
+	rt.recordings.On("Copy", mock.Anything, "greeting").Return(&ari.StoredRecordingHandle{}, nil)
+
+	if _, err := RecordWithReview(context.Background(), nil, rt.stubs, Name("greeting")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rt.names) != 2 {
+		t.Fatalf("expected 2 recordings, got %d", len(rt.names))
+	}
+
+	rt.recordings.AssertCalled(t, "Copy", ari.NewKey(ari.StoredRecordingKey, rt.names[1]), "greeting")
+	rt.assertDeleted(t, 2)
+}
+
+func TestRecordWithReviewOptions(t *testing.T) {
+	rt := newReviewTest(t, pressed("1"))
+
+	rt.recordings.On("Copy", mock.Anything, "greeting").Return(&ari.StoredRecordingHandle{}, nil)
+
+	// spare capacity, which an append would write into
+	opts := make([]OptionFunc, 2, 3)
+	opts[0], opts[1] = rt.stubs, Name("greeting")
+
+	sentinel := Name("sentinel")
+	opts = append(opts, sentinel)[:2]
+
+	if _, err := RecordWithReview(context.Background(), nil, opts...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := defaultOptions()
+	o.Apply(opts[:3]...)
+
+	if o.name != "sentinel" {
+		t.Errorf("expected the caller's options to be untouched, got name %q", o.name)
+	}
+}
+
+func TestRecordWithReviewNotAccepted(t *testing.T) {
+	rt := newReviewTest(t, pressed("2"), pressed("2"))
+
+	_, err := RecordWithReview(context.Background(), nil, rt.stubs, MaxAttempts(2))
+	if !errors.Is(err, ErrNotAccepted) {
+		t.Errorf("expected ErrNotAccepted, got %v", err)
+	}
+
+	rt.assertDeleted(t, 2)
+
+	rt = newReviewTest(t, noResponse, noResponse)
+
+	_, err = RecordWithReview(context.Background(), nil, rt.stubs, ReviewReplays(1))
+	if !errors.Is(err, ErrNotAccepted) {
+		t.Errorf("expected ErrNotAccepted with no response, got %v", err)
+	}
+
+	rt.assertDeleted(t, 1)
+}
+
+func TestRecordWithReviewHangup(t *testing.T) {
+	rt := newReviewTest(t, pressed("3"), &play.Result{Status: play.Hangup})
+
+	_, err := RecordWithReview(context.Background(), nil, rt.stubs)
+	if !errors.Is(err, ErrHangup) {
+		t.Errorf("expected ErrHangup, got %v", err)
+	}
+
+	rt.assertDeleted(t, 1)
+	rt.recordings.AssertNumberOfCalls(t, "Copy", 0)
+}
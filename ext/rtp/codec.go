@@ -0,0 +1,203 @@
+package rtp
+
+import "encoding/binary"
+
+// Codec converts between RTP payloads and 16-bit signed linear PCM samples
+type Codec interface {
+	// Format returns the name by which Asterisk knows the format (ex. "ulaw")
+	Format() string
+
+	// PayloadType returns the RTP payload type which Asterisk uses for the format
+	PayloadType() uint8
+
+	// SampleRate returns the number of samples per second
+	SampleRate() int
+
+	// Encode encodes the given samples as an RTP payload
+	Encode(samples []int16) []byte
+
+	// Decode decodes the given RTP payload to samples
+	Decode(payload []byte) []int16
+}
+
+var (
+	// ULaw is the G.711 μ-law codec, at 8kHz
+	ULaw Codec = g711{format: "ulaw", payloadType: 0, encode: ulawEncode, decode: &ulawTable}
+
+	// ALaw is the G.711 A-law codec, at 8kHz
+	ALaw Codec = g711{format: "alaw", payloadType: 8, encode: alawEncode, decode: &alawTable}
+
+	// Slin16 is uncompressed 16-bit signed linear audio, at 16kHz
+	Slin16 Codec = slin{format: "slin16", payloadType: 118, sampleRate: 16000}
+)
+
+// g711 is a G.711 codec, which encodes each sample as one byte
+type g711 struct {
+	format      string
+	payloadType uint8
+	encode      func(int16) byte
+	decode      *[256]int16
+}
+
+func (c g711) Format() string     { return c.format }
+func (c g711) PayloadType() uint8 { return c.payloadType }
+func (c g711) SampleRate() int    { return 8000 }
+
+func (c g711) Encode(samples []int16) []byte {
+	ret := make([]byte, len(samples))
+
+	for i, s := range samples {
+		ret[i] = c.encode(s)
+	}
+
+	return ret
+}
+
+func (c g711) Decode(payload []byte) []int16 {
+	ret := make([]int16, len(payload))
+
+	for i, b := range payload {
+		ret[i] = c.decode[b]
+	}
+
+	return ret
+}
+
+// slin is signed linear audio, which RTP carries in network (big-endian) byte order
+type slin struct {
+	format      string
+	payloadType uint8
+	sampleRate  int
+}
+
+func (c slin) Format() string     { return c.format }
+func (c slin) PayloadType() uint8 { return c.payloadType }
+func (c slin) SampleRate() int    { return c.sampleRate }
+
+func (c slin) Encode(samples []int16) []byte {
+	ret := make([]byte, 2*len(samples))
+
+	for i, s := range samples {
+		binary.BigEndian.PutUint16(ret[2*i:], uint16(s))
+	}
+
+	return ret
+}
+
+func (c slin) Decode(payload []byte) []int16 {
+	ret := make([]int16, len(payload)/2)
+
+	for i := range ret {
+		ret[i] = int16(binary.BigEndian.Uint16(payload[2*i:]))
+	}
+
+	return ret
+}
+
+var ulawTable, alawTable [256]int16
+
+func init() {
+	for i := range 256 {
+		ulawTable[i] = ulawDecode(byte(i))
+		alawTable[i] = alawDecode(byte(i))
+	}
+}
+
+const (
+	ulawBias = 0x84
+	ulawClip = 32635
+)
+
+func ulawEncode(s int16) byte {
+	v := int(s)
+
+	var sign int
+
+	if v < 0 {
+		v = -v
+		sign = 0x80
+	}
+
+	if v > ulawClip {
+		v = ulawClip
+	}
+
+	v += ulawBias
+
+	exp := 7
+	for mask := 0x4000; v&mask == 0 && exp > 0; mask >>= 1 {
+		exp--
+	}
+
+	mantissa := (v >> (exp + 3)) & 0x0f
+
+	return ^byte(sign | exp<<4 | mantissa)
+}
+
+func ulawDecode(u byte) int16 {
+	u = ^u
+
+	exp := int(u>>4) & 0x07
+	v := ((int(u&0x0f) << 3) + ulawBias) << exp
+	v -= ulawBias
+
+	if u&0x80 != 0 {
+		return int16(-v)
+	}
+
+	return int16(v)
+}
+
+// alawSegments holds the upper bound of each A-law segment, in 13-bit magnitude
+var alawSegments = [8]int{0x1f, 0x3f, 0x7f, 0xff, 0x1ff, 0x3ff, 0x7ff, 0xfff}
+
+func alawEncode(s int16) byte {
+	v := int(s) >> 3
+
+	mask := 0xd5
+	if v < 0 {
+		mask = 0x55
+		v = -v - 1
+	}
+
+	seg := 0
+	for seg < len(alawSegments) && v > alawSegments[seg] {
+		seg++
+	}
+
+	if seg >= len(alawSegments) {
+		return byte(0x7f ^ mask)
+	}
+
+	a := seg << 4
+	if seg < 2 {
+		a |= (v >> 1) & 0x0f
+	} else {
+		a |= (v >> seg) & 0x0f
+	}
+
+	return byte(a ^ mask)
+}
+
+func alawDecode(a byte) int16 {
+	a ^= 0x55
+
+	t := int(a&0x0f) << 4
+	seg := int(a&0x70) >> 4
+
+	switch seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+
+	if a&0x80 != 0 {
+		return int16(t)
+	}
+
+	return int16(-t)
+}
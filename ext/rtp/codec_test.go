@@ -0,0 +1,69 @@
+package rtp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestG711(t *testing.T) {
+	tests := []struct {
+		codec   Codec
+		silence byte
+	}{
+		{ULaw, 0xff},
+		{ALaw, 0xd5},
+	}
+
+	for _, tc := range tests {
+		if b := tc.codec.Encode([]int16{0}); b[0] != tc.silence {
+			t.Errorf("%s: expected silence to encode as %#x, got %#x", tc.codec.Format(), tc.silence, b[0])
+		}
+
+		// Encoding a decoded value must return the same code
+		for i := range 256 {
+			s := tc.codec.Decode([]byte{byte(i)})
+			if b := tc.codec.Encode(s); b[0] != byte(i) && s[0] != 0 {
+				t.Errorf("%s: %#x decodes to %d, which encodes to %#x", tc.codec.Format(), i, s[0], b[0])
+			}
+		}
+
+		// The quantization error is bounded relative to the sample
+		for _, s := range []int16{1, 100, -100, 1000, -1000, 12345, -12345, 32767, -32768} {
+			d := tc.codec.Decode(tc.codec.Encode([]int16{s}))[0]
+
+			diff := int(d) - int(s)
+			if diff < 0 {
+				diff = -diff
+			}
+
+			if limit := abs(int(s))/16 + 64; diff > limit {
+				t.Errorf("%s: %d decoded as %d", tc.codec.Format(), s, d)
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func TestSlin16(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768}
+
+	payload := Slin16.Encode(samples)
+	if payload[2] != 0x00 || payload[3] != 0x01 {
+		t.Errorf("expected big-endian samples, got %x", payload)
+	}
+
+	if decoded := Slin16.Decode(payload); !reflect.DeepEqual(decoded, samples) {
+		t.Errorf("expected %v, got %v", samples, decoded)
+	}
+
+	if Slin16.SampleRate() != 16000 || Slin16.Format() != "slin16" {
+		t.Errorf("unexpected slin16 parameters")
+	}
+}
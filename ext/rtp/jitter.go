@@ -0,0 +1,112 @@
+package rtp
+
+import "time"
+
+// maxMisorder is the distance, in sequence numbers, behind the next expected
+// packet beyond which a packet is taken to mean that the stream restarted,
+// rather than that it arrived late
+const maxMisorder = 100
+
+type jitterEntry struct {
+	payload []byte
+	arrived time.Time
+}
+
+// jitterBuffer restores the order of the packets of a stream.  Packets are
+// released in sequence; a missing packet is waited for until either more
+// than depth later packets are held or the earliest of them has been held
+// for maxDelay, and is then taken to be lost.  Packets which arrive after
+// they were taken to be lost are discarded.
+type jitterBuffer struct {
+	depth    int
+	maxDelay time.Duration
+
+	started bool
+	next    uint16
+	pending map[uint16]jitterEntry
+
+	// lost counts the packets which were taken to be lost
+	lost int
+}
+
+func newJitterBuffer(depth int, maxDelay time.Duration) *jitterBuffer {
+	return &jitterBuffer{
+		depth:    depth,
+		maxDelay: maxDelay,
+		pending:  make(map[uint16]jitterEntry),
+	}
+}
+
+// reset discards the held packets, so that the next packet starts a new stream
+func (j *jitterBuffer) reset() {
+	j.started = false
+	clear(j.pending)
+}
+
+// push adds a packet to the buffer, returning false if it was discarded
+// because it is late or a duplicate
+func (j *jitterBuffer) push(seq uint16, payload []byte, now time.Time) bool {
+	if j.started && int16(seq-j.next) < -maxMisorder {
+		j.reset()
+	}
+
+	if !j.started {
+		j.started = true
+		j.next = seq
+	}
+
+	if int16(seq-j.next) < 0 {
+		return false
+	}
+
+	if _, ok := j.pending[seq]; ok {
+		return false
+	}
+
+	j.pending[seq] = jitterEntry{payload: payload, arrived: now}
+
+	return true
+}
+
+// pop returns the payload of the next packet, if it is ready to be released
+func (j *jitterBuffer) pop(now time.Time) ([]byte, bool) {
+	if e, ok := j.pending[j.next]; ok {
+		delete(j.pending, j.next)
+		j.next++
+
+		return e.payload, true
+	}
+
+	if len(j.pending) == 0 {
+		return nil, false
+	}
+
+	// Find the earliest packet held
+	var (
+		earliest uint16
+		arrived  time.Time
+	)
+
+	first := true
+
+	for seq, e := range j.pending {
+		if first || int16(seq-earliest) < 0 {
+			earliest = seq
+			first = false
+		}
+
+		if arrived.IsZero() || e.arrived.Before(arrived) {
+			arrived = e.arrived
+		}
+	}
+
+	if len(j.pending) <= j.depth && now.Sub(arrived) < j.maxDelay {
+		return nil, false
+	}
+
+	// Give up on the missing packets
+	j.lost += int(earliest - j.next)
+	j.next = earliest
+
+	return j.pop(now)
+}
@@ -0,0 +1,102 @@
+package rtp
+
+import (
+	"testing"
+	"time"
+)
+
+// popAll returns the first byte of each payload which the jitter buffer releases
+func popAll(jb *jitterBuffer, now time.Time) (ret []byte) {
+	for {
+		p, ok := jb.pop(now)
+		if !ok {
+			return ret
+		}
+
+		ret = append(ret, p[0])
+	}
+}
+
+func TestJitterBuffer(t *testing.T) {
+	now := time.Now()
+
+	jb := newJitterBuffer(2, 100*time.Millisecond)
+
+	push := func(seq uint16) bool {
+		return jb.push(seq, []byte{byte(seq)}, now)
+	}
+
+	// Packets in order are released at once, across the wrap of the
+	// sequence number
+	push(65535)
+
+	if got := popAll(jb, now); string(got) != "\xff" {
+		t.Errorf("expected the first packet, got %v", got)
+	}
+
+	push(0)
+
+	if got := popAll(jb, now); len(got) != 1 || got[0] != 0 {
+		t.Errorf("expected the packet after the wrap, got %v", got)
+	}
+
+	// Reordered packets are held until the missing one arrives
+	push(2)
+	push(3)
+
+	if got := popAll(jb, now); len(got) != 0 {
+		t.Errorf("expected packets to be held, got %v", got)
+	}
+
+	push(1)
+
+	if got := popAll(jb, now); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("expected reordered packets, got %v", got)
+	}
+
+	if push(2) {
+		t.Error("expected a duplicate packet to be discarded")
+	}
+
+	// A missing packet is lost once more than depth later packets are held
+	push(5)
+	push(6)
+
+	if got := popAll(jb, now); len(got) != 0 {
+		t.Errorf("expected packets to be held, got %v", got)
+	}
+
+	push(7)
+
+	if got := popAll(jb, now); len(got) != 3 || got[0] != 5 {
+		t.Errorf("expected packets after the loss, got %v", got)
+	}
+
+	if push(4) {
+		t.Error("expected a late packet to be discarded")
+	}
+
+	// ... or once the held packets have waited too long
+	push(9)
+
+	if got := popAll(jb, now.Add(50*time.Millisecond)); len(got) != 0 {
+		t.Errorf("expected packet to be held, got %v", got)
+	}
+
+	if got := popAll(jb, now.Add(100*time.Millisecond)); len(got) != 1 || got[0] != 9 {
+		t.Errorf("expected packet after the delay, got %v", got)
+	}
+
+	if jb.lost != 2 {
+		t.Errorf("expected 2 lost packets, got %d", jb.lost)
+	}
+
+	// A packet far behind restarts the stream
+	if !push(65535 - 140) {
+		t.Error("expected a restarted stream to be accepted")
+	}
+
+	if got := popAll(jb, now); len(got) != 1 {
+		t.Errorf("expected the restarted stream to be released, got %v", got)
+	}
+}
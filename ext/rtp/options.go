@@ -0,0 +1,132 @@
+package rtp
+
+import "time"
+
+var (
+	// DefaultListenAddress is the default UDP address on which an Endpoint
+	// listens for RTP
+	DefaultListenAddress = "127.0.0.1:0"
+
+	// DefaultPacketTime is the default duration of audio in each RTP packet
+	DefaultPacketTime = 20 * time.Millisecond
+
+	// DefaultJitterDepth is the default number of later packets which are
+	// held while waiting for a missing one
+	DefaultJitterDepth = 5
+)
+
+// Options describes the options for an RTP Endpoint
+type Options struct {
+	codec Codec
+
+	listen string
+
+	// externalHost is the <host>:<port> to which Asterisk sends RTP.  If
+	// empty, the address of the listener is used.
+	externalHost string
+
+	app string
+
+	channelID string
+
+	direction string
+
+	variables map[string]string
+
+	packetTime time.Duration
+
+	jitterDepth int
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		codec:       ULaw,
+		listen:      DefaultListenAddress,
+		direction:   "both",
+		packetTime:  DefaultPacketTime,
+		jitterDepth: DefaultJitterDepth,
+	}
+}
+
+// Apply applies a set of options for the Endpoint
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// WithCodec sets the codec of the audio (ex. Slin16).  The default is ULaw.
+func WithCodec(c Codec) OptionFunc {
+	return func(o *Options) {
+		o.codec = c
+	}
+}
+
+// ListenAddress sets the UDP address (ex. "10.0.0.5:0") on which the
+// Endpoint listens for RTP.  The default is DefaultListenAddress, which is
+// only reachable by an Asterisk on the same host.
+func ListenAddress(addr string) OptionFunc {
+	return func(o *Options) {
+		o.listen = addr
+	}
+}
+
+// ExternalHost sets the <host>:<port> to which Asterisk should send RTP,
+// where it differs from the address on which the Endpoint listens (ex.
+// behind NAT, or when listening on all interfaces)
+func ExternalHost(hostport string) OptionFunc {
+	return func(o *Options) {
+		o.externalHost = hostport
+	}
+}
+
+// App sets the ARI application into which the external media channel is
+// placed.  By default, the current application is used.
+func App(name string) OptionFunc {
+	return func(o *Options) {
+		o.app = name
+	}
+}
+
+// ChannelID sets the ID of the external media channel.  By default, an ID
+// is generated.
+func ChannelID(id string) OptionFunc {
+	return func(o *Options) {
+		o.channelID = id
+	}
+}
+
+// Direction sets the direction of the audio of the external media channel.
+// The default is "both".
+func Direction(dir string) OptionFunc {
+	return func(o *Options) {
+		o.direction = dir
+	}
+}
+
+// Variables sets channel variables on the external media channel
+func Variables(vars map[string]string) OptionFunc {
+	return func(o *Options) {
+		o.variables = vars
+	}
+}
+
+// PacketTime sets the duration of audio in each RTP packet sent by the
+// Endpoint.  The default is DefaultPacketTime.
+func PacketTime(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.packetTime = d
+	}
+}
+
+// JitterDepth sets the number of later packets which are held while waiting
+// for a missing one, before it is taken to be lost.  The default is
+// DefaultJitterDepth.
+func JitterDepth(n int) OptionFunc {
+	return func(o *Options) {
+		o.jitterDepth = n
+	}
+}
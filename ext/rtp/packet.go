@@ -0,0 +1,79 @@
+package rtp
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidPacket indicates that a datagram is not a valid RTP packet
+var ErrInvalidPacket = errors.New("invalid RTP packet")
+
+const headerSize = 12
+
+// header is the fixed header of an RTP packet (RFC 3550, section 5.1)
+type header struct {
+	payloadType uint8
+	marker      bool
+	sequence    uint16
+	timestamp   uint32
+	ssrc        uint32
+}
+
+// marshal returns the RTP packet of the header and the given payload
+func (h *header) marshal(payload []byte) []byte {
+	b := make([]byte, headerSize+len(payload))
+
+	b[0] = 2 << 6 // version 2; no padding, extension, or CSRCs
+
+	b[1] = h.payloadType & 0x7f
+	if h.marker {
+		b[1] |= 0x80
+	}
+
+	binary.BigEndian.PutUint16(b[2:], h.sequence)
+	binary.BigEndian.PutUint32(b[4:], h.timestamp)
+	binary.BigEndian.PutUint32(b[8:], h.ssrc)
+	copy(b[headerSize:], payload)
+
+	return b
+}
+
+// parsePacket parses an RTP packet, returning its header and payload.  Any
+// CSRCs, header extension, and padding are skipped.
+func parsePacket(b []byte) (h header, payload []byte, err error) {
+	if len(b) < headerSize || b[0]>>6 != 2 {
+		return h, nil, ErrInvalidPacket
+	}
+
+	h.marker = b[1]&0x80 != 0
+	h.payloadType = b[1] & 0x7f
+	h.sequence = binary.BigEndian.Uint16(b[2:])
+	h.timestamp = binary.BigEndian.Uint32(b[4:])
+	h.ssrc = binary.BigEndian.Uint32(b[8:])
+
+	offset := headerSize + 4*int(b[0]&0x0f)
+
+	if b[0]&0x10 != 0 {
+		if len(b) < offset+4 {
+			return h, nil, ErrInvalidPacket
+		}
+
+		offset += 4 + 4*int(binary.BigEndian.Uint16(b[offset+2:]))
+	}
+
+	end := len(b)
+
+	if b[0]&0x20 != 0 {
+		if end == 0 || int(b[end-1]) == 0 {
+			return h, nil, ErrInvalidPacket
+		}
+
+		end -= int(b[end-1])
+	}
+
+	if offset > end {
+		return h, nil, ErrInvalidPacket
+	}
+
+	return h, b[offset:end], nil
+}
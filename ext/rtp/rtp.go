@@ -0,0 +1,454 @@
+// Package rtp provides the Go side of an external media channel (see
+// ari.Channel.ExternalMedia):  an Endpoint which receives and sends the
+// channel's audio as RTP over UDP, and exposes it as an io.Reader and an
+// io.Writer of PCM.
+//
+// The PCM is 16-bit signed linear, little-endian, mono audio at the sample
+// rate of the codec (8kHz for ULaw and ALaw; 16kHz for Slin16).  Received
+// packets pass through a jitter buffer, which restores their order;  sent
+// audio is packetized and paced in real time.
+package rtp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/rid"
+)
+
+// ErrNoRemote indicates that audio cannot be sent because the address of
+// the remote end is not yet known
+var ErrNoRemote = errors.New("remote address unknown")
+
+// receiveQueue is the number of received frames which are queued for the
+// reader; frames received while the queue is full are discarded
+const receiveQueue = 50
+
+// Endpoint is the local end of an RTP media stream
+type Endpoint struct {
+	conn  *net.UDPConn
+	codec Codec
+
+	packetTime  time.Duration
+	jitterDepth int
+
+	// h is the external media channel, if the Endpoint created one
+	h *ari.ChannelHandle
+
+	remote atomic.Pointer[net.UDPAddr]
+
+	frames chan []byte
+
+	rmu  sync.Mutex
+	rbuf []byte
+
+	wmu       sync.Mutex
+	wbuf      []byte
+	sequence  uint16
+	timestamp uint32
+	ssrc      uint32
+	nextSend  time.Time
+
+	closeOnce sync.Once
+	closeErr  error
+	done      chan struct{}
+}
+
+// Listen starts an Endpoint listening for RTP, without creating an external
+// media channel.  The remote end is the source of the first packet
+// received, unless it is set with SetRemote.
+func Listen(opts ...OptionFunc) (*Endpoint, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	return listen(o)
+}
+
+func listen(o *Options) (*Endpoint, error) {
+	if o.codec == nil {
+		return nil, errors.New("no codec")
+	}
+
+	if o.packetTime <= 0 {
+		return nil, errors.New("packet time must be positive")
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", o.listen)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to resolve listen address %s", o.listen)
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to listen for RTP")
+	}
+
+	e := &Endpoint{
+		conn:        conn,
+		codec:       o.codec,
+		packetTime:  o.packetTime,
+		jitterDepth: o.jitterDepth,
+		frames:      make(chan []byte, receiveQueue),
+		sequence:    uint16(rand.Uint32()),
+		timestamp:   rand.Uint32(),
+		ssrc:        rand.Uint32(),
+		done:        make(chan struct{}),
+	}
+
+	go e.receive()
+
+	return e, nil
+}
+
+// Open starts an Endpoint listening for RTP and creates an external media
+// channel which sends its audio there.  The remote end is the address which
+// Asterisk reports in the channel's UNICASTRTP_LOCAL_ADDRESS and
+// UNICASTRTP_LOCAL_PORT variables.  The Endpoint is closed, and the channel
+// hung up, when the context is cancelled.
+func Open(ctx context.Context, c ari.Channel, opts ...OptionFunc) (*Endpoint, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	e, err := listen(o)
+	if err != nil {
+		return nil, err
+	}
+
+	host := o.externalHost
+	if host == "" {
+		if e.LocalAddr().IP.IsUnspecified() {
+			e.Close() // nolint
+
+			return nil, errors.New("an ExternalHost is required when listening on all interfaces")
+		}
+
+		host = e.LocalAddr().String()
+	}
+
+	id := o.channelID
+	if id == "" {
+		id = rid.New(rid.Channel)
+	}
+
+	mo := ari.ExternalMediaOptions{
+		ChannelID:      id,
+		App:            o.app,
+		ExternalHost:   host,
+		Encapsulation:  "rtp",
+		Transport:      "udp",
+		ConnectionType: "client",
+		Format:         o.codec.Format(),
+		Direction:      o.direction,
+		Variables:      o.variables,
+	}
+
+	if err := mo.Validate(); err != nil {
+		e.Close() // nolint
+
+		return nil, eris.Wrap(err, "invalid external media options")
+	}
+
+	h, err := c.ExternalMedia(ari.NewKey(ari.ChannelKey, id), mo)
+	if err != nil {
+		e.Close() // nolint
+
+		return nil, eris.Wrap(err, "failed to create external media channel")
+	}
+
+	e.h = h
+
+	remote, err := remoteAddress(h)
+	if err != nil {
+		e.Close() // nolint
+
+		return nil, err
+	}
+
+	e.SetRemote(remote)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			e.Close() // nolint
+		case <-e.done:
+		}
+	}()
+
+	return e, nil
+}
+
+// remoteAddress returns the address from which Asterisk sends the RTP of the
+// external media channel
+func remoteAddress(h *ari.ChannelHandle) (*net.UDPAddr, error) {
+	host, err := h.GetVariable("UNICASTRTP_LOCAL_ADDRESS")
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to get UNICASTRTP_LOCAL_ADDRESS")
+	}
+
+	port, err := h.GetVariable("UNICASTRTP_LOCAL_PORT")
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to get UNICASTRTP_LOCAL_PORT")
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid RTP address %s:%s", host, port)
+	}
+
+	return addr, nil
+}
+
+// Channel returns the external media channel, if the Endpoint was created by Open
+func (e *Endpoint) Channel() *ari.ChannelHandle {
+	return e.h
+}
+
+// Codec returns the codec of the Endpoint
+func (e *Endpoint) Codec() Codec {
+	return e.codec
+}
+
+// LocalAddr returns the address on which the Endpoint listens
+func (e *Endpoint) LocalAddr() *net.UDPAddr {
+	return e.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// RemoteAddr returns the address to which the Endpoint sends audio, or nil
+// if it is not yet known
+func (e *Endpoint) RemoteAddr() *net.UDPAddr {
+	return e.remote.Load()
+}
+
+// SetRemote sets the address to which the Endpoint sends audio
+func (e *Endpoint) SetRemote(addr *net.UDPAddr) {
+	e.remote.Store(addr)
+}
+
+// Close stops the Endpoint and, if it was created by Open, hangs up the
+// external media channel.  Reads return io.EOF once the audio received
+// before the Endpoint was closed has been read.
+func (e *Endpoint) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.done)
+
+		if e.h != nil {
+			if err := e.h.Hangup(); err != nil {
+				e.closeErr = eris.Wrap(err, "failed to hang up external media channel")
+			}
+		}
+
+		if err := e.conn.Close(); err != nil && e.closeErr == nil {
+			e.closeErr = err
+		}
+	})
+
+	return e.closeErr
+}
+
+// Read reads received audio as PCM, blocking until some is available
+func (e *Endpoint) Read(p []byte) (int, error) {
+	e.rmu.Lock()
+	defer e.rmu.Unlock()
+
+	for len(e.rbuf) == 0 {
+		f, ok := <-e.frames
+		if !ok {
+			return 0, io.EOF
+		}
+
+		e.rbuf = f
+	}
+
+	n := copy(p, e.rbuf)
+	e.rbuf = e.rbuf[n:]
+
+	return n, nil
+}
+
+// receive reads RTP packets until the Endpoint is closed, queueing their
+// audio for the reader
+func (e *Endpoint) receive() {
+	defer close(e.frames)
+
+	jb := newJitterBuffer(e.jitterDepth, time.Duration(e.jitterDepth)*e.packetTime)
+	buf := make([]byte, 1500)
+
+	var (
+		ssrc    uint32
+		started bool
+	)
+
+	for {
+		// Wake periodically, so that packets held for a lost one are released
+		_ = e.conn.SetReadDeadline(time.Now().Add(e.packetTime)) // nolint
+
+		n, addr, err := e.conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				e.release(jb, time.Now())
+				continue
+			}
+
+			return
+		}
+
+		h, payload, err := parsePacket(buf[:n])
+		if err != nil || h.payloadType != e.codec.PayloadType() {
+			continue
+		}
+
+		e.remote.CompareAndSwap(nil, addr)
+
+		if !started || h.ssrc != ssrc {
+			jb.reset()
+
+			ssrc = h.ssrc
+			started = true
+		}
+
+		jb.push(h.sequence, append([]byte(nil), payload...), time.Now())
+		e.release(jb, time.Now())
+	}
+}
+
+// release queues the audio of the packets which the jitter buffer releases
+func (e *Endpoint) release(jb *jitterBuffer, now time.Time) {
+	for {
+		payload, ok := jb.pop(now)
+		if !ok {
+			return
+		}
+
+		select {
+		case e.frames <- pcmBytes(e.codec.Decode(payload)):
+		default:
+		}
+	}
+}
+
+// frameSize returns the number of bytes of PCM in each packet
+func (e *Endpoint) frameSize() int {
+	return 2 * e.samplesPerFrame()
+}
+
+func (e *Endpoint) samplesPerFrame() int {
+	return int(time.Duration(e.codec.SampleRate()) * e.packetTime / time.Second)
+}
+
+// Write sends the given PCM audio.  It is sent in packets of the packet time
+// (see PacketTime), paced in real time, so Write blocks for about as long as
+// the audio lasts.  Audio which does not fill a packet is held until the
+// next Write.  If a packet cannot be sent, the audio which was held is
+// dropped, and the number of bytes of p which were sent is returned.
+func (e *Endpoint) Write(p []byte) (int, error) {
+	e.wmu.Lock()
+	defer e.wmu.Unlock()
+
+	select {
+	case <-e.done:
+		return 0, net.ErrClosed
+	default:
+	}
+
+	remote := e.remote.Load()
+	if remote == nil {
+		return 0, ErrNoRemote
+	}
+
+	held := len(e.wbuf)
+	e.wbuf = append(e.wbuf, p...)
+
+	size := e.frameSize()
+
+	var sent int
+
+	for len(e.wbuf)-sent >= size {
+		if err := e.send(remote, e.wbuf[sent:sent+size]); err != nil {
+			// Drop the unsent audio, so that the caller may retry with
+			// the rest of p without repeating any of it
+			e.wbuf = e.wbuf[:0]
+
+			return max(sent-held, 0), err
+		}
+
+		sent += size
+	}
+
+	e.wbuf = append(e.wbuf[:0], e.wbuf[sent:]...)
+
+	return len(p), nil
+}
+
+// send sends one packet of PCM audio, once it is due
+func (e *Endpoint) send(remote *net.UDPAddr, pcm []byte) error {
+	now := time.Now()
+
+	var marker bool
+
+	if e.nextSend.IsZero() || now.Sub(e.nextSend) > e.packetTime {
+		// The audio starts or resumes after a pause, so the timestamp
+		// advances by the length of the pause
+		if !e.nextSend.IsZero() {
+			e.timestamp += uint32(time.Duration(e.codec.SampleRate()) * now.Sub(e.nextSend) / time.Second)
+		}
+
+		e.nextSend = now
+		marker = true
+	}
+
+	if wait := time.Until(e.nextSend); wait > 0 {
+		t := time.NewTimer(wait)
+
+		select {
+		case <-t.C:
+		case <-e.done:
+			t.Stop()
+			return net.ErrClosed
+		}
+	}
+
+	samples := make([]int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+
+	h := header{
+		payloadType: e.codec.PayloadType(),
+		marker:      marker,
+		sequence:    e.sequence,
+		timestamp:   e.timestamp,
+		ssrc:        e.ssrc,
+	}
+
+	if _, err := e.conn.WriteToUDP(h.marshal(e.codec.Encode(samples)), remote); err != nil {
+		return eris.Wrap(err, "failed to send RTP")
+	}
+
+	e.sequence++
+	e.timestamp += uint32(len(samples))
+	e.nextSend = e.nextSend.Add(e.packetTime)
+
+	return nil
+}
+
+// pcmBytes returns the samples as little-endian PCM
+func pcmBytes(samples []int16) []byte {
+	ret := make([]byte, 2*len(samples))
+
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(ret[2*i:], uint16(s))
+	}
+
+	return ret
+}
@@ -0,0 +1,300 @@
+package rtp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+)
+
+func TestPacket(t *testing.T) {
+	h := header{payloadType: 118, marker: true, sequence: 65535, timestamp: 160, ssrc: 0xdeadbeef}
+
+	b := h.marshal([]byte{1, 2, 3})
+
+	parsed, payload, err := parsePacket(b)
+	if err != nil {
+		t.Fatalf("failed to parse packet: %v", err)
+	}
+
+	if parsed != h || !bytes.Equal(payload, []byte{1, 2, 3}) {
+		t.Errorf("expected %+v, got %+v %v", h, parsed, payload)
+	}
+
+	// CSRC, header extension, and padding
+	b = []byte{0xb1, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1,
+		9, 9, 9, 9, // CSRC
+		0xbe, 0xde, 0, 1, 8, 8, 8, 8, // extension of one word
+		1, 2, 3, 0, 0, 3, // payload and padding
+	}
+
+	if _, payload, err = parsePacket(b); err != nil || !bytes.Equal(payload, []byte{1, 2, 3}) {
+		t.Errorf("expected payload after CSRC and extension, got %v (%v)", payload, err)
+	}
+
+	for _, b := range [][]byte{
+		{0x80, 0x00},
+		{0x40, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1},
+		{0x8f, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1},
+		{0xa0, 0x00, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 1, 20},
+	} {
+		if _, _, err := parsePacket(b); !errors.Is(err, ErrInvalidPacket) {
+			t.Errorf("%x: expected ErrInvalidPacket, got %v", b, err)
+		}
+	}
+}
+
+// ramp returns frames of PCM, as the codec reproduces them, and the PCM to
+// write to produce them
+func ramp(c Codec, samples int) (written, expected []byte) {
+	s := make([]int16, samples)
+	for i := range s {
+		s[i] = int16((i%200 - 100) * 300)
+	}
+
+	return pcmBytes(s), pcmBytes(c.Decode(c.Encode(s)))
+}
+
+// readFull reads the given number of bytes from the Endpoint, failing the
+// test if they do not arrive in time
+func readFull(t *testing.T, e *Endpoint, n int) []byte {
+	t.Helper()
+
+	buf := make([]byte, n)
+	errCh := make(chan error, 1)
+
+	go func() {
+		_, err := io.ReadFull(e, buf)
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out reading audio")
+	}
+
+	return buf
+}
+
+func TestLoopback(t *testing.T) {
+	for _, c := range []Codec{ULaw, ALaw, Slin16} {
+		a, err := Listen(WithCodec(c))
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+
+		b, err := Listen(WithCodec(c))
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+
+		if _, err := b.Write([]byte{0, 0}); !errors.Is(err, ErrNoRemote) {
+			t.Errorf("%s: expected ErrNoRemote, got %v", c.Format(), err)
+		}
+
+		a.SetRemote(b.LocalAddr())
+
+		// Three packets, plus part of one which is held
+		frames := 3 * c.SampleRate() / 50
+		written, expected := ramp(c, frames)
+
+		if _, err := a.Write(append(written, 0, 0)); err != nil {
+			t.Fatalf("%s: failed to write: %v", c.Format(), err)
+		}
+
+		if got := readFull(t, b, len(expected)); !bytes.Equal(got, expected) {
+			t.Errorf("%s: received audio differs from that sent", c.Format())
+		}
+
+		// The remote is learned from the received audio
+		if b.RemoteAddr().Port != a.LocalAddr().Port {
+			t.Errorf("%s: expected remote %v, got %v", c.Format(), a.LocalAddr(), b.RemoteAddr())
+		}
+
+		if _, err := b.Write(written); err != nil {
+			t.Fatalf("%s: failed to write back: %v", c.Format(), err)
+		}
+
+		if got := readFull(t, a, len(expected)); !bytes.Equal(got, expected) {
+			t.Errorf("%s: returned audio differs from that sent", c.Format())
+		}
+
+		a.Close() // nolint
+		b.Close() // nolint
+
+		if _, err := b.Read(make([]byte, 10)); err != io.EOF {
+			t.Errorf("%s: expected EOF after close, got %v", c.Format(), err)
+		}
+
+		if _, err := a.Write(written); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("%s: expected write after close to fail, got %v", c.Format(), err)
+		}
+	}
+}
+
+func TestWriteClosed(t *testing.T) {
+	a, err := Listen(WithCodec(Slin16))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	b, err := Listen(WithCodec(Slin16))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer b.Close() // nolint
+
+	a.SetRemote(b.LocalAddr())
+
+	// Half a packet is held, then a second of audio is written, which is
+	// interrupted by the close
+	size := a.frameSize()
+
+	if _, err := a.Write(make([]byte, size/2)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	time.AfterFunc(100*time.Millisecond, func() {
+		a.Close() // nolint
+	})
+
+	p := make([]byte, 50*size)
+
+	n, err := a.Write(p)
+	if !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected the write to be interrupted by the close, got %v", err)
+	}
+
+	if n <= 0 || n >= len(p) || (n+size/2)%size != 0 {
+		t.Errorf("expected the whole packets sent from p to be counted, got %d of %d", n, len(p))
+	}
+
+	if len(a.wbuf) != 0 {
+		t.Errorf("expected the unsent audio to be dropped, %d bytes held", len(a.wbuf))
+	}
+}
+
+func TestLoopbackReorder(t *testing.T) {
+	e, err := Listen(WithCodec(Slin16))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer e.Close() // nolint
+
+	conn, err := net.DialUDP("udp", nil, e.LocalAddr())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close() // nolint
+
+	send := func(seq uint16, pt uint8) {
+		h := header{payloadType: pt, sequence: seq, ssrc: 1}
+
+		if _, err := conn.Write(h.marshal(Slin16.Encode([]int16{int16(seq)}))); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+	}
+
+	for _, seq := range []uint16{10, 12, 11, 11, 14, 13} {
+		send(seq, Slin16.PayloadType())
+	}
+
+	send(15, ULaw.PayloadType()) // not of the codec, so ignored
+	send(16, Slin16.PayloadType())
+
+	expected := pcmBytes([]int16{10, 11, 12, 13, 14, 16})
+
+	if got := readFull(t, e, len(expected)); !bytes.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	asterisk, err := Listen(WithCodec(Slin16))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer asterisk.Close() // nolint
+
+	key := ari.NewKey(ari.ChannelKey, "media-1")
+
+	c := &arimocks.Channel{}
+	c.On("ExternalMedia", key, mock.Anything).Return(ari.NewChannelHandle(key, c, nil), nil)
+	c.On("GetVariable", key, "UNICASTRTP_LOCAL_ADDRESS").Return("127.0.0.1", nil)
+	c.On("GetVariable", key, "UNICASTRTP_LOCAL_PORT").Return(strconv.Itoa(asterisk.LocalAddr().Port), nil)
+	c.On("Hangup", key, string(ari.HangupNormal)).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e, err := Open(ctx, c, WithCodec(Slin16), ChannelID("media-1"), App("agent"))
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	opts := c.Calls[0].Arguments.Get(1).(ari.ExternalMediaOptions)
+
+	if opts.ExternalHost != e.LocalAddr().String() || opts.Format != "slin16" || opts.Encapsulation != "rtp" ||
+		opts.Direction != "both" || opts.App != "agent" {
+		t.Errorf("unexpected external media options %+v", opts)
+	}
+
+	if e.Channel().ID() != "media-1" {
+		t.Errorf("expected channel media-1, got %s", e.Channel().ID())
+	}
+
+	written, expected := ramp(Slin16, 320)
+
+	if _, err := e.Write(written); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if got := readFull(t, asterisk, len(expected)); !bytes.Equal(got, expected) {
+		t.Error("audio received by asterisk differs from that sent")
+	}
+
+	cancel()
+
+	if _, err := io.Copy(io.Discard, e); err != nil {
+		t.Errorf("expected EOF once cancelled, got %v", err)
+	}
+
+	c.AssertCalled(t, "Hangup", key, string(ari.HangupNormal))
+}
+
+func TestOpenFailure(t *testing.T) {
+	key := ari.NewKey(ari.ChannelKey, "media-1")
+
+	c := &arimocks.Channel{}
+	c.On("ExternalMedia", key, mock.Anything).Return(ari.NewChannelHandle(key, c, nil), nil)
+	c.On("GetVariable", key, mock.Anything).Return("", errors.New("variable not found"))
+	c.On("Hangup", key, string(ari.HangupNormal)).Return(nil)
+
+	if _, err := Open(context.Background(), c, ChannelID("media-1")); err == nil {
+		t.Error("expected error without the RTP address")
+	}
+
+	c.AssertCalled(t, "Hangup", key, string(ari.HangupNormal))
+
+	if _, err := Open(context.Background(), c, ListenAddress("0.0.0.0:0")); err == nil {
+		t.Error("expected error listening on all interfaces without an external host")
+	}
+
+	if _, err := Open(context.Background(), c, Direction("sideways")); err == nil {
+		t.Error("expected error for an invalid direction")
+	}
+
+	c.AssertNumberOfCalls(t, "ExternalMedia", 1)
+}
@@ -0,0 +1,359 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+)
+
+// ErrNotInProgress indicates that an operation was attempted on a consultation which has already ended
+var ErrNotInProgress = errors.New("transfer is not in progress")
+
+// Consultation is an attended transfer in progress.  The transferee waits on
+// hold in the original bridge while the transferor consults with the target
+// in a consultation bridge.  The transferor may then Complete the transfer,
+// Cancel it, or Swap between the two parties.
+type Consultation struct {
+	client ari.Client
+	o      *Options
+
+	// bridge is the original bridge, which holds the transferee
+	bridge *ari.BridgeHandle
+
+	// consult is the consultation bridge, which holds the target
+	consult *ari.BridgeHandle
+
+	transferor *ari.ChannelHandle
+	transferee *ari.ChannelHandle
+
+	subs []ari.Subscription
+
+	done chan struct{}
+
+	mu  sync.Mutex
+	res Result
+
+	// withTarget indicates that the transferor is in the consultation bridge, with the target
+	withTarget bool
+}
+
+// Attended begins an attended transfer.  The transferor is removed from the
+// given bridge, leaving the transferee on hold there, and the target
+// endpoint (tech/resource) is dialed.  Attended returns once the target
+// answers or fails to, or the transferee hangs up.
+//
+// If the target answers, the transferor is joined with it in a consultation
+// bridge and the transfer is in progress (see Consultation).  Otherwise, the
+// transferor is returned to the original bridge, the transferee is taken off
+// hold, and the Result of the consultation describes the failure.
+func Attended(ctx context.Context, client ari.Client, bridge *ari.BridgeHandle, transferor, transferee *ari.ChannelHandle, target string, opts ...OptionFunc) (*Consultation, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	c := &Consultation{
+		client:     client,
+		o:          o,
+		bridge:     bridge,
+		transferor: transferor,
+		transferee: transferee,
+		done:       make(chan struct{}),
+	}
+
+	// Watch for the transferee to hang up from the start, so that the dial
+	// of the target is cancelled if it does
+	transfereeSub := transferee.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd)
+
+	defer func() {
+		// Once the consultation is in progress, the subscription is handed to its watcher
+		if c.subs == nil {
+			transfereeSub.Cancel()
+		}
+	}()
+
+	if err := bridge.RemoveChannel(transferor.ID()); err != nil {
+		return nil, eris.Wrap(err, "failed to remove transferor from bridge")
+	}
+
+	if err := transferee.MOH(o.mohClass); err != nil {
+		c.restore()
+		return nil, eris.Wrap(err, "failed to hold transferee")
+	}
+
+	var err error
+
+	c.consult, err = joinBridge(client, transferor.Key(), transferor.ID())
+	if err != nil {
+		c.restore()
+		return nil, eris.Wrap(err, "failed to create consultation bridge")
+	}
+
+	c.withTarget = true
+
+	if err := startWaiting(transferor, o); err != nil {
+		c.restore()
+		return nil, eris.Wrap(err, "failed to indicate ringing to transferor")
+	}
+
+	s, err := dial.Dial(ctx, client, target, append([]dial.OptionFunc{dial.Originator(transferor.Key())}, o.dialOptions...)...)
+	if err != nil {
+		stopWaiting(transferor, o)
+		c.restore()
+
+		return nil, eris.Wrap(err, "failed to dial transfer target")
+	}
+
+	c.res.Target = s.Handle()
+
+	var transfereeGone bool
+
+	select {
+	case <-s.Done():
+	case <-transfereeSub.Events():
+		transfereeGone = true
+
+		s.Cancel()
+	}
+
+	c.res.Dial, err = s.Result()
+
+	stopWaiting(transferor, o)
+
+	switch {
+	case transfereeGone:
+		hangup(client, c.res.Target, c.res.Dial)
+		c.restore()
+
+		c.res.Outcome = TransfereeHungUp
+		close(c.done)
+
+		return c, nil
+	case c.res.Dial.Outcome != dial.Answered:
+		c.restore()
+
+		c.res.Outcome = TargetUnavailable
+		if c.res.Dial.Outcome == dial.Cancelled {
+			c.res.Outcome = Cancelled
+		}
+
+		close(c.done)
+
+		return c, err
+	}
+
+	if err := c.consult.AddChannel(c.res.Target.ID()); err != nil {
+		_ = client.Channel().Hangup(c.res.Target.Key(), string(ari.HangupNormal)) // nolint
+		c.restore()
+
+		c.res.Outcome = Failed
+		close(c.done)
+
+		return c, eris.Wrap(err, "failed to add target to consultation bridge")
+	}
+
+	c.subs = []ari.Subscription{
+		c.res.Target.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd),
+		transfereeSub,
+	}
+
+	go c.watch(c.subs[0], TargetHungUp)
+	go c.watch(c.subs[1], TransfereeHungUp)
+
+	return c, nil
+}
+
+// watch ends the consultation with the given outcome when an event is
+// received on the subscription, which watches for the hangup of a party
+func (c *Consultation) watch(sub ari.Subscription, outcome Outcome) {
+	select {
+	case <-c.done:
+		return
+	case _, ok := <-sub.Events():
+		if !ok {
+			return
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.res.Outcome != InProgress {
+		return
+	}
+
+	switch outcome {
+	case TargetHungUp:
+		// Return the transferor to the transferee
+		c.restore()
+	case TransfereeHungUp:
+		// Leave the transferor with the target
+		if !c.withTarget {
+			_ = c.bridge.RemoveChannel(c.transferor.ID()) // nolint
+			_ = c.res.Target.StopMOH()                    // nolint
+			_ = c.consult.AddChannel(c.transferor.ID())   // nolint
+		}
+
+		c.res.Bridge = c.consult
+	}
+
+	c.finish(outcome)
+}
+
+// restore returns the transferor to the original bridge, takes the
+// transferee off hold, and deletes the consultation bridge.  Errors are
+// ignored, since any of the parties may already be gone.
+func (c *Consultation) restore() {
+	if c.consult != nil {
+		_ = c.consult.Delete() // nolint
+	}
+
+	_ = c.transferee.StopMOH()                 // nolint
+	_ = c.bridge.AddChannel(c.transferor.ID()) // nolint
+
+	c.withTarget = false
+}
+
+// finish ends the consultation with the given outcome.  It must be called with the lock held.
+func (c *Consultation) finish(outcome Outcome) {
+	c.res.Outcome = outcome
+
+	for _, s := range c.subs {
+		s.Cancel()
+	}
+
+	close(c.done)
+}
+
+// Complete completes the transfer:  the target joins the transferee in the
+// original bridge, and the consultation bridge is deleted.  The transferor
+// is removed from both bridges; it is left to the caller to hang it up or
+// to continue to use it.
+func (c *Consultation) Complete() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.res.Outcome != InProgress {
+		return ErrNotInProgress
+	}
+
+	if c.withTarget {
+		_ = c.transferee.StopMOH() // nolint
+	} else {
+		if err := c.bridge.RemoveChannel(c.transferor.ID()); err != nil {
+			return eris.Wrap(err, "failed to remove transferor from bridge")
+		}
+
+		_ = c.res.Target.StopMOH() // nolint
+	}
+
+	// Deleting the consultation bridge releases the transferor and the target
+	if err := c.consult.Delete(); err != nil {
+		return eris.Wrap(err, "failed to delete consultation bridge")
+	}
+
+	if err := c.bridge.AddChannel(c.res.Target.ID()); err != nil {
+		c.finish(Failed)
+		return eris.Wrap(err, "failed to add target to bridge")
+	}
+
+	c.res.Bridge = c.bridge
+	c.finish(Completed)
+
+	return nil
+}
+
+// Cancel cancels the transfer:  the target is hung up, and the transferor
+// is returned to the transferee in the original bridge.
+func (c *Consultation) Cancel() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.res.Outcome != InProgress {
+		return ErrNotInProgress
+	}
+
+	if err := c.client.Channel().Hangup(c.res.Target.Key(), string(ari.HangupNormal)); err != nil {
+		return eris.Wrap(err, "failed to hang up target")
+	}
+
+	if !c.withTarget {
+		// The transferor is already with the transferee
+		_ = c.consult.Delete() // nolint
+
+		c.finish(Cancelled)
+
+		return nil
+	}
+
+	c.restore()
+	c.finish(Cancelled)
+
+	return nil
+}
+
+// Swap moves the transferor between the target and the transferee.  The
+// party which is left waits on hold.
+func (c *Consultation) Swap() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.res.Outcome != InProgress {
+		return ErrNotInProgress
+	}
+
+	from, to := c.consult, c.bridge
+	held, resumed := c.res.Target, c.transferee
+
+	if !c.withTarget {
+		from, to = to, from
+		held, resumed = resumed, held
+	}
+
+	if err := from.RemoveChannel(c.transferor.ID()); err != nil {
+		return eris.Wrap(err, "failed to remove transferor from bridge")
+	}
+
+	if err := held.MOH(c.o.mohClass); err != nil {
+		_ = from.AddChannel(c.transferor.ID()) // nolint
+		return eris.Wrap(err, "failed to hold party")
+	}
+
+	_ = resumed.StopMOH() // nolint
+
+	if err := to.AddChannel(c.transferor.ID()); err != nil {
+		return eris.Wrap(err, "failed to add transferor to bridge")
+	}
+
+	c.withTarget = !c.withTarget
+
+	return nil
+}
+
+// WithTarget indicates whether the transferor is currently talking with the
+// target (true) or with the transferee (false)
+func (c *Consultation) WithTarget() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.withTarget
+}
+
+// Done returns a channel which is closed when the consultation ends
+func (c *Consultation) Done() <-chan struct{} {
+	return c.done
+}
+
+// Result returns the current result of the transfer.  Its outcome is
+// InProgress until the consultation ends.
+func (c *Consultation) Result() *Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := c.res
+
+	return &res
+}
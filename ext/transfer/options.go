@@ -0,0 +1,65 @@
+package transfer
+
+import (
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+)
+
+// Options describes the options for a transfer
+type Options struct {
+	from *ari.BridgeHandle
+
+	moh      bool
+	mohClass string
+
+	dialOptions []dial.OptionFunc
+}
+
+func defaultOptions() *Options {
+	return new(Options)
+}
+
+// Apply applies a set of options for the transfer
+func (o *Options) Apply(opts ...OptionFunc) {
+	for _, f := range opts {
+		f(o)
+	}
+}
+
+// OptionFunc is a function which applies changes to an Options set
+type OptionFunc func(*Options)
+
+// From sets the bridge in which the transferred channel currently resides.
+// When a blind transfer completes, the channel is removed from this bridge.
+// It must be set if the channel is in a bridge; otherwise, the channel is
+// taken to be alone.
+func From(bridge *ari.BridgeHandle) OptionFunc {
+	return func(o *Options) {
+		o.from = bridge
+	}
+}
+
+// MusicOnHold plays the given music on hold class (or the default class, if
+// empty) to the waiting party while the target is dialed, in place of the
+// indication of ringing.
+func MusicOnHold(class string) OptionFunc {
+	return func(o *Options) {
+		o.moh = true
+		o.mohClass = class
+	}
+}
+
+// HoldClass sets the music on hold class which is played to the party on
+// hold during an attended transfer
+func HoldClass(class string) OptionFunc {
+	return func(o *Options) {
+		o.mohClass = class
+	}
+}
+
+// DialOptions sets the options which are applied to the dial of the target (ex. dial.CallerID)
+func DialOptions(opts ...dial.OptionFunc) OptionFunc {
+	return func(o *Options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
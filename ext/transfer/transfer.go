@@ -0,0 +1,218 @@
+// Package transfer provides blind and attended call transfers, performed
+// from ARI with bridges, music on hold, and outbound dials.
+package transfer
+
+import (
+	"context"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+	"github.com/CyCoreSystems/ari/v6/rid"
+)
+
+// Outcome describes the result of a transfer
+type Outcome int
+
+const (
+	// InProgress indicates that the transfer has not yet completed
+	InProgress Outcome = iota
+
+	// Completed indicates that the transferred party was connected with the target
+	Completed
+
+	// Cancelled indicates that the transfer was cancelled
+	Cancelled
+
+	// TargetUnavailable indicates that the target did not answer.  The dial
+	// result describes why.
+	TargetUnavailable
+
+	// TargetHungUp indicates that the target hung up before the transfer was completed
+	TargetHungUp
+
+	// TransfereeHungUp indicates that the transferred party hung up before the transfer was completed
+	TransfereeHungUp
+
+	// Failed indicates that the transfer failed.  The error describes why.
+	Failed
+)
+
+// String implements fmt.Stringer
+func (o Outcome) String() string {
+	switch o {
+	case InProgress:
+		return "in progress"
+	case Completed:
+		return "completed"
+	case Cancelled:
+		return "cancelled"
+	case TargetUnavailable:
+		return "target unavailable"
+	case TargetHungUp:
+		return "target hung up"
+	case TransfereeHungUp:
+		return "transferee hung up"
+	case Failed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+// Result describes the result of a transfer
+type Result struct {
+	// Outcome is the outcome of the transfer
+	Outcome Outcome
+
+	// Dial is the result of the dial of the target, if it was dialed
+	Dial *dial.Result
+
+	// Target is the channel of the target, if it was dialed
+	Target *ari.ChannelHandle
+
+	// Bridge is the bridge in which the transferred party and the target were
+	// joined, if the transfer completed
+	Bridge *ari.BridgeHandle
+}
+
+// Blind transfers the given channel to the target endpoint (tech/resource).
+// While the target is dialed, the channel hears ringing (or music on hold).
+// If the target answers, the channel is removed from its bridge (see From)
+// and joined with the target in a new bridge.  Otherwise, the channel is left
+// where it was.
+//
+// Cancelling the context cancels the transfer and hangs up the target.
+func Blind(ctx context.Context, client ari.Client, channel *ari.ChannelHandle, target string, opts ...OptionFunc) (*Result, error) {
+	o := defaultOptions()
+	o.Apply(opts...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	res := new(Result)
+
+	// Watch for the transferee to hang up while the target is dialed
+	sub := channel.Subscribe(ari.Events.ChannelHangupRequest, ari.Events.StasisEnd)
+	defer sub.Cancel()
+
+	if err := startWaiting(channel, o); err != nil {
+		res.Outcome = Failed
+		return res, eris.Wrap(err, "failed to indicate ringing to channel")
+	}
+
+	s, err := dial.Dial(ctx, client, target, append([]dial.OptionFunc{dial.Originator(channel.Key())}, o.dialOptions...)...)
+	if err != nil {
+		stopWaiting(channel, o)
+
+		res.Outcome = Failed
+
+		return res, eris.Wrap(err, "failed to dial transfer target")
+	}
+
+	res.Target = s.Handle()
+
+	var transfereeGone bool
+
+	select {
+	case <-s.Done():
+	case <-sub.Events():
+		transfereeGone = true
+
+		s.Cancel()
+	}
+
+	res.Dial, err = s.Result()
+
+	stopWaiting(channel, o)
+
+	switch {
+	case transfereeGone:
+		res.Outcome = TransfereeHungUp
+		hangup(client, res.Target, res.Dial)
+
+		return res, nil
+	case res.Dial.Outcome == dial.Cancelled:
+		res.Outcome = Cancelled
+		return res, nil
+	case res.Dial.Outcome != dial.Answered:
+		res.Outcome = TargetUnavailable
+		return res, err
+	}
+
+	if o.from != nil {
+		if err := o.from.RemoveChannel(channel.ID()); err != nil {
+			res.Outcome = Failed
+			hangup(client, res.Target, res.Dial)
+
+			return res, eris.Wrap(err, "failed to remove channel from its bridge")
+		}
+	}
+
+	res.Bridge, err = joinBridge(client, channel.Key(), channel.ID(), res.Target.ID())
+	if err != nil {
+		// Put the channel back where it was
+		if o.from != nil {
+			_ = o.from.AddChannel(channel.ID()) // nolint
+		}
+
+		res.Outcome = Failed
+		hangup(client, res.Target, res.Dial)
+
+		return res, err
+	}
+
+	res.Outcome = Completed
+
+	return res, nil
+}
+
+// hangup hangs up the target if it answered, since it has nobody to talk to
+func hangup(client ari.Client, target *ari.ChannelHandle, r *dial.Result) {
+	if r != nil && r.Outcome == dial.Answered {
+		_ = client.Channel().Hangup(target.Key(), string(ari.HangupNormal)) // nolint
+	}
+}
+
+// joinBridge creates a new mixing bridge, on the node of the given reference
+// key, and adds the given channels to it.  If a channel cannot be added, the
+// bridge is deleted.
+func joinBridge(client ari.Client, ref *ari.Key, channelIDs ...string) (*ari.BridgeHandle, error) {
+	key := ref.New(ari.BridgeKey, rid.New(rid.Bridge))
+
+	br, err := client.Bridge().Create(key, "mixing", key.ID)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create bridge")
+	}
+
+	for _, id := range channelIDs {
+		if err := br.AddChannel(id); err != nil {
+			_ = br.Delete() // nolint
+
+			return nil, eris.Wrapf(err, "failed to add channel %s to bridge", id)
+		}
+	}
+
+	return br, nil
+}
+
+// startWaiting indicates ringing, or plays music on hold, to the waiting channel
+func startWaiting(ch *ari.ChannelHandle, o *Options) error {
+	if o.moh {
+		return ch.MOH(o.mohClass)
+	}
+
+	return ch.Ring()
+}
+
+// stopWaiting stops the ringing or music on hold of the waiting channel.  The
+// channel may already be gone, so errors are ignored.
+func stopWaiting(ch *ari.ChannelHandle, o *Options) {
+	if o.moh {
+		_ = ch.StopMOH() // nolint
+		return
+	}
+
+	_ = ch.StopRing() // nolint
+}
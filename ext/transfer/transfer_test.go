@@ -0,0 +1,300 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CyCoreSystems/ari/v6"
+	"github.com/CyCoreSystems/ari/v6/client/arimocks"
+	"github.com/CyCoreSystems/ari/v6/ext/dial"
+)
+
+type transferTest struct {
+	client  *arimocks.Client
+	channel *arimocks.Channel
+	bridge  *arimocks.Bridge
+
+	// targetEvents receives the events of the dial of the target
+	targetEvents chan ari.Event
+
+	// hangups receives the hangup events of each channel, by ID
+	hangups map[string]chan ari.Event
+
+	// newBridge is the key of the bridge created by the transfer
+	newBridge chan *ari.Key
+
+	// failAdd is the ID of a channel which cannot be added to a new bridge
+	failAdd string
+
+	targetKey *ari.Key
+	origKey   *ari.Key
+}
+
+func newTransferTest() *transferTest {
+	tt := &transferTest{
+		client:       &arimocks.Client{},
+		channel:      &arimocks.Channel{},
+		bridge:       &arimocks.Bridge{},
+		targetEvents: make(chan ari.Event, 10),
+		hangups:      make(map[string]chan ari.Event),
+		newBridge:    make(chan *ari.Key, 1),
+		targetKey:    ari.NewKey(ari.ChannelKey, "target"),
+		origKey:      ari.NewKey(ari.BridgeKey, "orig"),
+	}
+
+	tt.client.On("ApplicationName").Return("myapp")
+	tt.client.On("Channel").Return(tt.channel)
+	tt.client.On("Bridge").Return(tt.bridge)
+
+	tt.channel.On("StageOriginate", mock.Anything, mock.Anything).Return(
+		ari.NewChannelHandle(tt.targetKey, tt.channel, func(_ *ari.ChannelHandle) error { return nil }), nil)
+	tt.channel.On("Subscribe", tt.targetKey,
+		ari.Events.Dial, ari.Events.ChannelStateChange, ari.Events.StasisStart, ari.Events.ChannelDestroyed,
+	).Return(subscription(tt.targetEvents))
+
+	for _, id := range []string{"target", "transferor", "transferee"} {
+		events := make(chan ari.Event, 1)
+		tt.hangups[id] = events
+		tt.channel.On("Subscribe", ari.NewKey(ari.ChannelKey, id), ari.Events.ChannelHangupRequest, ari.Events.StasisEnd).
+			Return(subscription(events))
+	}
+
+	for _, m := range []string{"Ring", "StopRing", "StopMOH", "Answer"} {
+		tt.channel.On(m, mock.Anything).Return(nil)
+	}
+
+	tt.channel.On("MOH", mock.Anything, mock.Anything).Return(nil)
+	tt.channel.On("Hangup", mock.Anything, mock.Anything).Return(nil)
+
+	tt.bridge.On("Create", mock.Anything, "mixing", mock.Anything).Return(
+		func(key *ari.Key, _, _ string) (*ari.BridgeHandle, error) {
+			tt.newBridge <- key
+			return ari.NewBridgeHandle(key, tt.bridge, nil), nil
+		})
+	tt.bridge.On("AddChannel", mock.Anything, mock.Anything).Return(
+		func(key *ari.Key, id string) error {
+			if key.ID != tt.origKey.ID && id == tt.failAdd {
+				return errors.New("channel in use")
+			}
+
+			return nil
+		})
+	tt.bridge.On("RemoveChannel", mock.Anything, mock.Anything).Return(nil)
+	tt.bridge.On("Delete", mock.Anything).Return(nil)
+
+	return tt
+}
+
+func subscription(events chan ari.Event) *arimocks.Subscription {
+	sub := &arimocks.Subscription{}
+	sub.On("Events").Return((<-chan ari.Event)(events))
+	sub.On("Cancel").Return()
+
+	return sub
+}
+
+func (tt *transferTest) handle(id string) *ari.ChannelHandle {
+	return ari.NewChannelHandle(ari.NewKey(ari.ChannelKey, id), tt.channel, nil)
+}
+
+func (tt *transferTest) orig() *ari.BridgeHandle {
+	return ari.NewBridgeHandle(tt.origKey, tt.bridge, nil)
+}
+
+func TestBlind(t *testing.T) {
+	tt := newTransferTest()
+
+	tt.targetEvents <- &ari.StasisStart{Channel: ari.ChannelData{ID: "target", State: "Up"}}
+
+	res, err := Blind(context.Background(), tt.client, tt.handle("transferee"), "PJSIP/bob", From(tt.orig()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Outcome != Completed || res.Bridge == nil || res.Target.ID() != "target" {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	tt.channel.AssertCalled(t, "Ring", ari.NewKey(ari.ChannelKey, "transferee"))
+	tt.channel.AssertCalled(t, "StopRing", ari.NewKey(ari.ChannelKey, "transferee"))
+	tt.bridge.AssertCalled(t, "RemoveChannel", tt.origKey, "transferee")
+	tt.bridge.AssertCalled(t, "AddChannel", res.Bridge.Key(), "transferee")
+	tt.bridge.AssertCalled(t, "AddChannel", res.Bridge.Key(), "target")
+}
+
+func TestBlindJoinFails(t *testing.T) {
+	tt := newTransferTest()
+	tt.failAdd = "target"
+
+	tt.targetEvents <- &ari.StasisStart{Channel: ari.ChannelData{ID: "target", State: "Up"}}
+
+	res, err := Blind(context.Background(), tt.client, tt.handle("transferee"), "PJSIP/bob", From(tt.orig()))
+	if err == nil {
+		t.Fatal("expected error joining the target")
+	}
+
+	if res.Outcome != Failed {
+		t.Errorf("expected failed, got %s", res.Outcome)
+	}
+
+	tt.bridge.AssertCalled(t, "RemoveChannel", tt.origKey, "transferee")
+	tt.bridge.AssertCalled(t, "AddChannel", tt.origKey, "transferee")
+	tt.channel.AssertCalled(t, "Hangup", tt.targetKey, string(ari.HangupNormal))
+}
+
+func TestBlindBusy(t *testing.T) {
+	tt := newTransferTest()
+
+	tt.targetEvents <- &ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "target"}, Cause: int(ari.CauseUserBusy)}
+
+	res, err := Blind(context.Background(), tt.client, tt.handle("transferee"), "PJSIP/bob", From(tt.orig()), MusicOnHold("jazz"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Outcome != TargetUnavailable || res.Dial == nil || res.Dial.Cause != ari.CauseUserBusy {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	tt.channel.AssertCalled(t, "MOH", ari.NewKey(ari.ChannelKey, "transferee"), "jazz")
+	tt.bridge.AssertNotCalled(t, "RemoveChannel", tt.origKey, "transferee")
+	tt.bridge.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (tt *transferTest) attended(t *testing.T) *Consultation {
+	t.Helper()
+
+	tt.targetEvents <- &ari.StasisStart{Channel: ari.ChannelData{ID: "target", State: "Up"}}
+
+	c, err := Attended(context.Background(), tt.client, tt.orig(), tt.handle("transferor"), tt.handle("transferee"), "PJSIP/bob", HoldClass("jazz"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r := c.Result(); r.Outcome != InProgress || !c.WithTarget() {
+		t.Fatalf("expected consultation in progress with target, got %s", r.Outcome)
+	}
+
+	tt.bridge.AssertCalled(t, "RemoveChannel", tt.origKey, "transferor")
+	tt.channel.AssertCalled(t, "MOH", ari.NewKey(ari.ChannelKey, "transferee"), "jazz")
+
+	return c
+}
+
+func TestAttendedComplete(t *testing.T) {
+	tt := newTransferTest()
+	c := tt.attended(t)
+	consult := <-tt.newBridge
+
+	tt.bridge.AssertCalled(t, "AddChannel", consult, "transferor")
+	tt.bridge.AssertCalled(t, "AddChannel", consult, "target")
+
+	if err := c.Complete(); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+
+	if r := c.Result(); r.Outcome != Completed || r.Bridge.ID() != "orig" {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	tt.bridge.AssertCalled(t, "Delete", consult)
+	tt.bridge.AssertCalled(t, "AddChannel", tt.origKey, "target")
+
+	if err := c.Cancel(); err != ErrNotInProgress {
+		t.Errorf("expected ErrNotInProgress, got %v", err)
+	}
+}
+
+func TestAttendedSwapCancel(t *testing.T) {
+	tt := newTransferTest()
+	c := tt.attended(t)
+	consult := <-tt.newBridge
+
+	if err := c.Swap(); err != nil {
+		t.Fatalf("failed to swap: %v", err)
+	}
+
+	if c.WithTarget() {
+		t.Errorf("expected transferor to be with transferee")
+	}
+
+	tt.bridge.AssertCalled(t, "RemoveChannel", consult, "transferor")
+	tt.bridge.AssertCalled(t, "AddChannel", tt.origKey, "transferor")
+	tt.channel.AssertCalled(t, "MOH", tt.targetKey, "jazz")
+
+	if err := c.Cancel(); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+
+	if r := c.Result(); r.Outcome != Cancelled {
+		t.Errorf("expected cancelled, got %s", r.Outcome)
+	}
+
+	tt.channel.AssertCalled(t, "Hangup", tt.targetKey, string(ari.HangupNormal))
+	tt.bridge.AssertCalled(t, "Delete", consult)
+}
+
+func TestAttendedTargetHangup(t *testing.T) {
+	tt := newTransferTest()
+	c := tt.attended(t)
+	consult := <-tt.newBridge
+
+	tt.hangups["target"] <- &ari.ChannelHangupRequest{Channel: ari.ChannelData{ID: "target"}}
+
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for consultation to end")
+	}
+
+	if r := c.Result(); r.Outcome != TargetHungUp {
+		t.Errorf("expected target hung up, got %s", r.Outcome)
+	}
+
+	tt.bridge.AssertCalled(t, "Delete", consult)
+	tt.bridge.AssertCalled(t, "AddChannel", tt.origKey, "transferor")
+	tt.channel.AssertCalled(t, "StopMOH", ari.NewKey(ari.ChannelKey, "transferee"))
+}
+
+func TestAttendedTransfereeHangupWhileDialing(t *testing.T) {
+	tt := newTransferTest()
+
+	tt.hangups["transferee"] <- &ari.ChannelHangupRequest{Channel: ari.ChannelData{ID: "transferee"}}
+
+	c, err := Attended(context.Background(), tt.client, tt.orig(), tt.handle("transferor"), tt.handle("transferee"), "PJSIP/bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r := c.Result(); r.Outcome != TransfereeHungUp || r.Dial.Outcome != dial.Cancelled {
+		t.Errorf("expected transferee hung up with the dial cancelled, got %+v", r)
+	}
+
+	tt.channel.AssertCalled(t, "Hangup", tt.targetKey, string(ari.HangupNormal))
+	tt.bridge.AssertCalled(t, "AddChannel", tt.origKey, "transferor")
+}
+
+func TestAttendedUnavailable(t *testing.T) {
+	tt := newTransferTest()
+
+	tt.targetEvents <- &ari.ChannelDestroyed{Channel: ari.ChannelData{ID: "target"}, Cause: int(ari.CauseNoAnswer)}
+
+	c, err := Attended(context.Background(), tt.client, tt.orig(), tt.handle("transferor"), tt.handle("transferee"), "PJSIP/bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r := c.Result(); r.Outcome != TargetUnavailable {
+		t.Errorf("expected target unavailable, got %s", r.Outcome)
+	}
+
+	tt.bridge.AssertCalled(t, "AddChannel", tt.origKey, "transferor")
+
+	if err := c.Complete(); err != ErrNotInProgress {
+		t.Errorf("expected ErrNotInProgress, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package tts
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+// Placeholder is a stand-in Renderer for tests and development, which
+// renders silence, in proportion to the length of the text, as 8kHz signed
+// linear audio
+type Placeholder struct {
+	// PerCharacter is the duration of silence rendered for each character of
+	// the text.  If zero, 50ms is used.
+	PerCharacter time.Duration
+
+	mu      sync.Mutex
+	renders int
+}
+
+// Renders returns the number of texts which have been rendered
+func (p *Placeholder) Renders() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.renders
+}
+
+// Render implements Renderer
+func (p *Placeholder) Render(ctx context.Context, text string, _ play.Voice) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	per := p.PerCharacter
+	if per == 0 {
+		per = 50 * time.Millisecond
+	}
+
+	p.mu.Lock()
+	p.renders++
+	p.mu.Unlock()
+
+	dur := per * time.Duration(len([]rune(text)))
+
+	// 8000 samples per second, two bytes per sample
+	return make([]byte, 2*int(dur*8000/time.Second)), nil
+}
+
+// Format implements Renderer
+func (p *Placeholder) Format() string {
+	return "sln"
+}
@@ -0,0 +1,212 @@
+// Package tts provides text-to-speech for ext/play:  a cache, which stores
+// rendered speech by the hash of its content so that each text is rendered
+// only once, and a placeholder renderer for tests.
+package tts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+// Renderer synthesizes speech from text
+type Renderer interface {
+	// Render returns the audio of the text, spoken by the given voice, in the format of the renderer
+	Render(ctx context.Context, text string, voice play.Voice) ([]byte, error)
+
+	// Format returns the format of the rendered audio, as the file extension
+	// by which Asterisk recognizes it (ex. "wav", "sln16", "ulaw")
+	Format() string
+}
+
+// Cache is a text-to-speech provider (see play.TTS) which stores the audio
+// rendered by a Renderer in a local directory, named by the hash of its
+// content (text, voice, and format).  The directory must be readable by
+// Asterisk.
+type Cache struct {
+	r   Renderer
+	dir string
+
+	// recordings indicates that the audio is served as stored recordings,
+	// under the given prefix
+	recordings bool
+	prefix     string
+
+	mu sync.Mutex
+
+	// rendering tracks the renders in progress, by name, so that concurrent
+	// requests for the same text are rendered only once
+	rendering map[string]*render
+}
+
+type render struct {
+	done chan struct{}
+	err  error
+
+	// abandoned indicates that the render failed because the context of the
+	// request which started it was done.  Those waiting on it render the
+	// text again, under their own contexts.
+	abandoned bool
+}
+
+// OptionFunc is a function which applies changes to a Cache
+type OptionFunc func(*Cache)
+
+// AsRecordings serves the cached audio as stored recordings ("recording:"
+// URIs), rather than as sounds.  The directory of the cache must then be
+// Asterisk's recording directory (ex. /var/spool/asterisk/recording) or a
+// subdirectory of it, given by prefix.
+func AsRecordings(prefix string) OptionFunc {
+	return func(c *Cache) {
+		c.recordings = true
+		c.prefix = prefix
+	}
+}
+
+// NewCache returns a cache of the audio rendered by the given renderer, stored in the given directory
+func NewCache(r Renderer, dir string, opts ...OptionFunc) (*Cache, error) {
+	c := &Cache{
+		r:         r,
+		dir:       dir,
+		rendering: make(map[string]*render),
+	}
+
+	for _, f := range opts {
+		f(c)
+	}
+
+	c.dir = filepath.Join(c.dir, c.prefix)
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return nil, eris.Wrap(err, "failed to create cache directory")
+	}
+
+	return c, nil
+}
+
+// Name returns the name under which the given text, spoken by the given
+// voice, is cached.  It is the hash of the content.
+func (c *Cache) Name(text string, voice play.Voice) string {
+	h := sha256.New()
+
+	for _, s := range []string{c.r.Format(), voice.Name, voice.Language, text} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// URI implements play.TTS.  The text is rendered, unless it has already
+// been cached, and the URI of the cached audio is returned.
+func (c *Cache) URI(ctx context.Context, text string, voice play.Voice) (string, error) {
+	name := c.Name(text, voice)
+
+	if err := c.ensure(ctx, name, text, voice); err != nil {
+		return "", err
+	}
+
+	if c.recordings {
+		return "recording:" + path.Join(c.prefix, name), nil
+	}
+
+	return "sound:" + filepath.Join(c.dir, name), nil
+}
+
+// Path returns the path of the cached audio file for the given name
+func (c *Cache) Path(name string) string {
+	return filepath.Join(c.dir, name+"."+c.r.Format())
+}
+
+// ensure renders the text into the cache, unless it is already present
+func (c *Cache) ensure(ctx context.Context, name, text string, voice play.Voice) error {
+	for {
+		c.mu.Lock()
+
+		if r, ok := c.rendering[name]; ok {
+			c.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-r.done:
+			}
+
+			if r.abandoned {
+				continue
+			}
+
+			return r.err
+		}
+
+		if _, err := os.Stat(c.Path(name)); err == nil {
+			c.mu.Unlock()
+			return nil
+		}
+
+		r := &render{done: make(chan struct{})}
+		c.rendering[name] = r
+
+		c.mu.Unlock()
+
+		r.err = c.render(ctx, name, text, voice)
+		r.abandoned = r.err != nil && ctx.Err() != nil
+
+		c.mu.Lock()
+		delete(c.rendering, name)
+		c.mu.Unlock()
+
+		close(r.done)
+
+		return r.err
+	}
+}
+
+// render renders the text and stores it in the cache.  The audio is written
+// to a temporary file first, so that Asterisk never sees a partial file.
+func (c *Cache) render(ctx context.Context, name, text string, voice play.Voice) error {
+	data, err := c.r.Render(ctx, text, voice)
+	if err != nil {
+		return eris.Wrap(err, "failed to render text")
+	}
+
+	if len(data) == 0 {
+		return errors.New("renderer returned no audio")
+	}
+
+	f, err := os.CreateTemp(c.dir, ".render-*")
+	if err != nil {
+		return eris.Wrap(err, "failed to create cache file")
+	}
+
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		// Asterisk runs as another user, so the file must be world-readable
+		err = os.Chmod(f.Name(), 0o644)
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), c.Path(name))
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name()) // nolint
+
+		return eris.Wrap(err, "failed to write cache file")
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CyCoreSystems/ari/v6/ext/play"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	r := &Placeholder{PerCharacter: 10 * time.Millisecond}
+
+	c, err := NewCache(r, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	voice := play.Voice{Language: "en-US"}
+
+	uri, err := c.URI(context.Background(), "hello", voice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name := c.Name("hello", voice)
+	if uri != "sound:"+dir+"/"+name {
+		t.Errorf("unexpected URI %s", uri)
+	}
+
+	info, err := os.Stat(c.Path(name))
+	if err != nil {
+		t.Fatalf("cache file is missing: %v", err)
+	}
+
+	// 50ms of 8kHz, 16-bit audio
+	if info.Size() != 800 {
+		t.Errorf("unexpected cache file size %d", info.Size())
+	}
+
+	if again, _ := c.URI(context.Background(), "hello", voice); again != uri || r.Renders() != 1 {
+		t.Errorf("expected cached URI %s with one render, got %s with %d renders", uri, again, r.Renders())
+	}
+
+	if other := c.Name("hello", play.Voice{Language: "es-ES"}); other == name {
+		t.Error("expected distinct names for distinct voices")
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	r := new(Placeholder)
+
+	c, err := NewCache(r, t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.URI(context.Background(), "your balance is", play.Voice{}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := r.Renders(); n != 1 {
+		t.Errorf("expected one render, got %d", n)
+	}
+}
+
+// gatedRenderer renders once it is released, or fails when its context is done
+type gatedRenderer struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *gatedRenderer) Render(ctx context.Context, _ string, _ play.Voice) ([]byte, error) {
+	r.started <- struct{}{}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.release:
+		return []byte{0, 0}, nil
+	}
+}
+
+func (r *gatedRenderer) Format() string {
+	return "sln"
+}
+
+func TestCacheCancelledRender(t *testing.T) {
+	r := &gatedRenderer{started: make(chan struct{}, 2), release: make(chan struct{})}
+
+	c, err := NewCache(r, t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.URI(ctx, "please hold", play.Voice{})
+		first <- err
+	}()
+
+	<-r.started
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := c.URI(context.Background(), "please hold", play.Voice{})
+		second <- err
+	}()
+
+	// Let the second request wait on the first render
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled request to fail, got %v", err)
+	}
+
+	select {
+	case <-r.started:
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiting request to render the text again")
+	}
+
+	close(r.release)
+
+	if err := <-second; err != nil {
+		t.Errorf("expected the waiting request to succeed, got %v", err)
+	}
+}
+
+func TestCacheRecordings(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := NewCache(new(Placeholder), dir, AsRecordings("tts"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	uri, err := c.URI(context.Background(), "goodbye", play.Voice{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(uri, "recording:tts/") {
+		t.Errorf("unexpected URI %s", uri)
+	}
+
+	if _, err := os.Stat(dir + "/tts/" + strings.TrimPrefix(uri, "recording:tts/") + ".sln"); err != nil {
+		t.Errorf("cache file is missing: %v", err)
+	}
+}
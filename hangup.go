@@ -0,0 +1,330 @@
+package ari
+
+// HangupReason is a reason which may be given to Asterisk when hanging up a
+// channel.  Asterisk translates the reason into a Q.850 cause code (see Cause).
+type HangupReason string
+
+// Hangup reasons.  Channel.Hangup takes a plain string, so convert them when
+// calling it (ex. string(HangupBusy)), or use ChannelHandle.HangupWithReason.
+const (
+	// HangupNormal hangs up the channel with normal clearing
+	HangupNormal HangupReason = "normal"
+
+	// HangupBusy hangs up the channel as busy
+	HangupBusy HangupReason = "busy"
+
+	// HangupCongestion hangs up the channel due to congestion
+	HangupCongestion HangupReason = "congestion"
+
+	// HangupNoAnswer hangs up the channel as having not been answered
+	HangupNoAnswer HangupReason = "no_answer"
+
+	// HangupTimeout hangs up the channel as having not responded
+	HangupTimeout HangupReason = "timeout"
+
+	// HangupRejected hangs up the channel as rejected
+	HangupRejected HangupReason = "rejected"
+
+	// HangupUnallocated hangs up the channel as an unallocated number
+	HangupUnallocated HangupReason = "unallocated"
+
+	// HangupNormalUnspecified hangs up the channel as normal, unspecified
+	HangupNormalUnspecified HangupReason = "normal_unspecified"
+
+	// HangupNumberIncomplete hangs up the channel as having an invalid number format
+	HangupNumberIncomplete HangupReason = "number_incomplete"
+
+	// HangupCodecMismatch hangs up the channel as having no compatible codec
+	HangupCodecMismatch HangupReason = "codec_mismatch"
+
+	// HangupInterworking hangs up the channel due to an unspecified interworking problem
+	HangupInterworking HangupReason = "interworking"
+
+	// HangupFailure hangs up the channel due to a failure
+	HangupFailure HangupReason = "failure"
+
+	// HangupAnsweredElsewhere hangs up the channel as having been answered elsewhere
+	HangupAnsweredElsewhere HangupReason = "answered_elsewhere"
+)
+
+// hangupReasonCauses maps each hangup reason to the cause code which Asterisk applies for it
+var hangupReasonCauses = map[HangupReason]Cause{
+	HangupNormal:            CauseNormalClearing,
+	HangupBusy:              CauseUserBusy,
+	HangupCongestion:        CauseNormalCircuitCongestion,
+	HangupNoAnswer:          CauseNoAnswer,
+	HangupTimeout:           CauseNoUserResponse,
+	HangupRejected:          CauseCallRejected,
+	HangupUnallocated:       CauseUnallocated,
+	HangupNormalUnspecified: CauseNormalUnspecified,
+	HangupNumberIncomplete:  CauseInvalidNumberFormat,
+	HangupCodecMismatch:     CauseBearerCapabilityNotAvailable,
+	HangupInterworking:      CauseInterworking,
+	HangupFailure:           CauseFailure,
+	HangupAnsweredElsewhere: CauseAnsweredElsewhere,
+}
+
+// Valid indicates whether the reason is one which Asterisk accepts
+func (r HangupReason) Valid() bool {
+	_, ok := hangupReasonCauses[r]
+	return ok
+}
+
+// Cause returns the cause code which Asterisk applies for the hangup reason,
+// or CauseNotDefined if the reason is not known
+func (r HangupReason) Cause() Cause {
+	return hangupReasonCauses[r]
+}
+
+// Cause is a Q.850 hangup cause code, as reported by Asterisk in the Cause
+// field of ChannelDestroyed and ChannelHangupRequest events
+type Cause int
+
+// The Q.850 cause codes used by Asterisk
+const (
+	CauseNotDefined                   Cause = 0
+	CauseUnallocated                  Cause = 1
+	CauseNoRouteTransitNet            Cause = 2
+	CauseNoRouteDestination           Cause = 3
+	CauseMisdialledTrunkPrefix        Cause = 5
+	CauseChannelUnacceptable          Cause = 6
+	CauseCallAwardedDelivered         Cause = 7
+	CausePreEmpted                    Cause = 8
+	CauseNumberPortedNotHere          Cause = 14
+	CauseNormalClearing               Cause = 16
+	CauseUserBusy                     Cause = 17
+	CauseNoUserResponse               Cause = 18
+	CauseNoAnswer                     Cause = 19
+	CauseSubscriberAbsent             Cause = 20
+	CauseCallRejected                 Cause = 21
+	CauseNumberChanged                Cause = 22
+	CauseRedirectedToNewDestination   Cause = 23
+	CauseAnsweredElsewhere            Cause = 26
+	CauseDestinationOutOfOrder        Cause = 27
+	CauseInvalidNumberFormat          Cause = 28
+	CauseFacilityRejected             Cause = 29
+	CauseResponseToStatusEnquiry      Cause = 30
+	CauseNormalUnspecified            Cause = 31
+	CauseNormalCircuitCongestion      Cause = 34
+	CauseNetworkOutOfOrder            Cause = 38
+	CauseNormalTemporaryFailure       Cause = 41
+	CauseSwitchCongestion             Cause = 42
+	CauseAccessInfoDiscarded          Cause = 43
+	CauseRequestedChanUnavail         Cause = 44
+	CauseFacilityNotSubscribed        Cause = 50
+	CauseOutgoingCallBarred           Cause = 52
+	CauseIncomingCallBarred           Cause = 54
+	CauseBearerCapabilityNotAuth      Cause = 57
+	CauseBearerCapabilityNotAvailable Cause = 58
+	CauseBearerCapabilityNotImpl      Cause = 65
+	CauseChanNotImplemented           Cause = 66
+	CauseFacilityNotImplemented       Cause = 69
+	CauseInvalidCallReference         Cause = 81
+	CauseIncompatibleDestination      Cause = 88
+	CauseInvalidMsgUnspecified        Cause = 95
+	CauseMandatoryIEMissing           Cause = 96
+	CauseMessageTypeNonexist          Cause = 97
+	CauseWrongMessage                 Cause = 98
+	CauseIENonexist                   Cause = 99
+	CauseInvalidIEContents            Cause = 100
+	CauseWrongCallState               Cause = 101
+	CauseRecoveryOnTimerExpire        Cause = 102
+	CauseMandatoryIELengthError       Cause = 103
+	CauseProtocolError                Cause = 111
+	CauseInterworking                 Cause = 127
+)
+
+// Aliases of cause codes, as used by Asterisk
+const (
+	CauseBusy         = CauseUserBusy
+	CauseFailure      = CauseNetworkOutOfOrder
+	CauseNormal       = CauseNormalClearing
+	CauseCongestion   = CauseNormalCircuitCongestion
+	CauseUnregistered = CauseSubscriberAbsent
+)
+
+// causeInfo describes a cause code
+type causeInfo struct {
+	// name is the name of the cause, as used by Asterisk's HANGUPCAUSE and HANGUP_CAUSE
+	name string
+
+	// text is the description of the cause, as sent in the cause_txt of events
+	text string
+}
+
+var causes = map[Cause]causeInfo{
+	CauseNotDefined:                   {"NOTDEFINED", "Not defined"},
+	CauseUnallocated:                  {"UNALLOCATED", "Unallocated (unassigned) number"},
+	CauseNoRouteTransitNet:            {"NO_ROUTE_TRANSIT_NET", "No route to specified transmit network"},
+	CauseNoRouteDestination:           {"NO_ROUTE_DESTINATION", "No route to destination"},
+	CauseMisdialledTrunkPrefix:        {"MISDIALLED_TRUNK_PREFIX", "Misdialed trunk prefix"},
+	CauseChannelUnacceptable:          {"CHANNEL_UNACCEPTABLE", "Channel unacceptable"},
+	CauseCallAwardedDelivered:         {"CALL_AWARDED_DELIVERED", "Call awarded and being delivered in an established channel"},
+	CausePreEmpted:                    {"PRE_EMPTED", "Pre-empted"},
+	CauseNumberPortedNotHere:          {"NUMBER_PORTED_NOT_HERE", "Number ported elsewhere"},
+	CauseNormalClearing:               {"NORMAL_CLEARING", "Normal Clearing"},
+	CauseUserBusy:                     {"USER_BUSY", "User busy"},
+	CauseNoUserResponse:               {"NO_USER_RESPONSE", "No user responding"},
+	CauseNoAnswer:                     {"NO_ANSWER", "User alerting, no answer"},
+	CauseSubscriberAbsent:             {"SUBSCRIBER_ABSENT", "Subscriber absent"},
+	CauseCallRejected:                 {"CALL_REJECTED", "Call Rejected"},
+	CauseNumberChanged:                {"NUMBER_CHANGED", "Number changed"},
+	CauseRedirectedToNewDestination:   {"REDIRECTED_TO_NEW_DESTINATION", "Redirected to new destination"},
+	CauseAnsweredElsewhere:            {"ANSWERED_ELSEWHERE", "Answered elsewhere"},
+	CauseDestinationOutOfOrder:        {"DESTINATION_OUT_OF_ORDER", "Destination out of order"},
+	CauseInvalidNumberFormat:          {"INVALID_NUMBER_FORMAT", "Invalid number format"},
+	CauseFacilityRejected:             {"FACILITY_REJECTED", "Facility rejected"},
+	CauseResponseToStatusEnquiry:      {"RESPONSE_TO_STATUS_ENQUIRY", "Response to STATus ENQuiry"},
+	CauseNormalUnspecified:            {"NORMAL_UNSPECIFIED", "Normal, unspecified"},
+	CauseNormalCircuitCongestion:      {"NORMAL_CIRCUIT_CONGESTION", "Circuit/channel congestion"},
+	CauseNetworkOutOfOrder:            {"NETWORK_OUT_OF_ORDER", "Network out of order"},
+	CauseNormalTemporaryFailure:       {"NORMAL_TEMPORARY_FAILURE", "Temporary failure"},
+	CauseSwitchCongestion:             {"SWITCH_CONGESTION", "Switching equipment congestion"},
+	CauseAccessInfoDiscarded:          {"ACCESS_INFO_DISCARDED", "Access information discarded"},
+	CauseRequestedChanUnavail:         {"REQUESTED_CHAN_UNAVAIL", "Requested channel not available"},
+	CauseFacilityNotSubscribed:        {"FACILITY_NOT_SUBSCRIBED", "Facility not subscribed"},
+	CauseOutgoingCallBarred:           {"OUTGOING_CALL_BARRED", "Outgoing call barred"},
+	CauseIncomingCallBarred:           {"INCOMING_CALL_BARRED", "Incoming call barred"},
+	CauseBearerCapabilityNotAuth:      {"BEARERCAPABILITY_NOTAUTH", "Bearer capability not authorized"},
+	CauseBearerCapabilityNotAvailable: {"BEARERCAPABILITY_NOTAVAIL", "Bearer capability not available"},
+	CauseBearerCapabilityNotImpl:      {"BEARERCAPABILITY_NOTIMPL", "Bearer capability not implemented"},
+	CauseChanNotImplemented:           {"CHAN_NOT_IMPLEMENTED", "Channel not implemented"},
+	CauseFacilityNotImplemented:       {"FACILITY_NOT_IMPLEMENTED", "Facility not implemented"},
+	CauseInvalidCallReference:         {"INVALID_CALL_REFERENCE", "Invalid call reference value"},
+	CauseIncompatibleDestination:      {"INCOMPATIBLE_DESTINATION", "Incompatible destination"},
+	CauseInvalidMsgUnspecified:        {"INVALID_MSG_UNSPECIFIED", "Invalid message unspecified"},
+	CauseMandatoryIEMissing:           {"MANDATORY_IE_MISSING", "Mandatory information element is missing"},
+	CauseMessageTypeNonexist:          {"MESSAGE_TYPE_NONEXIST", "Message type nonexist."},
+	CauseWrongMessage:                 {"WRONG_MESSAGE", "Wrong message"},
+	CauseIENonexist:                   {"IE_NONEXIST", "Info. element nonexist or not implemented"},
+	CauseInvalidIEContents:            {"INVALID_IE_CONTENTS", "Invalid information element contents"},
+	CauseWrongCallState:               {"WRONG_CALL_STATE", "Message not compatible with call state"},
+	CauseRecoveryOnTimerExpire:        {"RECOVERY_ON_TIMER_EXPIRE", "Recover on timer expiry"},
+	CauseMandatoryIELengthError:       {"MANDATORY_IE_LENGTH_ERROR", "Mandatory IE length error"},
+	CauseProtocolError:                {"PROTOCOL_ERROR", "Protocol error, unspecified"},
+	CauseInterworking:                 {"INTERWORKING", "Interworking, unspecified"},
+}
+
+// Name returns the Asterisk name of the cause (ex. "NORMAL_CLEARING"), or
+// the empty string if the cause is not known
+func (c Cause) Name() string {
+	return causes[c].name
+}
+
+// String returns the description of the cause, as Asterisk reports it
+func (c Cause) String() string {
+	if info, ok := causes[c]; ok {
+		return info.text
+	}
+
+	return "Unknown"
+}
+
+// IsNormal indicates whether the cause describes a normal call clearing
+func (c Cause) IsNormal() bool {
+	return c == CauseNormalClearing || c == CauseNormalUnspecified
+}
+
+// IsBusy indicates whether the cause describes a busy destination
+func (c Cause) IsBusy() bool {
+	return c == CauseUserBusy
+}
+
+// IsNoAnswer indicates whether the cause describes a destination which did not answer
+func (c Cause) IsNoAnswer() bool {
+	return c == CauseNoAnswer || c == CauseNoUserResponse
+}
+
+// IsCongestion indicates whether the cause describes a congested or unavailable route
+func (c Cause) IsCongestion() bool {
+	switch c {
+	case CauseNormalCircuitCongestion, CauseSwitchCongestion, CauseRequestedChanUnavail, CauseNormalTemporaryFailure:
+		return true
+	}
+
+	return false
+}
+
+// SIPStatus returns the SIP response status which Asterisk sends for the
+// cause, or 0 if it has no particular mapping
+func (c Cause) SIPStatus() int {
+	switch c {
+	case CauseUnallocated, CauseNoRouteDestination, CauseNoRouteTransitNet:
+		return 404
+	case CauseNormalCircuitCongestion, CauseSwitchCongestion, CauseChanNotImplemented:
+		return 503
+	case CauseNoUserResponse:
+		return 408
+	case CauseNoAnswer, CauseSubscriberAbsent, CauseNormalUnspecified:
+		return 480
+	case CauseCallRejected:
+		return 403
+	case CauseNumberChanged:
+		return 410
+	case CauseInvalidNumberFormat:
+		return 484
+	case CauseUserBusy:
+		return 486
+	case CauseNetworkOutOfOrder, CauseInterworking:
+		return 500
+	case CauseFacilityRejected:
+		return 501
+	case CauseDestinationOutOfOrder:
+		return 502
+	case CauseBearerCapabilityNotAvailable:
+		return 488
+	}
+
+	return 0
+}
+
+// CauseFromSIPStatus returns the cause which Asterisk applies for the given
+// SIP response status.  Statuses without a particular mapping are mapped
+// according to their class.
+func CauseFromSIPStatus(status int) Cause {
+	switch status {
+	case 401, 403, 407, 603:
+		return CauseCallRejected
+	case 404, 485, 604:
+		return CauseUnallocated
+	case 408:
+		return CauseNoUserResponse
+	case 409:
+		return CauseNormalTemporaryFailure
+	case 410:
+		return CauseNumberChanged
+	case 420:
+		return CauseNoRouteDestination
+	case 480, 483:
+		return CauseNoAnswer
+	case 484:
+		return CauseInvalidNumberFormat
+	case 486, 600:
+		return CauseUserBusy
+	case 488, 606:
+		return CauseBearerCapabilityNotAvailable
+	case 500:
+		return CauseFailure
+	case 501:
+		return CauseFacilityRejected
+	case 502:
+		return CauseDestinationOutOfOrder
+	case 503:
+		return CauseCongestion
+	case 504:
+		return CauseRecoveryOnTimerExpire
+	}
+
+	switch {
+	case status >= 200 && status < 300:
+		return CauseNormalClearing
+	case status >= 400 && status < 500:
+		return CauseInterworking
+	case status >= 500 && status < 600:
+		return CauseFailure
+	case status >= 600 && status < 700:
+		return CauseCallRejected
+	}
+
+	return CauseNotDefined
+}
@@ -0,0 +1,137 @@
+package ari
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHangupReasonCause(t *testing.T) {
+	tests := []struct {
+		reason HangupReason
+		cause  Cause
+	}{
+		{HangupNormal, CauseNormalClearing},
+		{HangupBusy, CauseUserBusy},
+		{HangupCongestion, CauseNormalCircuitCongestion},
+		{HangupNoAnswer, CauseNoAnswer},
+		{HangupTimeout, CauseNoUserResponse},
+		{HangupCodecMismatch, CauseBearerCapabilityNotAvailable},
+		{HangupFailure, CauseNetworkOutOfOrder},
+		{HangupAnsweredElsewhere, CauseAnsweredElsewhere},
+		{"bogus", CauseNotDefined},
+	}
+
+	for _, tt := range tests {
+		if c := tt.reason.Cause(); c != tt.cause {
+			t.Errorf("%s: expected cause %d, got %d", tt.reason, tt.cause, c)
+		}
+
+		if valid := tt.reason.Valid(); valid != (tt.cause != CauseNotDefined) {
+			t.Errorf("%s: unexpected validity %v", tt.reason, valid)
+		}
+	}
+}
+
+func TestCauseNames(t *testing.T) {
+	if n := CauseNormalClearing.Name(); n != "NORMAL_CLEARING" {
+		t.Errorf("unexpected name %q", n)
+	}
+
+	if s := CauseNormalClearing.String(); s != "Normal Clearing" {
+		t.Errorf("unexpected text %q", s)
+	}
+
+	if n := Cause(200).Name(); n != "" {
+		t.Errorf("expected no name for unknown cause, got %q", n)
+	}
+
+	if s := Cause(200).String(); s != "Unknown" {
+		t.Errorf("unexpected text for unknown cause %q", s)
+	}
+
+	for c, info := range causes {
+		if info.name == "" || info.text == "" {
+			t.Errorf("cause %d is missing a name or description", c)
+		}
+	}
+}
+
+func TestCauseSIPStatus(t *testing.T) {
+	tests := []struct {
+		cause  Cause
+		status int
+	}{
+		{CauseUnallocated, 404},
+		{CauseUserBusy, 486},
+		{CauseNoAnswer, 480},
+		{CauseNoUserResponse, 408},
+		{CauseCallRejected, 403},
+		{CauseCongestion, 503},
+		{CauseBearerCapabilityNotAvailable, 488},
+		{CauseNormalClearing, 0},
+	}
+
+	for _, tt := range tests {
+		if s := tt.cause.SIPStatus(); s != tt.status {
+			t.Errorf("%s: expected SIP status %d, got %d", tt.cause.Name(), tt.status, s)
+		}
+
+		if tt.status == 0 {
+			continue
+		}
+
+		// each SIP status which Asterisk sends should map back to a cause of the same status
+		if back := CauseFromSIPStatus(tt.status).SIPStatus(); back != tt.status {
+			t.Errorf("%d: round trip yielded SIP status %d", tt.status, back)
+		}
+	}
+}
+
+func TestCauseFromSIPStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		cause  Cause
+	}{
+		{486, CauseUserBusy},
+		{600, CauseUserBusy},
+		{603, CauseCallRejected},
+		{404, CauseUnallocated},
+		{487, CauseInterworking},
+		{599, CauseFailure},
+		{699, CauseCallRejected},
+		{200, CauseNormalClearing},
+		{100, CauseNotDefined},
+	}
+
+	for _, tt := range tests {
+		if c := CauseFromSIPStatus(tt.status); c != tt.cause {
+			t.Errorf("%d: expected cause %s, got %s", tt.status, tt.cause.Name(), c.Name())
+		}
+	}
+}
+
+func TestChannelDestroyedHangupCause(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "events", "ChannelDestroyed.json"))
+	if err != nil {
+		t.Fatalf("failed to read sample: %v", err)
+	}
+
+	e, err := DecodeEvent(golden)
+	if err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+
+	evt, ok := e.(*ChannelDestroyed)
+	if !ok {
+		t.Fatalf("unexpected event type %T", e)
+	}
+
+	if c := evt.HangupCause(); c != CauseNormalClearing || !c.IsNormal() {
+		t.Errorf("unexpected cause %d", c)
+	}
+
+	if c := evt.HangupCause(); c.String() != evt.CauseTxt {
+		t.Errorf("cause text %q does not match event %q", c.String(), evt.CauseTxt)
+	}
+}
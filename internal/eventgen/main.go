@@ -1,11 +1,17 @@
 package main
 
-// Generate event code from the events.json swagger
+// Generate event code from the events.json swagger.  Several versions of the
+// swagger may be given, oldest first, in which case they are merged.
 
 import (
 	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"text/template"
@@ -31,11 +37,90 @@ func init() {
 	typeMappings["object"] = "interface{}"
 }
 
+// legacyEvents lists the models which are not subtypes of Message or Event
+// but which have always been generated as events.  They are retained as
+// events for compatibility.
+var legacyEvents = map[string]bool{
+	"ContactInfo": true,
+	"Peer":        true,
+}
+
+// keyKind describes a resource type to which an event may refer
+type keyKind struct {
+	// Key is the key kind constant for the resource
+	Key string
+
+	// Getter is the name of the event method which returns the IDs of the resources of this kind
+	Getter string
+
+	// Noun is the name of the resource, as used in the doc comment of the Getter
+	Noun string
+
+	// ID is the selector suffix which yields the ID of the resource
+	ID string
+
+	// Present is the selector suffix which yields a value which is non-empty if the resource is present
+	Present string
+}
+
+// keyKinds lists the resource types to which events may refer, keyed by the
+// swagger type of the property.  The order is that in which the ID methods
+// are generated.
+var keyKinds = []struct {
+	Type string
+	Kind keyKind
+}{
+	{"Channel", keyKind{Key: "ChannelKey", Getter: "GetChannelIDs", Noun: "channel", ID: ".ID", Present: ".ID"}},
+	{"Bridge", keyKind{Key: "BridgeKey", Getter: "GetBridgeIDs", Noun: "bridge", ID: ".ID", Present: ".ID"}},
+	{"Playback", keyKind{Key: "PlaybackKey", Getter: "GetPlaybackIDs", Noun: "playback", ID: ".ID", Present: ".ID"}},
+	{"LiveRecording", keyKind{Key: "LiveRecordingKey", Getter: "GetRecordingIDs", Noun: "recording", ID: ".ID()", Present: ".Name"}},
+	{"Endpoint", keyKind{Key: "EndpointKey", Getter: "GetEndpointIDs", Noun: "endpoint", ID: ".ID()", Present: ".Resource"}},
+}
+
+// spec is the subset of the swagger event definition file used by the generator
+type spec struct {
+	Models map[string]*model `json:"models"`
+}
+
+type model struct {
+	Description string               `json:"description"`
+	Properties  map[string]*property `json:"properties"`
+	SubTypes    []string             `json:"subTypes"`
+}
+
+type property struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    *bool  `json:"required"`
+}
+
 type event struct {
 	Name        string
 	Event       string
 	Description string
 	Properties  propList
+
+	// Keys indicates that the Keys method should be generated
+	Keys bool
+
+	// Refs lists the references to resources held by the event
+	Refs []ref
+
+	// Getters lists the ID methods which should be generated
+	Getters []getter
+}
+
+// ref is a reference, by a property of an event, to a resource
+type ref struct {
+	Kind    keyKind
+	ID      string
+	Present string
+}
+
+// getter describes a generated ID method
+type getter struct {
+	Kind keyKind
+	Refs []ref
 }
 
 type prop struct {
@@ -80,13 +165,15 @@ func (el eventList) Swap(l int, r int) {
 }
 
 func main() {
-	if len(os.Args) < 3 {
-		log.Fatalf("Usage: %s <template> <specFile.json>\n", os.Args[0])
+	pkgDir := flag.String("pkg", ".", "directory of the package for which code is generated")
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		log.Fatalf("Usage: %s [-pkg <dir>] <template> <specFile.json> [<specFile.json>...]\n", os.Args[0])
 		return
 	}
 
-	templateFile := os.Args[1]
-	specFile := os.Args[2]
+	templateFile := flag.Arg(0)
 
 	// load template
 	tmpl, err := template.New("eventsTemplate").ParseFiles(templateFile)
@@ -94,105 +181,321 @@ func main() {
 		log.Fatalln("failed to parse template", err)
 	}
 
-	// load file
-	input, err := os.Open(specFile)
+	// load and merge the spec files, oldest first
+	models := make(map[string]*model)
+
+	for _, specFile := range flag.Args()[1:] {
+		s, err := loadSpec(specFile)
+		if err != nil {
+			log.Fatalln("failed to load event definition file", specFile, err)
+		}
+
+		mergeModels(models, s.Models)
+	}
+
+	if len(models) < 1 {
+		log.Fatalln("no models found")
+	}
+
+	methods, err := declaredMethods(*pkgDir)
 	if err != nil {
-		log.Fatalln("failed to open event definition file", err)
+		log.Fatalln("failed to read package methods", err)
 	}
 
-	defer input.Close()
+	isEvent := eventModels(models)
 
-	// parse data
-	data := make(map[string]any)
-	dec := json.NewDecoder(input)
+	// convert data
+
+	var events, plain eventList
+
+	for mkey, m := range models {
+		if mkey == "Message" || mkey == "Event" {
+			continue
+		}
+
+		e := event{
+			Name:        goName(mkey),
+			Event:       mkey,
+			Description: cleanDescription(m.Description),
+			Properties:  properties(m),
+		}
+
+		if !isEvent[mkey] {
+			plain = append(plain, e)
+			continue
+		}
+
+		e.Refs = refs(models, isEvent, m, "evt", 0)
+		e.Keys = !methods[e.Name]["Keys"]
+
+		for _, kk := range keyKinds {
+			if methods[e.Name][kk.Kind.Getter] {
+				continue
+			}
+
+			g := getter{Kind: kk.Kind}
+
+			for _, r := range e.Refs {
+				if r.Kind == kk.Kind {
+					g.Refs = append(g.Refs, r)
+				}
+			}
 
-	if err := dec.Decode(&data); err != nil {
-		log.Fatalln("failed to decode event definition file", err)
+			if len(g.Refs) > 0 {
+				e.Getters = append(e.Getters, g)
+			}
+		}
+
+		events = append(events, e)
 	}
 
-	// convert data
+	sort.Sort(events)
+	sort.Sort(plain)
+
+	data := struct {
+		Events eventList
+		Models eventList
+	}{
+		Events: events,
+		Models: plain,
+	}
 
-	var events eventList
+	if err := tmpl.ExecuteTemplate(os.Stdout, "template.tmpl", data); err != nil {
+		log.Fatalln("failed to execute template:", err)
+	}
+}
 
-	models, ok := data["models"].(map[string]any)
-	if !ok {
-		log.Fatalln("failed to get models")
+func loadSpec(specFile string) (*spec, error) {
+	input, err := os.Open(specFile)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(models) < 1 {
-		log.Fatalln("no models found")
+	defer input.Close()
+
+	s := new(spec)
+
+	if err := json.NewDecoder(input).Decode(s); err != nil {
+		return nil, err
 	}
 
-	for mkey, m := range models {
-		model := m.(map[string]any)
-		name := strings.ReplaceAll(mkey, "Id", "ID")
+	return s, nil
+}
+
+// mergeModels merges the models of a newer spec into those of the older
+// specs.  Properties which the older specs did not define for an existing
+// model are marked optional, since older versions of Asterisk will not send
+// them.  Descriptions and types are taken from the newer spec.
+func mergeModels(models map[string]*model, newer map[string]*model) {
+	for name, m := range newer {
+		if m.Properties == nil {
+			m.Properties = make(map[string]*property)
+		}
 
-		if name == "Message" || name == "Event" {
+		old, ok := models[name]
+		if !ok {
+			models[name] = m
 			continue
 		}
 
-		var pl propList
+		for pname, p := range m.Properties {
+			if _, ok := old.Properties[pname]; !ok {
+				optional := false
+				p.Required = &optional
+			}
 
-		props := model["properties"].(map[string]any)
-		for pkey, p := range props {
-			propm := p.(map[string]any)
-			desc, _ := propm["description"].(string)
+			old.Properties[pname] = p
+		}
 
-			desc = strings.ReplaceAll(desc, "\n", "")
-			desc = strings.ReplaceAll(desc, "\r", "")
+		if m.Description != "" {
+			old.Description = m.Description
+		}
 
-			if desc != "" {
-				desc = "// " + desc
+		for _, st := range m.SubTypes {
+			if !contains(old.SubTypes, st) {
+				old.SubTypes = append(old.SubTypes, st)
 			}
+		}
+	}
+}
 
-			t, ok := typeMappings[propm["type"].(string)]
-			if !ok {
-				t = propm["type"].(string)
+// eventModels returns the set of models which are generated as events:  the
+// subtypes of Message and Event and the legacy events.
+func eventModels(models map[string]*model) map[string]bool {
+	ret := make(map[string]bool)
+
+	for _, base := range []string{"Message", "Event"} {
+		if m, ok := models[base]; ok {
+			for _, st := range m.SubTypes {
+				if st != "Event" {
+					ret[st] = true
+				}
 			}
+		}
+	}
 
-			var name string
+	for name := range legacyEvents {
+		if _, ok := models[name]; ok {
+			ret[name] = true
+		}
+	}
+
+	return ret
+}
+
+func properties(m *model) (pl propList) {
+	for pkey, p := range m.Properties {
+		desc := cleanDescription(p.Description)
+		if desc != "" {
+			desc = "// " + desc
+		}
 
-			items := strings.Split(pkey, "_")
-			for _, x := range items {
-				name += cases.Title(language.English).String(x)
+		required := true
+		if p.Required != nil {
+			required = *p.Required
+		}
+
+		// Optional properties use omitzero rather than omitempty so that
+		// absent objects are omitted and present-but-empty lists are
+		// retained, keeping encoded events faithful to the source JSON.
+		mapping := "`json:\"" + pkey + "\"` "
+		if !required {
+			mapping = "`json:\"" + pkey + ",omitzero\"`"
+		}
+
+		pl = append(pl, prop{
+			Name:        fieldName(pkey),
+			Mapping:     mapping,
+			JSONName:    pkey,
+			Type:        goType(p.Type),
+			Description: desc,
+			Required:    required,
+		})
+	}
+
+	sort.Sort(pl)
+
+	return
+}
+
+// maxRefDepth limits the depth to which refs descends into plain models
+const maxRefDepth = 3
+
+// refs returns the references to resources held by the properties of the
+// given model, descending into any properties which are themselves plain
+// (non-event) models.  The selector is the Go expression for the model.
+func refs(models map[string]*model, isEvent map[string]bool, m *model, selector string, depth int) (list []ref) {
+	pl := properties(m)
+
+	for _, p := range pl {
+		t := m.Properties[p.JSONName].Type
+		sel := selector + "." + p.Name
+
+		for _, kk := range keyKinds {
+			if kk.Type == t {
+				list = append(list, ref{
+					Kind:    kk.Kind,
+					ID:      sel + kk.Kind.ID,
+					Present: sel + kk.Kind.Present,
+				})
 			}
+		}
+
+		if sub, ok := models[t]; ok && !isEvent[t] && depth < maxRefDepth {
+			list = append(list, refs(models, isEvent, sub, sel, depth+1)...)
+		}
+	}
+
+	return
+}
 
-			required := true
-			if req, ok := propm["required"].(bool); ok {
-				required = req
+// declaredMethods returns the names of the methods, by receiver type name,
+// which are declared in the hand-written Go files of the given package
+// directory.  Test files and generated (_gen.go) files are ignored.
+func declaredMethods(dir string) (map[string]map[string]bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]map[string]bool)
+	fset := token.NewFileSet()
+
+	for _, fn := range files {
+		if strings.HasSuffix(fn, "_test.go") || strings.HasSuffix(fn, "_gen.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, fn, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range f.Decls {
+			fd, ok := d.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 {
+				continue
 			}
 
-			mapping := "`json:\"" + pkey + "\"` "
-			if !required {
-				mapping = "`json:\"" + pkey + ",omitempty\"`"
+			recv := fd.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
 			}
 
-			pl = append(pl, prop{
-				Name:        name,
-				Mapping:     mapping,
-				JSONName:    pkey,
-				Type:        t,
-				Description: desc,
-			})
+			ident, ok := recv.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			if ret[ident.Name] == nil {
+				ret[ident.Name] = make(map[string]bool)
+			}
+
+			ret[ident.Name][fd.Name.Name] = true
 		}
+	}
+
+	return ret, nil
+}
 
-		sort.Sort(pl)
+func goName(name string) string {
+	return strings.ReplaceAll(name, "Id", "ID")
+}
 
-		desc, _ := model["description"].(string)
-		desc = strings.ReplaceAll(desc, "\n", "")
-		desc = strings.ReplaceAll(desc, "\r", "")
+func goType(t string) string {
+	if mapped, ok := typeMappings[t]; ok {
+		return mapped
+	}
 
-		events = append(events, event{
-			Name:        name,
-			Event:       mkey,
-			Description: desc,
-			Properties:  pl,
-		})
+	if strings.HasPrefix(t, "List[") && strings.HasSuffix(t, "]") {
+		return "[]" + goType(strings.TrimSuffix(strings.TrimPrefix(t, "List["), "]"))
 	}
 
-	sort.Sort(events)
+	return goName(t)
+}
 
-	if err := tmpl.ExecuteTemplate(os.Stdout, "template.tmpl", events); err != nil {
-		log.Fatalln("failed to execute template:", err)
+func fieldName(pkey string) (name string) {
+	items := strings.Split(pkey, "_")
+	for _, x := range items {
+		name += cases.Title(language.English).String(x)
 	}
+
+	return
+}
+
+func cleanDescription(desc string) string {
+	desc = strings.ReplaceAll(desc, "\n", "")
+	desc = strings.ReplaceAll(desc, "\r", "")
+
+	return desc
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// requestMethods maps the request methods of the native client to their HTTP methods
+var requestMethods = map[string]string{
+	"get":  "GET",
+	"post": "POST",
+	"put":  "PUT",
+	"del":  "DELETE",
+}
+
+// dynamicSegment matches one or more path parameters of a swagger path, for
+// the portions of a request URL which are not known statically
+const dynamicSegment = `\{[^/}]+\}(?:/\{[^/}]+\})*`
+
+var (
+	formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+	queryKey   = regexp.MustCompile(`(?:^|[?&])([A-Za-z_][A-Za-z0-9_]*)=`)
+)
+
+// call describes a request made to Asterisk by the native client
+type call struct {
+	// Method is the HTTP method of the request
+	Method string
+
+	// Pattern matches the swagger paths to which the request may be made
+	Pattern *regexp.Regexp
+
+	// Params is the set of parameters which the request may send
+	Params map[string]bool
+
+	// AnyBody indicates that the request body is not a struct, so its parameters are unknown
+	AnyBody bool
+}
+
+// implementedCalls returns the requests made to Asterisk by the native
+// client package in the given directory.  The package is type-checked so
+// that the parameters sent in request bodies may be determined.
+func implementedCalls(dir string) ([]*call, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to parse client package")
+	}
+
+	if len(pkgs) != 1 {
+		return nil, eris.Errorf("expected a single package in %s, found %d", dir, len(pkgs))
+	}
+
+	var (
+		files []*ast.File
+		name  string
+	)
+
+	for n, p := range pkgs {
+		name = n
+
+		for _, f := range p.Files {
+			files = append(files, f)
+		}
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check(name, fset, files, info); err != nil {
+		return nil, eris.Wrap(err, "failed to type-check client package")
+	}
+
+	var ret []*call
+
+	for _, f := range files {
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+
+			ret = append(ret, functionCalls(fn, info)...)
+		}
+	}
+
+	return ret, nil
+}
+
+// functionCalls returns the requests made by the given function
+func functionCalls(fn *ast.FuncDecl, info *types.Info) (ret []*call) {
+	queryParams := functionQueryParams(fn, info)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok || len(ce.Args) < 1 {
+			return true
+		}
+
+		method, ok := requestMethod(ce, info)
+		if !ok {
+			return true
+		}
+
+		c := &call{
+			Method: method,
+			Params: make(map[string]bool),
+		}
+
+		if !c.setPath(urlParts(ce.Args[0], fn, info)) {
+			return true
+		}
+
+		for k := range queryParams {
+			c.Params[k] = true
+		}
+
+		// the third argument of put and post is the request body
+		if len(ce.Args) > 2 && method != "DELETE" {
+			c.AnyBody = !bodyParams(info.TypeOf(ce.Args[2]), c.Params)
+		}
+
+		ret = append(ret, c)
+
+		return true
+	})
+
+	return
+}
+
+// requestMethod returns the HTTP method of the given call, if it is a call to
+// one of the request methods of the native client
+func requestMethod(ce *ast.CallExpr, info *types.Info) (string, bool) {
+	sel, ok := ce.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+
+	method, ok := requestMethods[sel.Sel.Name]
+	if !ok {
+		return "", false
+	}
+
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return "", false
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return "", false
+	}
+
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	if named, ok := t.(*types.Named); !ok || named.Obj().Name() != "Client" {
+		return "", false
+	}
+
+	return method, true
+}
+
+// urlPart is a portion of a request URL.  Dynamic parts are not known statically.
+type urlPart struct {
+	Literal string
+	Dynamic bool
+}
+
+// urlParts returns the parts of the given URL expression
+func urlParts(expr ast.Expr, fn *ast.FuncDecl, info *types.Info) []urlPart {
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return []urlPart{{Literal: constant.StringVal(tv.Value)}}
+	}
+
+	switch v := expr.(type) {
+	case *ast.ParenExpr:
+		return urlParts(v.X, fn, info)
+	case *ast.BinaryExpr:
+		if v.Op == token.ADD {
+			return append(urlParts(v.X, fn, info), urlParts(v.Y, fn, info)...)
+		}
+	case *ast.CallExpr:
+		if sel, ok := v.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Sprintf" && len(v.Args) > 0 {
+			if tv, ok := info.Types[v.Args[0]]; ok && tv.Value != nil {
+				return formatParts(constant.StringVal(tv.Value))
+			}
+		}
+	case *ast.Ident:
+		if rhs := definition(v, fn, info); rhs != nil {
+			return urlParts(rhs, fn, info)
+		}
+	}
+
+	return []urlPart{{Dynamic: true}}
+}
+
+// formatParts returns the parts of a URL built from the given format string
+func formatParts(format string) (ret []urlPart) {
+	idx := formatVerb.FindAllStringIndex(format, -1)
+
+	last := 0
+	for _, loc := range idx {
+		ret = append(ret, urlPart{Literal: format[last:loc[0]]}, urlPart{Dynamic: true})
+		last = loc[1]
+	}
+
+	return append(ret, urlPart{Literal: format[last:]})
+}
+
+// definition returns the expression with which the given local variable was
+// defined within the function, if any
+func definition(id *ast.Ident, fn *ast.FuncDecl, info *types.Info) (rhs ast.Expr) {
+	obj := info.Uses[id]
+	if obj == nil {
+		return nil
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		as, ok := n.(*ast.AssignStmt)
+		if !ok || as.Tok != token.DEFINE || len(as.Lhs) != len(as.Rhs) {
+			return rhs == nil
+		}
+
+		for i, l := range as.Lhs {
+			if lid, ok := l.(*ast.Ident); ok && info.Defs[lid] == obj {
+				rhs = as.Rhs[i]
+			}
+		}
+
+		return rhs == nil
+	})
+
+	return rhs
+}
+
+// setPath sets the path pattern of the call from the parts of its URL.
+// It returns false if the URL does not begin with a known path.
+func (c *call) setPath(parts []urlPart) bool {
+	if len(parts) == 0 || parts[0].Dynamic || !strings.HasPrefix(parts[0].Literal, "/") {
+		return false
+	}
+
+	var pattern strings.Builder
+
+	for _, p := range parts {
+		if p.Dynamic {
+			pattern.WriteString(dynamicSegment)
+
+			continue
+		}
+
+		lit, _, query := strings.Cut(p.Literal, "?")
+
+		pattern.WriteString(regexp.QuoteMeta(lit))
+
+		if query {
+			break
+		}
+	}
+
+	c.Pattern = regexp.MustCompile("^" + pattern.String() + "$")
+
+	return true
+}
+
+// functionQueryParams returns the query parameter names which the function
+// may send:  those in string literals of the form `name=` and those set on
+// url.Values.
+func functionQueryParams(fn *ast.FuncDecl, info *types.Info) map[string]bool {
+	ret := make(map[string]bool)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.BasicLit:
+			if v.Kind != token.STRING {
+				break
+			}
+
+			s, err := strconv.Unquote(v.Value)
+			if err != nil {
+				break
+			}
+
+			for _, m := range queryKey.FindAllStringSubmatch(s, -1) {
+				ret[m[1]] = true
+			}
+		case *ast.CallExpr:
+			sel, ok := v.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "Set" && sel.Sel.Name != "Add") || len(v.Args) != 2 {
+				break
+			}
+
+			if named, ok := info.TypeOf(sel.X).(*types.Named); !ok || named.String() != "net/url.Values" {
+				break
+			}
+
+			if tv, ok := info.Types[v.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+				ret[constant.StringVal(tv.Value)] = true
+			}
+		}
+
+		return true
+	})
+
+	return ret
+}
+
+// bodyParams adds the JSON names of the fields of the given request body type
+// to the parameter set.  It returns false if the body is not a struct.
+func bodyParams(t types.Type, params map[string]bool) bool {
+	for {
+		p, ok := t.(*types.Pointer)
+		if !ok {
+			break
+		}
+
+		t = p.Elem()
+	}
+
+	if t == nil {
+		return true
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+
+		name, _, _ := strings.Cut(reflect.StructTag(st.Tag(i)).Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if f.Embedded() && name == "" {
+			bodyParams(f.Type(), params)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name()
+		}
+
+		params[name] = true
+	}
+
+	return true
+}
+
+// missingParam describes a parameter of an implemented operation which is never sent
+type missingParam struct {
+	Op     *operation
+	Params []string
+}
+
+// coverage compares the operations of the spec with the calls made by the client
+type coverage struct {
+	Operations  int
+	Implemented int
+	Missing     []*operation
+	Partial     []missingParam
+}
+
+// computeCoverage determines which operations and parameters of the spec are
+// not implemented by the given calls.  WebSocket operations are served by the
+// event connection rather than by REST requests, so they are not reported.
+func computeCoverage(ops []*operation, calls []*call) *coverage {
+	ret := new(coverage)
+
+	for _, op := range ops {
+		if op.Upgrade != "" {
+			continue
+		}
+
+		ret.Operations++
+
+		var (
+			found   bool
+			anyBody bool
+		)
+
+		params := make(map[string]bool)
+
+		for _, c := range calls {
+			if c.Method != op.HTTPMethod || !c.Pattern.MatchString(op.Path) {
+				continue
+			}
+
+			found = true
+			anyBody = anyBody || c.AnyBody
+
+			for k := range c.Params {
+				params[k] = true
+			}
+		}
+
+		if !found {
+			ret.Missing = append(ret.Missing, op)
+			continue
+		}
+
+		ret.Implemented++
+
+		var missing []string
+
+		for _, p := range op.Parameters {
+			switch {
+			case p.ParamType == "path":
+			case p.ParamType == "body" && anyBody:
+			case params[p.Name]:
+			default:
+				missing = append(missing, p.Name)
+			}
+		}
+
+		if len(missing) > 0 {
+			sort.Strings(missing)
+
+			ret.Partial = append(ret.Partial, missingParam{Op: op, Params: missing})
+		}
+	}
+
+	return ret
+}
+
+// Write writes the coverage report
+func (c *coverage) Write(w io.Writer) error {
+	var err error
+
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("ARI REST coverage: %d of %d operations implemented\n", c.Implemented, c.Operations)
+
+	if len(c.Missing) > 0 {
+		printf("\nMissing operations:\n")
+
+		for _, op := range c.Missing {
+			printf("  %-7s %-55s %s.%s\n", op.HTTPMethod, op.Path, op.Resource, op.Nickname)
+		}
+	}
+
+	if len(c.Partial) > 0 {
+		printf("\nMissing parameters:\n")
+
+		for _, p := range c.Partial {
+			printf("  %-7s %-55s %s\n", p.Op.HTTPMethod, p.Op.Path, strings.Join(p.Params, ", "))
+		}
+	}
+
+	return err
+}
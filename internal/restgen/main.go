@@ -0,0 +1,56 @@
+package main
+
+// Generate REST request and response types from the ARI swagger and report
+// the operations and parameters which the native client does not implement.
+//
+// The swagger is read from the resources.json resource listing, alongside
+// which the API declarations (channels.json, bridges.json, etc) must be
+// found.  Asterisk installs these into its data directory
+// (/var/lib/asterisk/rest-api by default); they may also be found in
+// rest-api/api-docs of the Asterisk source tree.
+
+import (
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	clientDir := flag.String("client", "client/native", "directory of the client package whose coverage is reported")
+	typesFile := flag.String("types", "", "file to which request and response types are written (none if empty)")
+	pkgName := flag.String("package", "ariapi", "package name of the generated types")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		log.Fatalf("Usage: %s [-client <dir>] [-types <file.go>] [-package <name>] <resources.json>\n", os.Args[0])
+	}
+
+	decls, err := loadSpec(flag.Arg(0))
+	if err != nil {
+		log.Fatalln("failed to load ARI swagger:", err)
+	}
+
+	if *typesFile != "" {
+		f, err := os.Create(*typesFile)
+		if err != nil {
+			log.Fatalln("failed to create types file:", err)
+		}
+
+		if err := generateTypes(f, *pkgName, decls); err != nil {
+			log.Fatalln("failed to generate types:", err)
+		}
+
+		if err := f.Close(); err != nil {
+			log.Fatalln("failed to write types file:", err)
+		}
+	}
+
+	calls, err := implementedCalls(*clientDir)
+	if err != nil {
+		log.Fatalln("failed to analyze client:", err)
+	}
+
+	if err := computeCoverage(operations(decls), calls).Write(os.Stdout); err != nil {
+		log.Fatalln("failed to write coverage report:", err)
+	}
+}
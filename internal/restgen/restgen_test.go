@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	decls, err := loadSpec(filepath.Join("testdata", "resources.json"))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	calls, err := implementedCalls(filepath.Join("..", "..", "client", "native"))
+	if err != nil {
+		t.Fatalf("failed to analyze client: %v", err)
+	}
+
+	c := computeCoverage(operations(decls), calls)
+
+	// the event websocket is not a REST operation
+	if c.Operations != 8 {
+		t.Errorf("Expected 8 operations, got %d", c.Operations)
+	}
+
+	var missing []string
+	for _, op := range c.Missing {
+		missing = append(missing, op.Nickname)
+	}
+
+	if strings.Join(missing, ",") != "redirect,rtpstatistics" {
+		t.Errorf("Unexpected missing operations: %v", missing)
+	}
+
+	if len(c.Partial) != 1 || c.Partial[0].Op.Nickname != "hangup" || strings.Join(c.Partial[0].Params, ",") != "reason_code" {
+		t.Errorf("Unexpected missing parameters: %+v", c.Partial)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := c.Write(buf); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "6 of 8 operations implemented") {
+		t.Errorf("Unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestGenerateTypes(t *testing.T) {
+	decls, err := loadSpec(filepath.Join("testdata", "resources.json"))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := generateTypes(buf, "ariapi", decls); err != nil {
+		t.Fatalf("failed to generate types: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", buf, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+
+	for _, s := range []string{
+		"type Channel struct",
+		"type ChannelsOriginateRequest struct",
+		"type ChannelsListResponse = []Channel",
+		"OtherChannelID string",
+		"type EventsUserEventRequest struct",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected generated source to contain '%s'", s)
+		}
+	}
+
+	if strings.Contains(buf.String(), "type Message struct") {
+		t.Errorf("Expected event models to be skipped")
+	}
+}
+
+func TestExportName(t *testing.T) {
+	for in, out := range map[string]string{
+		"channelId":       "ChannelID",
+		"protocol_id":     "ProtocolID",
+		"reason_code":     "ReasonCode",
+		"identity":        "Identity",
+		"originateWithId": "OriginateWithID",
+	} {
+		if n := exportName(in); n != out {
+			t.Errorf("Expected '%s' for '%s', got '%s'", out, in, n)
+		}
+	}
+}
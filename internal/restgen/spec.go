@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// resourceListing is the subset of the swagger resources.json used by the generator
+type resourceListing struct {
+	APIs []struct {
+		Path        string `json:"path"`
+		Description string `json:"description"`
+	} `json:"apis"`
+}
+
+// declaration is the subset of a swagger API declaration (channels.json, etc) used by the generator
+type declaration struct {
+	// Name is the name of the resource, derived from the resource path (channels, bridges, etc)
+	Name string `json:"-"`
+
+	ResourcePath string            `json:"resourcePath"`
+	APIs         []api             `json:"apis"`
+	Models       map[string]*model `json:"models"`
+}
+
+type api struct {
+	Path       string       `json:"path"`
+	Operations []*operation `json:"operations"`
+}
+
+type operation struct {
+	// Resource is the name of the resource to which the operation belongs
+	Resource string `json:"-"`
+
+	// Path is the path of the API to which the operation belongs
+	Path string `json:"-"`
+
+	HTTPMethod    string       `json:"httpMethod"`
+	Upgrade       string       `json:"upgrade"`
+	Summary       string       `json:"summary"`
+	Nickname      string       `json:"nickname"`
+	ResponseClass string       `json:"responseClass"`
+	Parameters    []*parameter `json:"parameters"`
+}
+
+type parameter struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	ParamType     string `json:"paramType"`
+	DataType      string `json:"dataType"`
+	Required      bool   `json:"required"`
+	AllowMultiple bool   `json:"allowMultiple"`
+}
+
+type model struct {
+	ID          string               `json:"id"`
+	Description string               `json:"description"`
+	Properties  map[string]*property `json:"properties"`
+}
+
+type property struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// loadSpec loads the given resources.json and each of the API declarations
+// which it lists.  The declarations are expected to be in the same directory.
+func loadSpec(resourcesFile string) ([]*declaration, error) {
+	listing := new(resourceListing)
+	if err := readJSON(resourcesFile, listing); err != nil {
+		return nil, eris.Wrap(err, "failed to read resource listing")
+	}
+
+	dir := filepath.Dir(resourcesFile)
+
+	var ret []*declaration
+
+	for _, a := range listing.APIs {
+		name := resourceName(a.Path)
+		if name == "" {
+			return nil, eris.Errorf("invalid resource path %s", a.Path)
+		}
+
+		decl := new(declaration)
+		if err := readJSON(filepath.Join(dir, name+".json"), decl); err != nil {
+			return nil, eris.Wrapf(err, "failed to read API declaration for %s", name)
+		}
+
+		decl.Name = name
+
+		for _, x := range decl.APIs {
+			for _, op := range x.Operations {
+				op.Resource = name
+				op.Path = x.Path
+			}
+		}
+
+		ret = append(ret, decl)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret, nil
+}
+
+// resourceName returns the name of the resource from its listing path (/api-docs/channels.{format})
+func resourceName(p string) string {
+	return strings.TrimSuffix(filepath.Base(p), ".{format}")
+}
+
+func readJSON(name string, v any) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return json.NewDecoder(f).Decode(v)
+}
+
+// operations returns the operations of all the given declarations
+func operations(decls []*declaration) (ret []*operation) {
+	for _, d := range decls {
+		for _, x := range d.APIs {
+			ret = append(ret, x.Operations...)
+		}
+	}
+
+	return
+}
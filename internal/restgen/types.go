@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"go/format"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rotisserie/eris"
+)
+
+//go:embed types.tmpl
+var typesTemplate string
+
+// eventsResource is the resource whose models are the ARI events, which are
+// generated by eventgen rather than restgen
+const eventsResource = "events"
+
+var typeMappings = map[string]string{
+	"boolean":    "bool",
+	"int":        "int",
+	"long":       "int64",
+	"double":     "float64",
+	"string":     "string",
+	"Date":       "string", // Asterisk dates are not RFC 3339, so they are left for the caller to parse
+	"object":     "interface{}",
+	"containers": "map[string]string",
+	"binary":     "[]byte",
+}
+
+var idSuffix = regexp.MustCompile(`Id([A-Z]|$)`)
+
+type typeDef struct {
+	Name        string
+	Description string
+	Fields      []field
+}
+
+type requestDef struct {
+	Name     string
+	Method   string
+	Path     string
+	Summary  string
+	Fields   []field
+	Response string
+}
+
+type field struct {
+	Name    string
+	Type    string
+	Tag     string
+	Comment string
+}
+
+// generateTypes writes the Go source for the models of the given API
+// declarations and for the request parameters and responses of their
+// operations
+func generateTypes(w io.Writer, pkg string, decls []*declaration) error {
+	tmpl, err := template.New("types").Parse(typesTemplate)
+	if err != nil {
+		return eris.Wrap(err, "failed to parse template")
+	}
+
+	data := struct {
+		Package  string
+		Models   []typeDef
+		Requests []requestDef
+	}{
+		Package: pkg,
+	}
+
+	seen := make(map[string]bool)
+
+	for _, d := range decls {
+		if d.Name != eventsResource {
+			for _, m := range d.Models {
+				if !seen[m.ID] {
+					seen[m.ID] = true
+
+					data.Models = append(data.Models, modelType(m))
+				}
+			}
+		}
+
+		for _, x := range d.APIs {
+			for _, op := range x.Operations {
+				if op.Upgrade == "" {
+					data.Requests = append(data.Requests, requestType(op))
+				}
+			}
+		}
+	}
+
+	sort.Slice(data.Models, func(i, j int) bool {
+		return data.Models[i].Name < data.Models[j].Name
+	})
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, data); err != nil {
+		return eris.Wrap(err, "failed to execute template")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return eris.Wrap(err, "failed to format generated source")
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+func modelType(m *model) typeDef {
+	ret := typeDef{
+		Name:        exportName(m.ID),
+		Description: oneLine(m.Description),
+	}
+
+	for name, p := range m.Properties {
+		tag := name
+		if !p.Required {
+			tag += ",omitempty"
+		}
+
+		ret.Fields = append(ret.Fields, field{
+			Name:    exportName(name),
+			Type:    goType(p.Type),
+			Tag:     tag,
+			Comment: comment(p.Description),
+		})
+	}
+
+	sortFields(ret.Fields)
+
+	return ret
+}
+
+func requestType(op *operation) requestDef {
+	ret := requestDef{
+		Name:    exportName(op.Resource) + exportName(op.Nickname),
+		Method:  op.HTTPMethod,
+		Path:    op.Path,
+		Summary: oneLine(op.Summary),
+	}
+
+	if op.ResponseClass != "" && op.ResponseClass != "void" {
+		ret.Response = goType(op.ResponseClass)
+	}
+
+	for _, p := range op.Parameters {
+		t := goType(p.DataType)
+		if p.AllowMultiple {
+			t = "[]" + t
+		}
+
+		tag := p.Name
+		if p.ParamType == "path" {
+			// path parameters are part of the URL rather than the request body
+			tag = "-"
+		} else if !p.Required {
+			tag += ",omitempty"
+		}
+
+		ret.Fields = append(ret.Fields, field{
+			Name:    exportName(p.Name),
+			Type:    t,
+			Tag:     tag,
+			Comment: comment(p.Description),
+		})
+	}
+
+	sortFields(ret.Fields)
+
+	return ret
+}
+
+func goType(t string) string {
+	if mapped, ok := typeMappings[t]; ok {
+		return mapped
+	}
+
+	if strings.HasPrefix(t, "List[") && strings.HasSuffix(t, "]") {
+		return "[]" + goType(strings.TrimSuffix(strings.TrimPrefix(t, "List["), "]"))
+	}
+
+	return exportName(t)
+}
+
+// exportName converts a swagger name (camelCase or snake_case) to an exported Go name
+func exportName(name string) string {
+	var ret strings.Builder
+
+	for _, x := range strings.Split(name, "_") {
+		if x == "" {
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(x)
+		ret.WriteRune(unicode.ToUpper(r))
+		ret.WriteString(x[size:])
+	}
+
+	return idSuffix.ReplaceAllString(ret.String(), "ID$1")
+}
+
+func oneLine(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", "")
+
+	return strings.TrimSpace(s)
+}
+
+func comment(s string) string {
+	if s = oneLine(s); s != "" {
+		return "// " + s
+	}
+
+	return ""
+}
+
+func sortFields(fx []field) {
+	sort.Slice(fx, func(i, j int) bool {
+		return fx[i].Name < fx[j].Name
+	})
+}
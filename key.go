@@ -1,6 +1,13 @@
 package ari
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
 
 const (
 	// ApplicationKey is the key kind for ARI Application resources.
@@ -46,12 +53,15 @@ const (
 // Keys is a list of keys
 type Keys []*Key
 
-// Filter filters the key list using the given key type match
+// Filter filters the key list using the given matchers, returning those
+// keys which are matched by any of them.  Each key is returned at most once,
+// in the order of the original list.
 func (kx Keys) Filter(mx ...Matcher) (ret Keys) {
-	for _, m := range mx {
-		for _, k := range kx {
+	for _, k := range kx {
+		for _, m := range mx {
 			if m.Match(k) {
 				ret = append(ret, k)
+				break
 			}
 		}
 	}
@@ -70,6 +80,72 @@ func (kx Keys) Without(m Matcher) (ret Keys) {
 	return
 }
 
+// Union returns the keys which are in either list.  Keys are compared by
+// value, each distinct key is returned once, and the order of the lists is
+// preserved.
+func (kx Keys) Union(o Keys) (ret Keys) {
+	seen := make(map[string]bool)
+
+	for _, list := range []Keys{kx, o} {
+		for _, k := range list {
+			if s := k.Canonical(); !seen[s] {
+				seen[s] = true
+
+				ret = append(ret, k)
+			}
+		}
+	}
+
+	return
+}
+
+// Intersect returns the keys which are in both lists.  Keys are compared by
+// value, each distinct key is returned once, and the order of the receiver
+// is preserved.
+func (kx Keys) Intersect(o Keys) (ret Keys) {
+	other := o.set()
+	seen := make(map[string]bool)
+
+	for _, k := range kx {
+		if s := k.Canonical(); other[s] && !seen[s] {
+			seen[s] = true
+
+			ret = append(ret, k)
+		}
+	}
+
+	return
+}
+
+// Difference returns the keys which are in the receiver but not in the given
+// list.  Keys are compared by value, each distinct key is returned once, and
+// the order of the receiver is preserved.
+func (kx Keys) Difference(o Keys) (ret Keys) {
+	other := o.set()
+	seen := make(map[string]bool)
+
+	for _, k := range kx {
+		if s := k.Canonical(); !other[s] && !seen[s] {
+			seen[s] = true
+
+			ret = append(ret, k)
+		}
+	}
+
+	return
+}
+
+// set returns the set of canonical string forms of the keys
+func (kx Keys) set() map[string]bool {
+	ret := make(map[string]bool, len(kx))
+
+	for _, k := range kx {
+		ret[k.Canonical()] = true
+	}
+
+	return ret
+}
+
 // First returns the first key from a list of keys.  It is safe to use on empty lists, in which case, it will return nil.
 func (kx Keys) First() *Key {
 	if len(kx) < 1 {
@@ -237,6 +313,9 @@ func (k *Key) New(kind, id string) *Key {
 	return n
 }
 
+// KeyScheme is the URI scheme of the canonical string form of a Key
+const KeyScheme = "ari"
+
 func (k *Key) String() string {
 	if k.ID != "" {
 		return k.ID
@@ -252,3 +331,146 @@ func (k *Key) String() string {
 
 	return "emptyKey"
 }
+
+// Canonical returns the canonical string form of the key, which is of the
+// form:
+//
+//	ari:<kind>/<id>[?app=<app>&dialog=<dialog>&node=<node>]
+//
+// The kind and ID are path-escaped and the location parameters are
+// query-escaped, sorted, and omitted when empty.  The canonical form may be
+// parsed back into a Key with ParseKey.
+func (k *Key) Canonical() string {
+	var sb strings.Builder
+
+	sb.WriteString(KeyScheme + ":")
+	sb.WriteString(url.PathEscape(k.Kind))
+	sb.WriteString("/")
+	sb.WriteString(url.PathEscape(k.ID))
+
+	v := url.Values{}
+
+	if k.App != "" {
+		v.Set("app", k.App)
+	}
+
+	if k.Dialog != "" {
+		v.Set("dialog", k.Dialog)
+	}
+
+	if k.Node != "" {
+		v.Set("node", k.Node)
+	}
+
+	if len(v) > 0 {
+		sb.WriteString("?")
+		sb.WriteString(v.Encode())
+	}
+
+	return sb.String()
+}
+
+// ParseKey parses the canonical string form of a Key, as returned by
+// Key.Canonical.
+func ParseKey(s string) (*Key, error) {
+	rest, ok := strings.CutPrefix(s, KeyScheme+":")
+	if !ok {
+		return nil, eris.Errorf("key %q does not have the %q scheme", s, KeyScheme)
+	}
+
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	kind, id, ok := strings.Cut(rest, "/")
+	if !ok {
+		return nil, eris.Errorf("key %q has no kind/id separator", s)
+	}
+
+	k := new(Key)
+
+	var err error
+
+	if k.Kind, err = url.PathUnescape(kind); err != nil {
+		return nil, eris.Wrapf(err, "failed to parse kind of key %q", s)
+	}
+
+	if k.ID, err = url.PathUnescape(id); err != nil {
+		return nil, eris.Wrapf(err, "failed to parse ID of key %q", s)
+	}
+
+	if !hasQuery {
+		return k, nil
+	}
+
+	v, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to parse location of key %q", s)
+	}
+
+	for name, values := range v {
+		if len(values) != 1 {
+			return nil, eris.Errorf("key %q has multiple values for %q", s, name)
+		}
+
+		switch name {
+		case "app":
+			k.App = values[0]
+		case "dialog":
+			k.Dialog = values[0]
+		case "node":
+			k.Node = values[0]
+		default:
+			return nil, eris.Errorf("key %q has unknown parameter %q", s, name)
+		}
+	}
+
+	return k, nil
+}
+
+// MarshalText encodes the key in its canonical string form
+func (k *Key) MarshalText() ([]byte, error) {
+	return []byte(k.Canonical()), nil
+}
+
+// UnmarshalText decodes the key from its canonical string form
+func (k *Key) UnmarshalText(text []byte) error {
+	n, err := ParseKey(string(text))
+	if err != nil {
+		return err
+	}
+
+	*k = *n
+
+	return nil
+}
+
+// keyJSON is the JSON object form of a Key
+type keyJSON Key
+
+// MarshalJSON encodes the key as a JSON object of its fields.  This takes
+// precedence over MarshalText so that the JSON encoding of keys is
+// unchanged.
+func (k *Key) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*keyJSON)(k))
+}
+
+// UnmarshalJSON decodes the key from either a JSON object of its fields or a
+// JSON string of its canonical form.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		return k.UnmarshalText([]byte(s))
+	}
+
+	var o keyJSON
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+
+	*k = Key(o)
+
+	return nil
+}
@@ -1,6 +1,12 @@
 package ari
 
-import "testing"
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
 
 func TestKeyMatch(t *testing.T) {
 	// two empty keys should match
@@ -97,3 +103,210 @@ func TestKeysFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyMatchDialog(t *testing.T) {
+	ok := DialogKey("d1").Match(NewKey(ChannelKey, "ch1", WithDialog("d1")))
+	if !ok {
+		t.Errorf("Dialog key should match keys bound to the same dialog")
+	}
+
+	ok = DialogKey("d1").Match(NewKey(ChannelKey, "ch1", WithDialog("d2")))
+	if ok {
+		t.Errorf("Dialog key should not match keys bound to another dialog")
+	}
+
+	ok = DialogKey("d1").Match(NewKey(ChannelKey, "ch1"))
+	if !ok {
+		t.Errorf("Dialog key should match keys which are not bound to a dialog")
+	}
+
+	ok = NewKey(ChannelKey, "ch1").Match(NewKey(ChannelKey, "ch1", WithDialog("d1")))
+	if !ok {
+		t.Errorf("Key without dialog should match keys bound to a dialog")
+	}
+
+	ok = NewKey(ChannelKey, "ch1", WithDialog("d1")).Match(NewKey(ChannelKey, "ch1"))
+	if !ok {
+		t.Errorf("Resource key bound to a dialog should match keys which are not bound to a dialog")
+	}
+}
+
+var keyCanonicalTests = []struct {
+	key      *Key
+	expected string
+}{
+	{NewKey("", ""), "ari:/"},
+	{NewKey(ChannelKey, "ch1"), "ari:channel/ch1"},
+	{KindKey(BridgeKey), "ari:bridge/"},
+	{DialogKey("d1"), "ari:/?dialog=d1"},
+	{NodeKey("app", "node"), "ari:/?app=app&node=node"},
+	{NewKey(EndpointKey, "PJSIP/alice", WithApp("app"), WithDialog("d 1"), WithNode("42:01:0a")), "ari:endpoint/PJSIP%2Falice?app=app&dialog=d+1&node=42%3A01%3A0a"},
+	{NewKey(ChannelKey, "a?b&c=d/e%f"), "ari:channel/a%3Fb&c=d%2Fe%25f"},
+}
+
+func TestKeyCanonical(t *testing.T) {
+	for _, tt := range keyCanonicalTests {
+		if s := tt.key.Canonical(); s != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, s)
+		}
+
+		k, err := ParseKey(tt.expected)
+		if err != nil {
+			t.Errorf("Failed to parse '%s': %v", tt.expected, err)
+			continue
+		}
+
+		if !keysEqual(k, tt.key) {
+			t.Errorf("Expected '%#v', got '%#v'", tt.key, k)
+		}
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	for _, tt := range []struct {
+		key      *Key
+		expected string
+	}{
+		{NewKey(ChannelKey, "ch1", WithApp("app"), WithNode("node")), "ch1"},
+		{DialogKey("d1"), "[d1]"},
+		{NodeKey("app", "node"), "app@node"},
+		{NewKey("", ""), "emptyKey"},
+	} {
+		if s := tt.key.String(); s != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, s)
+		}
+	}
+}
+
+func TestParseKeyInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"channel/ch1",
+		"ari:channel",
+		"ari:channel/%zz",
+		"ari:channel/ch1?app=a&app=b",
+		"ari:channel/ch1?color=blue",
+	} {
+		if _, err := ParseKey(s); err == nil {
+			t.Errorf("Expected error parsing '%s'", s)
+		}
+	}
+}
+
+func TestKeyJSON(t *testing.T) {
+	key := NewKey(ChannelKey, "ch1", WithApp("app"), WithDialog("d1"), WithNode("node"))
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	if string(data) != `{"kind":"channel","id":"ch1","node":"node","dialog":"d1","app":"app"}` {
+		t.Errorf("Unexpected JSON encoding of key: %s", data)
+	}
+
+	var fromObject Key
+	if err = json.Unmarshal(data, &fromObject); err != nil {
+		t.Fatalf("Failed to unmarshal key object: %v", err)
+	}
+
+	if !keysEqual(&fromObject, key) {
+		t.Errorf("Expected '%v', got '%v'", key, &fromObject)
+	}
+
+	var fromString Key
+	if err = json.Unmarshal([]byte(`"`+key.Canonical()+`"`), &fromString); err != nil {
+		t.Fatalf("Failed to unmarshal key string: %v", err)
+	}
+
+	if !keysEqual(&fromString, key) {
+		t.Errorf("Expected '%v', got '%v'", key, &fromString)
+	}
+}
+
+func TestKeyYAML(t *testing.T) {
+	in := struct {
+		Key *Key `yaml:"key"`
+	}{
+		Key: NewKey(EndpointKey, "PJSIP/alice", WithDialog("d1")),
+	}
+
+	data, err := yaml.Marshal(&in)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	out := struct {
+		Key *Key `yaml:"key"`
+	}{}
+	if err = yaml.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Failed to unmarshal key: %v", err)
+	}
+
+	if !keysEqual(out.Key, in.Key) {
+		t.Errorf("Expected '%v', got '%v'", in.Key, out.Key)
+	}
+}
+
+func TestKeyFlag(t *testing.T) {
+	var key Key
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.TextVar(&key, "key", NewKey("", ""), "key")
+
+	if err := fs.Parse([]string{"-key", "ari:bridge/br1?node=node"}); err != nil {
+		t.Fatalf("Failed to parse flag: %v", err)
+	}
+
+	if !keysEqual(&key, NewKey(BridgeKey, "br1", WithNode("node"))) {
+		t.Errorf("Unexpected key from flag: %v", &key)
+	}
+}
+
+func FuzzKeyRoundTrip(f *testing.F) {
+	f.Add("channel", "ch1", "app", "node", "dialog")
+	f.Add("endpoint", "PJSIP/alice", "", "42:01:0a", "")
+	f.Add("", "", "", "", "a b+c&d=e")
+
+	f.Fuzz(func(t *testing.T, kind, id, app, node, dialog string) {
+		key := &Key{Kind: kind, ID: id, App: app, Node: node, Dialog: dialog}
+
+		k, err := ParseKey(key.Canonical())
+		if err != nil {
+			t.Fatalf("Failed to parse '%s': %v", key.Canonical(), err)
+		}
+
+		if !keysEqual(k, key) {
+			t.Errorf("Expected '%#v', got '%#v'", key, k)
+		}
+	})
+}
+
+func FuzzParseKey(f *testing.F) {
+	for _, tt := range keyCanonicalTests {
+		f.Add(tt.expected)
+	}
+
+	f.Add("ari:channel/ch1?")
+	f.Add("ari:/?app")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		k, err := ParseKey(s)
+		if err != nil {
+			return
+		}
+
+		k2, err := ParseKey(k.Canonical())
+		if err != nil {
+			t.Fatalf("Failed to parse canonical form '%s' of '%s': %v", k.Canonical(), s, err)
+		}
+
+		if !keysEqual(k, k2) {
+			t.Errorf("Expected '%#v', got '%#v'", k, k2)
+		}
+	})
+}
+
+func keysEqual(a, b *Key) bool {
+	return a.Kind == b.Kind && a.ID == b.ID && a.App == b.App && a.Node == b.Node && a.Dialog == b.Dialog
+}
@@ -0,0 +1,72 @@
+package ari
+
+import (
+	"regexp"
+	"strings"
+)
+
+// And returns a Matcher which matches keys which are matched by all of the
+// given matchers.  With no matchers, all keys are matched.
+func And(mx ...Matcher) Matcher {
+	return MatchFunc(func(k *Key) bool {
+		for _, m := range mx {
+			if !m.Match(k) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Or returns a Matcher which matches keys which are matched by any of the
+// given matchers.  With no matchers, no keys are matched.
+func Or(mx ...Matcher) Matcher {
+	return MatchFunc(func(k *Key) bool {
+		for _, m := range mx {
+			if m.Match(k) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Not returns a Matcher which matches keys which are not matched by the given matcher
+func Not(m Matcher) Matcher {
+	return MatchFunc(func(k *Key) bool {
+		return !m.Match(k)
+	})
+}
+
+// IDPrefix returns a Matcher which matches keys whose ID begins with the given prefix
+func IDPrefix(prefix string) Matcher {
+	return MatchFunc(func(k *Key) bool {
+		return k != nil && strings.HasPrefix(k.ID, prefix)
+	})
+}
+
+// IDRegexp returns a Matcher which matches keys whose ID matches the given regular expression
+func IDRegexp(re *regexp.Regexp) Matcher {
+	return MatchFunc(func(k *Key) bool {
+		return k != nil && re.MatchString(k.ID)
+	})
+}
+
+// KindIn returns a Matcher which matches keys of any of the given kinds
+func KindIn(kinds ...string) Matcher {
+	return MatchFunc(func(k *Key) bool {
+		if k == nil {
+			return false
+		}
+
+		for _, kind := range kinds {
+			if k.Kind == kind {
+				return true
+			}
+		}
+
+		return false
+	})
+}
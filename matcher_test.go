@@ -0,0 +1,72 @@
+package ari
+
+import (
+	"regexp"
+	"testing"
+)
+
+var matcherTestKeys = Keys{
+	NewKey(ChannelKey, "ch1"),
+	NewKey(ChannelKey, "ch2"),
+	NewKey(ChannelKey, "snoop-1"),
+	NewKey(BridgeKey, "br1"),
+	NewKey(PlaybackKey, "pb1"),
+}
+
+func keyIDs(kx Keys) (ids []string) {
+	for _, k := range kx {
+		ids = append(ids, k.ID)
+	}
+
+	return
+}
+
+func expectIDs(t *testing.T, name string, kx Keys, expected ...string) {
+	t.Helper()
+
+	ids := keyIDs(kx)
+	if len(ids) != len(expected) {
+		t.Errorf("%s: expected %v, got %v", name, expected, ids)
+		return
+	}
+
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Errorf("%s: expected %v, got %v", name, expected, ids)
+			return
+		}
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	kx := matcherTestKeys
+
+	expectIDs(t, "KindIn", kx.Filter(KindIn(BridgeKey, PlaybackKey)), "br1", "pb1")
+	expectIDs(t, "IDPrefix", kx.Filter(IDPrefix("ch")), "ch1", "ch2")
+	expectIDs(t, "IDRegexp", kx.Filter(IDRegexp(regexp.MustCompile(`^[a-z]+1$`))), "ch1", "br1", "pb1")
+	expectIDs(t, "Not", kx.Filter(Not(KindIn(ChannelKey))), "br1", "pb1")
+	expectIDs(t, "And", kx.Filter(And(KindIn(ChannelKey), Not(IDPrefix("snoop-")))), "ch1", "ch2")
+	expectIDs(t, "Or", kx.Filter(Or(IDPrefix("snoop-"), KindIn(BridgeKey))), "snoop-1", "br1")
+	expectIDs(t, "empty And", kx.Filter(And()), "ch1", "ch2", "snoop-1", "br1", "pb1")
+	expectIDs(t, "empty Or", kx.Filter(Or()))
+}
+
+func TestKeysFilterDeduplicates(t *testing.T) {
+	expectIDs(t, "Filter", matcherTestKeys.Filter(KindIn(ChannelKey), IDPrefix("ch")), "ch1", "ch2", "snoop-1")
+}
+
+func TestKeysSetOperations(t *testing.T) {
+	a := Keys{NewKey(ChannelKey, "ch1"), NewKey(ChannelKey, "ch2"), NewKey(ChannelKey, "ch1")}
+	b := Keys{NewKey(ChannelKey, "ch2"), NewKey(BridgeKey, "ch2"), NewKey(BridgeKey, "br1")}
+
+	union := a.Union(b)
+	expectIDs(t, "Union", union, "ch1", "ch2", "ch2", "br1")
+
+	if union[2].Kind != BridgeKey {
+		t.Errorf("Union: expected keys to be compared by kind as well as ID")
+	}
+
+	expectIDs(t, "Intersect", a.Intersect(b), "ch2")
+	expectIDs(t, "Difference", a.Difference(b), "ch1")
+	expectIDs(t, "Difference (reverse)", b.Difference(a), "ch2", "br1")
+}
@@ -0,0 +1,250 @@
+package ari
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// MediaScheme is the scheme of a media URI, which determines how Asterisk plays it
+type MediaScheme string
+
+const (
+	// MediaSound plays a sound file, by name (ex. "hello-world")
+	MediaSound MediaScheme = "sound"
+
+	// MediaRecording plays a stored recording, by name
+	MediaRecording MediaScheme = "recording"
+
+	// MediaNumber says a number (ex. "42" is said "forty-two")
+	MediaNumber MediaScheme = "number"
+
+	// MediaDigits says a string of digits, digit by digit
+	MediaDigits MediaScheme = "digits"
+
+	// MediaCharacters spells a string, character by character
+	MediaCharacters MediaScheme = "characters"
+
+	// MediaTone plays a tone, either an indication of the tone zone (ex.
+	// "ring") or a tone pattern (ex. "440+480/2000,0/4000")
+	MediaTone MediaScheme = "tone"
+
+	// MediaHTTP plays a media file fetched by HTTP
+	MediaHTTP MediaScheme = "http"
+
+	// MediaHTTPS plays a media file fetched by HTTPS
+	MediaHTTPS MediaScheme = "https"
+)
+
+// MediaSchemes is the list of media schemes which Asterisk supports
+var MediaSchemes = []MediaScheme{
+	MediaSound, MediaRecording, MediaNumber, MediaDigits, MediaCharacters, MediaTone, MediaHTTP, MediaHTTPS,
+}
+
+// MediaURI describes a media URI, which may be played to a channel or bridge
+// (see ChannelHandle.PlayMedia).  Its String method returns the media URI
+// as Asterisk expects it.
+type MediaURI struct {
+	// Scheme is the scheme of the media URI
+	Scheme MediaScheme
+
+	// Resource is the part of the media URI after the scheme (ex. the name of
+	// the sound, or, for HTTP, the "//host/path" of the URL)
+	Resource string
+
+	// ToneZone is the tone zone (ex. "fr") of the indication of a tone.  If
+	// empty, the tone zone of the channel is used.
+	ToneZone string
+}
+
+// String returns the media URI
+func (m MediaURI) String() string {
+	s := string(m.Scheme) + ":" + m.Resource
+
+	if m.ToneZone != "" {
+		s += ";tonezone=" + m.ToneZone
+	}
+
+	return s
+}
+
+// SoundURI returns the media URI of the given sound
+func SoundURI(name string) MediaURI {
+	return MediaURI{Scheme: MediaSound, Resource: name}
+}
+
+// RecordingURI returns the media URI of the given stored recording
+func RecordingURI(name string) MediaURI {
+	return MediaURI{Scheme: MediaRecording, Resource: name}
+}
+
+// NumberURI returns the media URI which says the given number
+func NumberURI(n int) MediaURI {
+	return MediaURI{Scheme: MediaNumber, Resource: strconv.Itoa(n)}
+}
+
+// DigitsURI returns the media URI which says the given digits
+func DigitsURI(digits string) MediaURI {
+	return MediaURI{Scheme: MediaDigits, Resource: digits}
+}
+
+// CharactersURI returns the media URI which spells the given characters
+func CharactersURI(chars string) MediaURI {
+	return MediaURI{Scheme: MediaCharacters, Resource: chars}
+}
+
+// ToneOption modifies the media URI of a tone
+type ToneOption func(*MediaURI)
+
+// WithToneZone plays the indication of the tone from the given tone zone (ex. "fr")
+func WithToneZone(zone string) ToneOption {
+	return func(m *MediaURI) {
+		m.ToneZone = zone
+	}
+}
+
+// WithToneDuration sets the duration of each part of a tone pattern which
+// does not have its own.  It does not apply to indications, whose durations
+// are given by their tone zones.
+func WithToneDuration(d time.Duration) ToneOption {
+	return func(m *MediaURI) {
+		if indicationPattern.MatchString(m.Resource) {
+			return
+		}
+
+		parts := strings.Split(m.Resource, ",")
+
+		for i, p := range parts {
+			if !strings.Contains(p, "/") {
+				parts[i] = p + "/" + strconv.FormatInt(d.Milliseconds(), 10)
+			}
+		}
+
+		m.Resource = strings.Join(parts, ",")
+	}
+}
+
+// ToneURI returns the media URI which plays the given tone, either an
+// indication (ex. "ring", "busy") or a tone pattern (ex. "425/500,0/500")
+func ToneURI(tone string, opts ...ToneOption) MediaURI {
+	m := MediaURI{Scheme: MediaTone, Resource: tone}
+
+	for _, o := range opts {
+		o(&m)
+	}
+
+	return m
+}
+
+// HTTPURI returns the media URI which plays the media file at the given HTTP or HTTPS URL
+func HTTPURI(rawURL string) MediaURI {
+	scheme, resource, _ := strings.Cut(rawURL, ":")
+
+	return MediaURI{Scheme: MediaScheme(strings.ToLower(scheme)), Resource: resource}
+}
+
+// ParseMediaURI parses and validates a media URI (ex. "sound:hello-world",
+// "tone:ring;tonezone=fr").  Any error is a *ValidationError.
+func ParseMediaURI(s string) (MediaURI, error) {
+	scheme, resource, ok := strings.Cut(s, ":")
+	if !ok {
+		return MediaURI{}, &ValidationError{Fields: []*FieldError{{Field: "Scheme", Message: "missing"}}}
+	}
+
+	m := MediaURI{Scheme: MediaScheme(scheme), Resource: resource}
+
+	if m.Scheme == MediaTone {
+		params := strings.Split(resource, ";")
+
+		m.Resource = params[0]
+
+		for _, p := range params[1:] {
+			zone, ok := strings.CutPrefix(p, "tonezone=")
+			if !ok {
+				return m, &ValidationError{Fields: []*FieldError{{Field: "Resource", Message: "unknown tone parameter " + p}}}
+			}
+
+			m.ToneZone = zone
+		}
+	}
+
+	return m, m.Validate()
+}
+
+var (
+	numberPattern     = regexp.MustCompile(`^-?[0-9]+$`)
+	digitsPattern     = regexp.MustCompile(`^[0-9*#-]+$`)
+	indicationPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	tonePattern       = regexp.MustCompile(`^!?M?[0-9]+([+*]M?[0-9]+)?(/[0-9]+)?(,!?M?[0-9]+([+*]M?[0-9]+)?(/[0-9]+)?)*$`)
+	toneZonePattern   = regexp.MustCompile(`^[a-z]+(-[a-z]+)?$`)
+)
+
+// Validate checks the media URI against the rules of its scheme, returning
+// a *ValidationError describing any problems.
+func (m MediaURI) Validate() error {
+	v := new(validator)
+
+	schemes := make([]string, len(MediaSchemes))
+	for i, s := range MediaSchemes {
+		schemes[i] = string(s)
+	}
+
+	v.check(m.Scheme != "", "Scheme", "missing")
+	v.oneOf(string(m.Scheme), "Scheme", schemes...)
+	v.check(m.Resource != "", "Resource", "missing")
+	v.check(m.ToneZone == "" || m.Scheme == MediaTone, "ToneZone", "only applies to tones")
+	v.check(m.ToneZone == "" || toneZonePattern.MatchString(m.ToneZone), "ToneZone", "invalid tone zone")
+
+	if m.Resource == "" {
+		return v.err()
+	}
+
+	switch m.Scheme {
+	case MediaSound, MediaRecording:
+		v.check(!strings.ContainsAny(m.Resource, " \t\r\n"), "Resource", "may not contain whitespace")
+	case MediaNumber:
+		v.check(numberPattern.MatchString(m.Resource), "Resource", "must be an integer")
+	case MediaDigits:
+		v.check(digitsPattern.MatchString(m.Resource), "Resource", "must contain only digits, *, #, and -")
+	case MediaCharacters:
+		v.check(printable(m.Resource), "Resource", "must contain only printable ASCII characters")
+	case MediaTone:
+		v.check(indicationPattern.MatchString(m.Resource) || tonePattern.MatchString(m.Resource), "Resource", "must be an indication or a tone pattern")
+	case MediaHTTP, MediaHTTPS:
+		u, err := url.Parse(m.String())
+		v.check(err == nil && u.Host != "", "Resource", "must be a URL with a host")
+	}
+
+	return v.err()
+}
+
+// printable indicates whether the string consists only of printable ASCII characters
+func printable(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MediaURIs validates the given media URIs and returns them as strings,
+// for methods (ex. Channel.Play) which take them so
+func MediaURIs(uris ...MediaURI) ([]string, error) {
+	ret := make([]string, len(uris))
+
+	for i, m := range uris {
+		if err := m.Validate(); err != nil {
+			return nil, eris.Wrapf(err, "invalid media URI %s", m)
+		}
+
+		ret[i] = m.String()
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,80 @@
+package ari
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediaURIString(t *testing.T) {
+	tests := []struct {
+		uri      MediaURI
+		expected string
+	}{
+		{SoundURI("hello-world"), "sound:hello-world"},
+		{RecordingURI("greeting"), "recording:greeting"},
+		{NumberURI(-42), "number:-42"},
+		{DigitsURI("123#"), "digits:123#"},
+		{CharactersURI("ABC"), "characters:ABC"},
+		{ToneURI("ring", WithToneZone("fr")), "tone:ring;tonezone=fr"},
+		{ToneURI("440+480,0/4000", WithToneDuration(2*time.Second)), "tone:440+480/2000,0/4000"},
+		{ToneURI("ring", WithToneDuration(time.Second), WithToneZone("fr")), "tone:ring;tonezone=fr"},
+		{HTTPURI("https://example.com/hello.wav"), "https://example.com/hello.wav"},
+	}
+
+	for _, tc := range tests {
+		if s := tc.uri.String(); s != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, s)
+		}
+
+		checkFieldErrors(t, tc.expected, tc.uri.Validate(), nil)
+
+		parsed, err := ParseMediaURI(tc.expected)
+		if err != nil {
+			t.Errorf("%s: failed to parse: %v", tc.expected, err)
+		}
+
+		if parsed != tc.uri {
+			t.Errorf("%s: expected parse to round-trip, got %+v", tc.expected, parsed)
+		}
+	}
+}
+
+func TestMediaURIValidate(t *testing.T) {
+	tests := []struct {
+		uri    string
+		fields []string
+	}{
+		{"hello-world", []string{"Scheme"}},
+		{"sounds:hello-world", []string{"Scheme"}},
+		{"sound:", []string{"Resource"}},
+		{"sound:hello world", []string{"Resource"}},
+		{"number:forty", []string{"Resource"}},
+		{"digits:12a", []string{"Resource"}},
+		{"characters:café", []string{"Resource"}},
+		{"tone:ring/500", []string{"Resource"}},
+		{"tone:ring;tonezone=FR", []string{"ToneZone"}},
+		{"tone:ring;volume=2", []string{"Resource"}},
+		{"http:/hello.wav", []string{"Resource"}},
+	}
+
+	for _, tc := range tests {
+		_, err := ParseMediaURI(tc.uri)
+		checkFieldErrors(t, tc.uri, err, tc.fields)
+	}
+
+	checkFieldErrors(t, "tonezone on sound", MediaURI{Scheme: MediaSound, Resource: "a", ToneZone: "fr"}.Validate(), []string{"ToneZone"})
+}
+
+func TestMediaURIs(t *testing.T) {
+	list, err := MediaURIs(SoundURI("a"), DigitsURI("12"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(list) != 2 || list[0] != "sound:a" || list[1] != "digits:12" {
+		t.Errorf("unexpected list %v", list)
+	}
+
+	_, err = MediaURIs(SoundURI("a"), NumberURI(1), DigitsURI("x"))
+	checkFieldErrors(t, "invalid", err, []string{"Resource"})
+}
@@ -1,5 +1,11 @@
 package ari
 
+import (
+	"math"
+	"strings"
+	"time"
+)
+
 // OriginateRequest defines the parameters for the creation of a new Asterisk channel
 type OriginateRequest struct {
 	// Endpoint is the name of the Asterisk resource to be used to create the
@@ -89,3 +95,197 @@ type OriginateRequest struct {
 	// Variables describes the set of channel variables to apply to the new channel.  It is optional.
 	Variables map[string]string `json:"variables,omitempty"`
 }
+
+// Validate checks the OriginateRequest against the rules which Asterisk
+// enforces, returning a *ValidationError describing any problems.
+// The native client checks it before sending the request.
+func (req *OriginateRequest) Validate() error {
+	v := new(validator)
+
+	v.check(req.Endpoint != "", "Endpoint", "is required")
+	v.check(req.Endpoint == "" || validEndpoint(req.Endpoint), "Endpoint", "must be of the form tech/resource")
+	v.check(req.Timeout != 0, "Timeout", "is required; use a negative value for no timeout")
+
+	hasCEP := req.Context != "" || req.Extension != "" || req.Priority != 0 || req.Label != ""
+
+	v.check(hasCEP || req.App != "", "App", "exactly one of App or Context/Extension/Priority must be specified")
+	v.check(!hasCEP || req.App == "", "App", "may not be used with Context/Extension/Priority/Label")
+	v.check(req.AppArgs == "" || req.App != "", "AppArgs", "requires App")
+
+	if hasCEP {
+		v.check(req.Context != "", "Context", "is required when originating into the dialplan")
+		v.check(req.Extension != "", "Extension", "is required when originating into the dialplan")
+		v.check(req.Priority > 0 || req.Label != "", "Priority", "one of Priority or Label is required when originating into the dialplan")
+	}
+
+	v.check(req.OtherChannelID == "" || isLocalEndpoint(req.Endpoint), "OtherChannelID", "is only valid for Local channels")
+	v.check(req.Formats == "" || req.Originator == "", "Formats", "may not be used with Originator")
+
+	if req.CallerID != "" {
+		_, err := CallerIDFromString(req.CallerID)
+		v.check(err == nil, "CallerID", "is not a valid caller ID")
+	}
+
+	return v.err()
+}
+
+// Originate is a fluent builder for an OriginateRequest.  Each method
+// returns the builder so that calls may be chained:
+//
+//	req, err := ari.NewOriginate().
+//		ToEndpoint("PJSIP/george").
+//		IntoApp("myapp").
+//		WithTimeout(30 * time.Second).
+//		WithCallerID("Jane", "100").
+//		Request()
+type Originate struct {
+	req OriginateRequest
+}
+
+// NewOriginate returns a new, empty Originate builder
+func NewOriginate() *Originate {
+	return new(Originate)
+}
+
+// ToEndpoint sets the endpoint (tech/resource) to be called
+func (o *Originate) ToEndpoint(endpoint string) *Originate {
+	o.req.Endpoint = endpoint
+	return o
+}
+
+// IntoApp places the new channel into the given ARI application, replacing
+// any dialplan location.  Any arguments are passed to the application.
+func (o *Originate) IntoApp(app string, args ...string) *Originate {
+	o.req.Context = ""
+	o.req.Extension = ""
+	o.req.Priority = 0
+	o.req.Label = ""
+
+	o.req.App = app
+	o.req.AppArgs = strings.Join(args, ",")
+
+	return o
+}
+
+// IntoDialplan places the new channel into the given dialplan location,
+// replacing any ARI application.
+func (o *Originate) IntoDialplan(context, extension string, priority int64) *Originate {
+	o.req.App = ""
+	o.req.AppArgs = ""
+
+	o.req.Context = context
+	o.req.Extension = extension
+	o.req.Priority = priority
+	o.req.Label = ""
+
+	return o
+}
+
+// AtLabel places the new channel at the given label of the dialplan
+// extension (see IntoDialplan).  The label overrides any priority.
+func (o *Originate) AtLabel(label string) *Originate {
+	o.req.Label = label
+	o.req.Priority = 0
+
+	return o
+}
+
+// WithTimeout sets the time to wait for the new channel to be answered.
+// Partial seconds are rounded up.  A negative duration means no timeout.
+func (o *Originate) WithTimeout(timeout time.Duration) *Originate {
+	if timeout < 0 {
+		o.req.Timeout = -1
+		return o
+	}
+
+	o.req.Timeout = int(math.Ceil(timeout.Seconds()))
+
+	return o
+}
+
+// WithoutTimeout waits indefinitely for the new channel to be answered.  Be
+// aware that this could result in an unlimited call.
+func (o *Originate) WithoutTimeout() *Originate {
+	o.req.Timeout = -1
+	return o
+}
+
+// WithCallerID sets the caller ID name and number of the new channel.
+// Either may be empty.
+func (o *Originate) WithCallerID(name, number string) *Originate {
+	o.req.CallerID = (&CallerID{Name: name, Number: number}).String()
+	return o
+}
+
+// WithVariables adds the given channel variables to those set on the new channel
+func (o *Originate) WithVariables(vars map[string]string) *Originate {
+	for k, v := range vars {
+		o.WithVariable(k, v)
+	}
+
+	return o
+}
+
+// WithVariable adds a channel variable to those set on the new channel
+func (o *Originate) WithVariable(name, value string) *Originate {
+	if o.req.Variables == nil {
+		o.req.Variables = make(map[string]string)
+	}
+
+	o.req.Variables[name] = value
+
+	return o
+}
+
+// WithSIPHeader adds a SIP header to the outbound INVITE of the new
+// (PJSIP) channel
+func (o *Originate) WithSIPHeader(name, value string) *Originate {
+	return o.WithVariable("PJSIP_HEADER(add,"+name+")", value)
+}
+
+// WithFormats sets the codecs which are allowed for the new channel.  It may
+// not be combined with WithOriginator.
+func (o *Originate) WithFormats(formats ...string) *Originate {
+	o.req.Formats = strings.Join(formats, ",")
+	return o
+}
+
+// WithOriginator sets the channel on whose behalf the new channel is
+// created, from which Asterisk derives its codecs.
+func (o *Originate) WithOriginator(channelID string) *Originate {
+	o.req.Originator = channelID
+	return o
+}
+
+// WithChannelID sets the unique ID of the new channel
+func (o *Originate) WithChannelID(id string) *Originate {
+	o.req.ChannelID = id
+	return o
+}
+
+// WithOtherChannelID sets the unique ID of the second channel of a Local
+// channel pair
+func (o *Originate) WithOtherChannelID(id string) *Originate {
+	o.req.OtherChannelID = id
+	return o
+}
+
+// Validate validates the request being built (see OriginateRequest.Validate)
+func (o *Originate) Validate() error {
+	return o.req.Validate()
+}
+
+// Request returns the OriginateRequest which has been built, or a
+// *ValidationError if it is not valid.
+func (o *Originate) Request() (OriginateRequest, error) {
+	req := o.req
+
+	if o.req.Variables != nil {
+		req.Variables = make(map[string]string, len(o.req.Variables))
+		for k, v := range o.req.Variables {
+			req.Variables[k] = v
+		}
+	}
+
+	return req, req.Validate()
+}
@@ -0,0 +1,128 @@
+package ari
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOriginateBuilder(t *testing.T) {
+	req, err := NewOriginate().
+		ToEndpoint("PJSIP/george").
+		IntoApp("myapp", "a", "b").
+		WithTimeout(1500*time.Millisecond).
+		WithCallerID("Jane", "100").
+		WithVariables(map[string]string{"FOO": "bar"}).
+		WithSIPHeader("X-Foo", "baz").
+		WithFormats("ulaw", "slin16").
+		Request()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if req.Endpoint != "PJSIP/george" || req.App != "myapp" || req.AppArgs != "a,b" {
+		t.Errorf("Unexpected request: %+v", req)
+	}
+
+	if req.Timeout != 2 {
+		t.Errorf("Expected timeout to be rounded up to 2, got %d", req.Timeout)
+	}
+
+	if req.CallerID != `"Jane" <100>` {
+		t.Errorf("Unexpected caller ID: %s", req.CallerID)
+	}
+
+	if req.Formats != "ulaw,slin16" {
+		t.Errorf("Unexpected formats: %s", req.Formats)
+	}
+
+	if req.Variables["FOO"] != "bar" || req.Variables["PJSIP_HEADER(add,X-Foo)"] != "baz" {
+		t.Errorf("Unexpected variables: %v", req.Variables)
+	}
+}
+
+func TestOriginateBuilderDialplan(t *testing.T) {
+	o := NewOriginate().
+		ToEndpoint("Local/100@internal").
+		IntoApp("myapp").
+		IntoDialplan("internal", "200", 3).
+		AtLabel("start").
+		WithoutTimeout().
+		WithOtherChannelID("other")
+
+	req, err := o.Request()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if req.App != "" || req.Context != "internal" || req.Extension != "200" {
+		t.Errorf("Expected dialplan to replace app: %+v", req)
+	}
+
+	if req.Label != "start" || req.Priority != 0 {
+		t.Errorf("Expected label to override priority: %+v", req)
+	}
+
+	if req.Timeout != -1 {
+		t.Errorf("Expected no timeout, got %d", req.Timeout)
+	}
+}
+
+func TestOriginateRequestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		Name   string
+		Req    OriginateRequest
+		Fields []string
+	}{
+		{"empty", OriginateRequest{}, []string{"Endpoint", "Timeout", "App"}},
+		{"bad endpoint", OriginateRequest{Endpoint: "george", Timeout: 30, App: "a"}, []string{"Endpoint"}},
+		{"app and dialplan", OriginateRequest{Endpoint: "PJSIP/george", Timeout: 30, App: "a", Context: "c", Extension: "e", Priority: 1}, []string{"App"}},
+		{"partial dialplan", OriginateRequest{Endpoint: "PJSIP/george", Timeout: 30, Context: "c"}, []string{"Extension", "Priority"}},
+		{"app args without app", OriginateRequest{Endpoint: "PJSIP/george", Timeout: 30, Context: "c", Extension: "e", Label: "l", AppArgs: "x"}, []string{"AppArgs"}},
+		{"other channel", OriginateRequest{Endpoint: "PJSIP/george", Timeout: 30, App: "a", OtherChannelID: "x"}, []string{"OtherChannelID"}},
+		{"formats and originator", OriginateRequest{Endpoint: "PJSIP/george", Timeout: 30, App: "a", Formats: "ulaw", Originator: "ch1"}, []string{"Formats"}},
+		{"bad caller ID", OriginateRequest{Endpoint: "PJSIP/george", Timeout: 30, App: "a", CallerID: `"Jane <100>`}, []string{"CallerID"}},
+		{"valid", OriginateRequest{Endpoint: "Local/100@internal", Timeout: -1, App: "a", OtherChannelID: "x"}, nil},
+	} {
+		checkFieldErrors(t, tc.Name, tc.Req.Validate(), tc.Fields)
+	}
+}
+
+// checkFieldErrors checks that err is a *ValidationError for exactly the given fields
+func checkFieldErrors(t *testing.T, name string, err error, fields []string) {
+	t.Helper()
+
+	if len(fields) == 0 {
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+
+		return
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("%s: expected *ValidationError, got %v", name, err)
+		return
+	}
+
+	for _, f := range fields {
+		if verr.Field(f) == nil {
+			t.Errorf("%s: expected error for field %s, got %v", name, f, err)
+		}
+	}
+
+	var ferr *FieldError
+	if !errors.As(err, &ferr) {
+		t.Errorf("%s: expected field errors to be unwrappable", name)
+	}
+
+	seen := make(map[string]bool)
+	for _, fe := range verr.Fields {
+		seen[fe.Field] = true
+	}
+
+	if len(seen) != len(fields) {
+		t.Errorf("%s: expected errors for fields %v, got %v", name, fields, err)
+	}
+}
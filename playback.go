@@ -41,11 +41,12 @@ type PlaybackData struct {
 	// Key is the cluster-unique identifier for this playback
 	Key *Key `json:"key"`
 
-	ID        string `json:"id"` // Unique ID for this playback session
-	Language  string `json:"language,omitempty"`
-	MediaURI  string `json:"media_uri"`  // URI for the media which is to be played
-	State     string `json:"state"`      // State of the playback operation
-	TargetURI string `json:"target_uri"` // URI of the channel or bridge on which the media should be played (follows format of 'type':'name')
+	ID           string `json:"id"` // Unique ID for this playback session
+	Language     string `json:"language,omitempty"`
+	MediaURI     string `json:"media_uri"`                // URI for the media which is to be played
+	NextMediaURI string `json:"next_media_uri,omitempty"` // URI for the media which is to be played next, if the playback is of a list of media URIs
+	State        string `json:"state"`                    // State of the playback operation
+	TargetURI    string `json:"target_uri"`               // URI of the channel or bridge on which the media should be played (follows format of 'type':'name')
 }
 
 // PlaybackHandle is the handle for performing playback operations
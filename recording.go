@@ -49,3 +49,16 @@ type RecordingOptions struct {
 	// If not specified, it will default to "none" (never terminate on DTMF).
 	Terminate string
 }
+
+// Validate checks the RecordingOptions against the rules which Asterisk
+// enforces, returning a *ValidationError describing any problems.
+func (opts *RecordingOptions) Validate() error {
+	v := new(validator)
+
+	v.check(opts.MaxDuration >= 0, "MaxDuration", "may not be negative")
+	v.check(opts.MaxSilence >= 0, "MaxSilence", "may not be negative")
+	v.oneOf(opts.Exists, "Exists", "fail", "overwrite", "append")
+	v.oneOf(opts.Terminate, "Terminate", "none", "any", "*", "#")
+
+	return v.err()
+}
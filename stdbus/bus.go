@@ -57,7 +57,7 @@ func (b *bus) Send(e ari.Event) {
 				break
 			}
 
-			if s.key.Match(k) {
+			if matches(s.key, k) {
 				matched = true
 
 				for _, topic := range s.events {
@@ -75,6 +75,17 @@ func (b *bus) Send(e ari.Event) {
 	b.rwMux.RUnlock()
 }
 
+// matches indicates whether an event key matches the key of a subscription.
+// A subscription to a whole dialog (see ari.DialogKey) only receives events
+// which are tagged with that dialog.
+func matches(sub *ari.Key, k *ari.Key) bool {
+	if sub != nil && sub.Dialog != "" && sub.Kind == "" && sub.ID == "" && (k == nil || k.Dialog != sub.Dialog) {
+		return false
+	}
+
+	return sub.Match(k)
+}
+
 // Subscribe returns a subscription to the given list
 // of event types
 func (b *bus) Subscribe(key *ari.Key, eTypes ...string) ari.Subscription {
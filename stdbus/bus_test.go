@@ -174,3 +174,50 @@ L:
 		t.Errorf("Expected 1 event to be sent, got %v", eventCount)
 	}
 }
+
+func TestEventsDialog(t *testing.T) {
+	b := New()
+	defer b.Close()
+
+	dialogSub := b.Subscribe(ari.DialogKey("d1"), ari.Events.All)
+	defer dialogSub.Cancel()
+
+	channelSub := b.Subscribe(ari.NewKey(ari.ChannelKey, "9ae755c1-28a1-11e7-a1b1-0a580a480105", ari.WithDialog("d1")), ari.Events.All)
+	defer channelSub.Cancel()
+
+	received := func(sub ari.Subscription) bool {
+		select {
+		case <-time.After(time.Millisecond):
+			return false
+		case <-sub.Events():
+			return true
+		}
+	}
+
+	// An untagged event reaches the channel, but not the whole dialog
+	b.Send(dtmfTestEvent)
+
+	if received(dialogSub) {
+		t.Error("dialog subscription received an untagged event")
+	}
+
+	if !received(channelSub) {
+		t.Error("channel subscription failed to receive an untagged event")
+	}
+
+	tagged, err := ari.DecodeEvent([]byte(dtmfTestEventData))
+	if err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+
+	tagged.SetDialog("d1")
+	b.Send(tagged)
+
+	if !received(dialogSub) {
+		t.Error("dialog subscription failed to receive a tagged event")
+	}
+
+	if !received(channelSub) {
+		t.Error("channel subscription failed to receive a tagged event")
+	}
+}
@@ -0,0 +1,102 @@
+package ari
+
+import "strings"
+
+// FieldError describes a problem with a single field of a request
+type FieldError struct {
+	// Field is the name of the request field which is invalid
+	Field string
+
+	// Message describes the problem with the field
+	Message string
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError describes the set of problems found by the validation of a
+// request.  Each problem is a *FieldError, which may be extracted with
+// errors.As.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual field errors
+func (e *ValidationError) Unwrap() []error {
+	ret := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		ret[i] = f
+	}
+
+	return ret
+}
+
+// Field returns the error for the given field, if there is one
+func (e *ValidationError) Field(name string) *FieldError {
+	for _, f := range e.Fields {
+		if f.Field == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// validator accumulates field errors
+type validator struct {
+	fields []*FieldError
+}
+
+// check records an error for the field if the condition does not hold
+func (v *validator) check(ok bool, field, msg string) {
+	if !ok {
+		v.fields = append(v.fields, &FieldError{Field: field, Message: msg})
+	}
+}
+
+// oneOf records an error for the field if its value is neither empty nor one of the given values
+func (v *validator) oneOf(value, field string, values ...string) {
+	if value == "" {
+		return
+	}
+
+	for _, x := range values {
+		if value == x {
+			return
+		}
+	}
+
+	v.check(false, field, "must be one of: "+strings.Join(values, ", "))
+}
+
+// err returns the accumulated errors, if there are any
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Fields: v.fields}
+}
+
+// validEndpoint indicates whether the endpoint is of the form tech/resource
+func validEndpoint(endpoint string) bool {
+	tech, resource, ok := strings.Cut(endpoint, "/")
+	return ok && tech != "" && resource != ""
+}
+
+// isLocalEndpoint indicates whether the endpoint describes a Local channel
+func isLocalEndpoint(endpoint string) bool {
+	return strings.HasPrefix(strings.ToLower(endpoint), "local/")
+}
@@ -0,0 +1,37 @@
+package ari
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChannelCreateRequestValidate(t *testing.T) {
+	checkFieldErrors(t, "empty", (&ChannelCreateRequest{}).Validate(), []string{"Endpoint", "App"})
+	checkFieldErrors(t, "other channel", (&ChannelCreateRequest{Endpoint: "PJSIP/george", App: "a", OtherChannelID: "x"}).Validate(), []string{"OtherChannelID"})
+	checkFieldErrors(t, "valid", (&ChannelCreateRequest{Endpoint: "PJSIP/george", App: "a"}).Validate(), nil)
+}
+
+func TestSnoopOptionsValidate(t *testing.T) {
+	checkFieldErrors(t, "empty", (&SnoopOptions{}).Validate(), []string{"App", "Spy"})
+	checkFieldErrors(t, "both none", (&SnoopOptions{App: "a", Spy: DirectionNone, Whisper: DirectionNone}).Validate(), []string{"Spy"})
+	checkFieldErrors(t, "whisper only", (&SnoopOptions{App: "a", Whisper: DirectionOut}).Validate(), nil)
+	checkFieldErrors(t, "bad direction", (&SnoopOptions{App: "a", Spy: "sideways", Whisper: "up"}).Validate(), []string{"Spy", "Whisper"})
+	checkFieldErrors(t, "valid", (&SnoopOptions{App: "a", Spy: DirectionBoth}).Validate(), nil)
+}
+
+func TestExternalMediaOptionsValidate(t *testing.T) {
+	checkFieldErrors(t, "empty", (&ExternalMediaOptions{}).Validate(), []string{"ExternalHost", "Format"})
+	checkFieldErrors(t, "bad host", (&ExternalMediaOptions{ExternalHost: "example.com", Format: "ulaw"}).Validate(), []string{"ExternalHost"})
+	checkFieldErrors(t, "bad values", (&ExternalMediaOptions{ExternalHost: "127.0.0.1:4000", Format: "ulaw", Transport: "sctp", ConnectionType: "peer", Direction: "in"}).Validate(), []string{"Transport", "ConnectionType", "Direction"})
+	checkFieldErrors(t, "audiosocket", (&ExternalMediaOptions{ExternalHost: "127.0.0.1:4000", Format: "slin", Encapsulation: "audiosocket"}).Validate(), []string{"Data"})
+	checkFieldErrors(t, "valid", (&ExternalMediaOptions{ExternalHost: "127.0.0.1:4000", Format: "ulaw"}).Validate(), nil)
+	checkFieldErrors(t, "server", (&ExternalMediaOptions{ConnectionType: "server", Format: "ulaw"}).Validate(), nil)
+	checkFieldErrors(t, "websocket server", (&ExternalMediaOptions{Transport: "websocket", Encapsulation: "none", ConnectionType: "server", Format: "slin16"}).Validate(), nil)
+	checkFieldErrors(t, "websocket client", (&ExternalMediaOptions{ExternalHost: "media_connection1", Transport: "websocket", Encapsulation: "none", Format: "slin16"}).Validate(), nil)
+	checkFieldErrors(t, "websocket client without host", (&ExternalMediaOptions{Transport: "websocket", Encapsulation: "none", Format: "slin16"}).Validate(), []string{"ExternalHost"})
+}
+
+func TestRecordingOptionsValidate(t *testing.T) {
+	checkFieldErrors(t, "bad values", (&RecordingOptions{MaxDuration: -time.Second, Exists: "replace", Terminate: "0"}).Validate(), []string{"MaxDuration", "Exists", "Terminate"})
+	checkFieldErrors(t, "valid", (&RecordingOptions{Exists: "overwrite", Terminate: "#"}).Validate(), nil)
+}